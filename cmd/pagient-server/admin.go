@@ -1,12 +1,20 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
 	"github.com/pagient/pagient-server/internal/config"
 	"github.com/pagient/pagient-server/internal/database"
 	"github.com/pagient/pagient-server/internal/logger"
 	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/notifier"
 	"github.com/pagient/pagient-server/internal/service"
+	"github.com/pagient/pagient-server/internal/ui/renderer"
 
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
@@ -32,6 +40,11 @@ func Admin() *cli.Command {
 				Name:  "client",
 				Usage: "Client ID",
 			},
+			&cli.StringFlag{
+				Name:  "role",
+				Value: string(model.UserRoleUser),
+				Usage: "User role, either admin, user or display",
+			},
 		},
 	}
 
@@ -51,6 +64,22 @@ func Admin() *cli.Command {
 		},
 	}
 
+	subcmdChangeRole := &cli.Command{
+		Name:   "change-role",
+		Usage:  "Change a user's role",
+		Action: cliEnvSetup(runChangeRole),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "username",
+				Usage: "The user to change role for",
+			},
+			&cli.StringFlag{
+				Name:  "role",
+				Usage: "New role to set for user, either admin, user or display",
+			},
+		},
+	}
+
 	subcmdCreateClient := &cli.Command{
 		Name:   "create-client",
 		Usage:  "Create a new client in database",
@@ -79,16 +108,147 @@ func Admin() *cli.Command {
 		},
 	}
 
-	return &cli.Command{
-		Name:  "admin",
-		Usage: "perform admin specific tasks, e.g. create users and clients",
-		Subcommands: []*cli.Command{
-			subcmdCreateUser,
-			subcmdChangePassword,
-			subcmdCreateClient,
-			subcmdCreatePager,
+	subcmdCreateAPIKey := &cli.Command{
+		Name:   "create-api-key",
+		Usage:  "Create a new api key for a user",
+		Action: cliEnvSetup(runCreateAPIKey),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "username",
+				Usage: "The user the api key acts as",
+			},
+			&cli.StringFlag{
+				Name:  "name",
+				Usage: "Name",
+			},
+			&cli.StringSliceFlag{
+				Name:  "scope",
+				Usage: "Scope granted to the api key, either patients:read, patients:create, pagers:call or display",
+			},
+			&cli.DurationFlag{
+				Name:  "expires",
+				Usage: "Duration until the api key expires, never if unset",
+			},
+		},
+	}
+
+	subcmdListAPIKeys := &cli.Command{
+		Name:   "list-api-keys",
+		Usage:  "List the api keys of a user",
+		Action: cliEnvSetup(runListAPIKeys),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "username",
+				Usage: "The user to list api keys for",
+			},
+		},
+	}
+
+	subcmdRevokeAPIKey := &cli.Command{
+		Name:   "revoke-api-key",
+		Usage:  "Revoke an api key",
+		Action: cliEnvSetup(runRevokeAPIKey),
+		Flags: []cli.Flag{
+			&cli.UintFlag{
+				Name:  "id",
+				Usage: "API key ID",
+			},
+		},
+	}
+
+	subcmdPurge := &cli.Command{
+		Name:   "purge",
+		Usage:  "Purge finished patients after their retention period",
+		Action: cliEnvSetup(runPurge),
+		Flags: []cli.Flag{
+			&cli.DurationFlag{
+				Name:  "older-than",
+				Usage: "Purge patients finished longer ago, defaults to the configured retention period",
+			},
+			&cli.StringFlag{
+				Name:  "mode",
+				Usage: "Either delete or anonymize, defaults to the configured mode",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Only list the patients to be purged",
+			},
+		},
+	}
+
+	subcmdReport := &cli.Command{
+		Name:   "report",
+		Usage:  "Report wait times, no-shows and pager utilization of visits",
+		Action: cliEnvSetup(runReport),
+		Flags: []cli.Flag{
+			&cli.DurationFlag{
+				Name:  "period",
+				Value: 30 * 24 * time.Hour,
+				Usage: "Report visits checked in within this period before now",
+			},
+			&cli.StringFlag{
+				Name:  "group",
+				Value: string(model.ReportGroupDay),
+				Usage: "Aggregate visits by either day, hour, room or client",
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Value: "csv",
+				Usage: "Output format, either csv or json",
+			},
 		},
 	}
+
+	subcmdBackup := &cli.Command{
+		Name:   "backup",
+		Usage:  "Write a consistent snapshot of the database, also while the web server is running",
+		Action: cliEnvSetup(runBackup),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "output",
+				Usage: "Backup file, defaults to a timestamped file in the configured backup folder",
+			},
+		},
+	}
+
+	subcmdRestore := &cli.Command{
+		Name:   "restore",
+		Usage:  "Replace the database by a backup, stop the web server first",
+		Action: runRestore,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "input",
+				Usage: "Backup file to restore",
+			},
+		},
+	}
+
+	subcommands := []*cli.Command{
+		subcmdCreateUser,
+		subcmdChangePassword,
+		subcmdChangeRole,
+		subcmdCreateClient,
+		subcmdCreatePager,
+		subcmdCreateAPIKey,
+		subcmdListAPIKeys,
+		subcmdRevokeAPIKey,
+		subcmdPurge,
+		subcmdReport,
+		subcmdBackup,
+		subcmdRestore,
+	}
+	subcommands = append(subcommands, userCommands()...)
+	subcommands = append(subcommands, clientCommands()...)
+	subcommands = append(subcommands, pagerCommands()...)
+	subcommands = append(subcommands, patientCommands()...)
+	subcommands = append(subcommands, tokenCommands()...)
+	subcommands = append(subcommands, inventoryCommands()...)
+
+	return &cli.Command{
+		Name:        "admin",
+		Usage:       "perform admin specific tasks, e.g. manage users and clients",
+		Subcommands: subcommands,
+	}
 }
 
 type commandFunc func(*cli.Context, service.Service, database.DB) error
@@ -121,7 +281,8 @@ func cliEnvSetup(cmdFunc commandFunc) cli.ActionFunc {
 		defer db.Close()
 
 		// Setup Business Layer
-		s := service.NewService(db, nil)
+		// changes get relayed to running web servers through the database
+		s := service.NewService(db, notifier.NewRelay(service.NewService(db, nil), nil))
 
 		return cmdFunc(c, s, db)
 	}
@@ -131,7 +292,11 @@ func runCreateUser(c *cli.Context, s service.Service, db database.DB) error {
 	user := &model.User{
 		Username: c.String("username"),
 		Password: c.String("password"),
-		ClientID: c.Uint("client"),
+		Role:     model.UserRole(c.String("role")),
+	}
+	if c.IsSet("client") {
+		clientID := c.Uint("client")
+		user.ClientID = &clientID
 	}
 
 	err := s.CreateUser(user)
@@ -168,6 +333,31 @@ func runChangePassword(c *cli.Context, s service.Service, db database.DB) error
 	return nil
 }
 
+func runChangeRole(c *cli.Context, s service.Service, db database.DB) error {
+	user := &model.User{
+		Username: c.String("username"),
+		Role:     model.UserRole(c.String("role")),
+	}
+
+	err := s.ChangeUserRole(user)
+	if err != nil && service.IsModelValidationErr(err) {
+		fmt.Printf("User is invalid: %s\n", err.Error())
+		return nil
+	}
+
+	if err != nil && service.IsModelNotExistErr(err) {
+		fmt.Printf("User %s doesn't exist\n", user.Username)
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "change user role failed")
+	}
+
+	fmt.Printf("Role of User %s successfully changed to %s!\n", user.Username, user.Role)
+	return nil
+}
+
 func runCreateClient(c *cli.Context, s service.Service, db database.DB) error {
 	client := &model.Client{
 		Name: c.String("name"),
@@ -206,3 +396,224 @@ func runCreatePager(c *cli.Context, s service.Service, db database.DB) error {
 	fmt.Printf("Pager - ID %d - successfully created!\n", pager.ID)
 	return nil
 }
+
+func runCreateAPIKey(c *cli.Context, s service.Service, db database.DB) error {
+	user, err := s.ShowUser(c.String("username"))
+	if err != nil {
+		return errors.Wrap(err, "get user failed")
+	}
+
+	if user == nil {
+		fmt.Printf("User %s doesn't exist\n", c.String("username"))
+		return nil
+	}
+
+	// scopes may be passed repeatedly or comma separated
+	var scopes []model.APIKeyScope
+	for _, value := range c.StringSlice("scope") {
+		for _, scope := range strings.Split(value, ",") {
+			scopes = append(scopes, model.APIKeyScope(strings.TrimSpace(scope)))
+		}
+	}
+
+	key := &model.APIKey{
+		Name:   c.String("name"),
+		User:   *user,
+		UserID: user.ID,
+	}
+	key.SetScopes(scopes)
+
+	if c.IsSet("expires") {
+		expiresAt := time.Now().Add(c.Duration("expires"))
+		key.ExpiresAt = &expiresAt
+	}
+
+	err = s.CreateAPIKey(key)
+	if err != nil && service.IsModelValidationErr(err) {
+		fmt.Printf("API key is invalid: %s\n", err.Error())
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "create api key failed")
+	}
+
+	fmt.Printf("API key - ID %d - successfully created!\n", key.ID)
+	fmt.Printf("Key: %s\n", key.Key)
+	fmt.Println("Store it safely, it can't be shown again.")
+	return nil
+}
+
+func runListAPIKeys(c *cli.Context, s service.Service, db database.DB) error {
+	keys, err := s.ListAPIKeys(c.String("username"))
+	if err != nil {
+		return errors.Wrap(err, "list api keys failed")
+	}
+
+	for _, key := range keys {
+		lastUsed := "never"
+		if !key.LastUsedAt.IsZero() {
+			lastUsed = key.LastUsedAt.Format(time.RFC3339)
+		}
+
+		expires := "never"
+		if key.ExpiresAt != nil {
+			expires = key.ExpiresAt.Format(time.RFC3339)
+		}
+
+		fmt.Printf("%d\t%s\t%s...\t%s\tlast used: %s\texpires: %s\n", key.ID, key.Name, key.Prefix, key.Scopes, lastUsed, expires)
+	}
+
+	return nil
+}
+
+func runRevokeAPIKey(c *cli.Context, s service.Service, db database.DB) error {
+	key, err := s.ShowAPIKey(c.Uint("id"))
+	if err != nil {
+		return errors.Wrap(err, "get api key failed")
+	}
+
+	if key == nil {
+		fmt.Printf("API key %d doesn't exist\n", c.Uint("id"))
+		return nil
+	}
+
+	if err := s.DeleteAPIKey(key); err != nil {
+		return errors.Wrap(err, "revoke api key failed")
+	}
+
+	fmt.Printf("API key %d successfully revoked!\n", key.ID)
+	return nil
+}
+
+func runPurge(c *cli.Context, s service.Service, db database.DB) error {
+	retention := config.Retention.Patients
+	if c.IsSet("older-than") {
+		retention = c.Duration("older-than")
+	}
+
+	if retention <= 0 {
+		fmt.Println("No retention period configured, set it in the config or pass --older-than")
+		return nil
+	}
+
+	mode := model.PurgeMode(config.Retention.Mode)
+	if c.IsSet("mode") {
+		mode = model.PurgeMode(c.String("mode"))
+	}
+
+	patients, err := s.PurgeFinishedPatients(time.Now().Add(-retention), mode, "admin", c.Bool("dry-run"))
+	if err != nil && service.IsInvalidArgumentErr(err) {
+		fmt.Printf("Purge is invalid: %s\n", err.Error())
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "purge patients failed")
+	}
+
+	for _, patient := range patients {
+		fmt.Printf("%d\t%s\n", patient.ID, patient.Name)
+	}
+
+	if c.Bool("dry-run") {
+		fmt.Printf("%d patients would be purged (%s)\n", len(patients), mode)
+		return nil
+	}
+
+	fmt.Printf("%d patients successfully purged (%s)!\n", len(patients), mode)
+	return nil
+}
+
+func runReport(c *cli.Context, s service.Service, db database.DB) error {
+	format := c.String("format")
+	if format != "csv" && format != "json" {
+		fmt.Printf("Format %q is unknown, use either csv or json\n", format)
+		return nil
+	}
+
+	to := time.Now()
+	rows, err := s.ReportVisits(to.Add(-c.Duration("period")), to, model.ReportGroup(c.String("group")))
+	if err != nil && service.IsInvalidArgumentErr(err) {
+		fmt.Printf("Report is invalid: %s\n", err.Error())
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "report visits failed")
+	}
+
+	if format == "csv" {
+		return renderer.WriteReportCSV(os.Stdout, rows)
+	}
+
+	list := make([]*renderer.ReportRowResponse, len(rows))
+	for i, row := range rows {
+		list[i] = renderer.NewReportRowResponse(row)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+
+	return errors.Wrap(encoder.Encode(list), "encode report failed")
+}
+
+func runBackup(c *cli.Context, s service.Service, db database.DB) error {
+	output := c.String("output")
+	if output == "" {
+		output = database.BackupFile(config.Backup.Path, time.Now())
+	}
+
+	if err := backup(db, output); err != nil {
+		return err
+	}
+
+	fmt.Printf("Database successfully backed up to %s!\n", output)
+	return nil
+}
+
+// backup writes a snapshot of the database to file creating it's folder if missing
+func backup(db database.DB, file string) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return errors.Wrap(err, "create backup folder failed")
+	}
+
+	return errors.Wrap(db.Backup(file), "backup database failed")
+}
+
+// runRestore doesn't open the database, as it's replaced
+func runRestore(c *cli.Context) error {
+	if err := config.Load(); err != nil {
+		log.Fatal().
+			Err(err).
+			Msg("config could not be loaded")
+	}
+
+	config.Log.Pretty = true
+
+	// Setup Logger
+	if err := logger.Init(); err != nil {
+		log.Fatal().
+			Err(err).
+			Msg("logger initialization failed")
+	}
+	defer logger.Close()
+
+	input := c.String("input")
+	if input == "" {
+		fmt.Println("No backup given, pass it by --input")
+		return cli.Exit("", 1)
+	}
+
+	kept, err := database.Restore(input)
+	if err != nil {
+		return errors.Wrap(err, "restore database failed")
+	}
+
+	fmt.Printf("Database successfully restored from %s!\n", input)
+	if kept != "" {
+		fmt.Printf("The replaced database was kept at %s\n", kept)
+	}
+
+	return nil
+}
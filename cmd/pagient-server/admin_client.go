@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/pagient/pagient-server/internal/database"
+	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/service"
+
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v2"
+)
+
+var clientColumns = []string{"id", "name"}
+
+// clientCommands provides the sub-commands to list, show, update and delete clients
+func clientCommands() []*cli.Command {
+	return []*cli.Command{
+		{
+			Name:   "list-clients",
+			Usage:  "List all clients",
+			Action: cliEnvSetup(runListClients),
+			Flags:  []cli.Flag{formatFlag()},
+		},
+		{
+			Name:   "show-client",
+			Usage:  "Show a client",
+			Action: cliEnvSetup(runShowClient),
+			Flags: []cli.Flag{
+				&cli.UintFlag{
+					Name:  "id",
+					Usage: "Client ID",
+				},
+				formatFlag(),
+			},
+		},
+		{
+			Name:   "update-client",
+			Usage:  "Rename a client",
+			Action: cliEnvSetup(runUpdateClient),
+			Flags: []cli.Flag{
+				&cli.UintFlag{
+					Name:  "id",
+					Usage: "Client ID",
+				},
+				&cli.StringFlag{
+					Name:  "name",
+					Usage: "New name",
+				},
+			},
+		},
+		{
+			Name:   "delete-client",
+			Usage:  "Delete a client neither assigned to a user nor having open visits",
+			Action: cliEnvSetup(runDeleteClient),
+			Flags: []cli.Flag{
+				&cli.UintFlag{
+					Name:  "id",
+					Usage: "Client ID",
+				},
+			},
+		},
+	}
+}
+
+func clientRow(client *model.Client) []interface{} {
+	return []interface{}{client.ID, client.Name}
+}
+
+func runListClients(c *cli.Context, s service.Service, db database.DB) error {
+	if err := validFormat(c); err != nil {
+		return err
+	}
+
+	clients, err := s.ListClients()
+	if err != nil {
+		return errors.Wrap(err, "list clients failed")
+	}
+
+	rows := make([][]interface{}, len(clients))
+	for i, client := range clients {
+		rows[i] = clientRow(client)
+	}
+
+	return printList(c, clientColumns, rows)
+}
+
+func runShowClient(c *cli.Context, s service.Service, db database.DB) error {
+	if err := validFormat(c); err != nil {
+		return err
+	}
+
+	client, err := s.ShowClient(c.Uint("id"))
+	if err != nil {
+		return errors.Wrap(err, "show client failed")
+	}
+
+	if client == nil {
+		fmt.Printf("Client %d doesn't exist\n", c.Uint("id"))
+		return nil
+	}
+
+	return printOne(c, clientColumns, clientRow(client))
+}
+
+func runUpdateClient(c *cli.Context, s service.Service, db database.DB) error {
+	client := &model.Client{
+		ID:   c.Uint("id"),
+		Name: c.String("name"),
+	}
+
+	err := s.UpdateClient(client)
+	if err != nil && service.IsModelValidationErr(err) {
+		fmt.Printf("Client is invalid: %s\n", err.Error())
+		return nil
+	}
+
+	if err != nil && service.IsModelNotExistErr(err) {
+		fmt.Printf("Client %d doesn't exist\n", client.ID)
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "update client failed")
+	}
+
+	fmt.Printf("Client %d successfully renamed to %s!\n", client.ID, client.Name)
+	return nil
+}
+
+func runDeleteClient(c *cli.Context, s service.Service, db database.DB) error {
+	client := &model.Client{
+		ID: c.Uint("id"),
+	}
+
+	err := s.DeleteClient(client)
+	if err != nil && service.IsModelNotExistErr(err) {
+		fmt.Printf("Client %d doesn't exist\n", client.ID)
+		return nil
+	}
+
+	if err != nil && service.IsInvalidArgumentErr(err) {
+		fmt.Printf("Client can't be deleted: %s\n", err.Error())
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "delete client failed")
+	}
+
+	fmt.Printf("Client %d successfully deleted!\n", client.ID)
+	return nil
+}
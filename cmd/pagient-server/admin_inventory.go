@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pagient/pagient-server/internal/database"
+	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/service"
+	"github.com/pagient/pagient-server/internal/ui/renderer"
+
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v2"
+)
+
+// inventoryCommands provides the sub-commands to import and export clients, pagers and users
+func inventoryCommands() []*cli.Command {
+	return []*cli.Command{
+		{
+			Name:   "import",
+			Usage:  "Import clients, pagers and users at once, nothing is imported if any row is invalid",
+			Action: cliEnvSetup(runImport),
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "file",
+					Usage: "File to import, - reads from stdin",
+				},
+				&cli.StringFlag{
+					Name:  "format",
+					Usage: "Either csv, json or yaml, defaults to the extension of the file",
+				},
+				&cli.StringFlag{
+					Name:  "kind",
+					Usage: "Import either clients, pagers or users only, required for csv",
+				},
+				&cli.BoolFlag{
+					Name:  "dry-run",
+					Usage: "Only validate the rows to be imported",
+				},
+			},
+		},
+		{
+			Name:   "export",
+			Usage:  "Export clients, pagers and users, passwords are left out and have to be set after import",
+			Action: cliEnvSetup(runExport),
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "file",
+					Usage: "File to export to, defaults to stdout",
+				},
+				&cli.StringFlag{
+					Name:  "format",
+					Usage: "Either csv, json or yaml, defaults to the extension of the file or json",
+				},
+				&cli.StringFlag{
+					Name:  "kind",
+					Usage: "Export either clients, pagers or users only, required for csv",
+				},
+			},
+		},
+	}
+}
+
+// inventoryFormat returns the format of the flag or else of the file extension
+func inventoryFormat(c *cli.Context) string {
+	if c.IsSet("format") {
+		return c.String("format")
+	}
+
+	switch strings.ToLower(filepath.Ext(c.String("file"))) {
+	case ".csv":
+		return renderer.InventoryFormatCSV
+	case ".yaml", ".yml":
+		return renderer.InventoryFormatYAML
+	default:
+		return renderer.InventoryFormatJSON
+	}
+}
+
+func runImport(c *cli.Context, s service.Service, db database.DB) error {
+	format := inventoryFormat(c)
+	kind := model.InventoryKind(c.String("kind"))
+	if err := renderer.ValidInventoryFormat(format, kind); err != nil {
+		fmt.Printf("Import is invalid: %s\n", err.Error())
+		return nil
+	}
+
+	var in io.Reader = os.Stdin
+	if file := c.String("file"); file != "-" {
+		if file == "" {
+			fmt.Println("No file given, pass it by --file or - to read from stdin")
+			return nil
+		}
+
+		f, err := os.Open(file)
+		if err != nil {
+			return errors.Wrap(err, "open import file failed")
+		}
+		defer f.Close()
+
+		in = f
+	}
+
+	inventory, importErrs, err := renderer.ReadInventory(in, format, kind)
+	if err != nil {
+		fmt.Printf("Import is invalid: %s\n", err.Error())
+		return nil
+	}
+
+	if len(importErrs) == 0 {
+		importErrs, err = s.ImportInventory(inventory, c.Bool("dry-run"))
+		if err != nil {
+			return errors.Wrap(err, "import inventory failed")
+		}
+	}
+
+	if len(importErrs) > 0 {
+		for _, importErr := range importErrs {
+			fmt.Println(importErr.Error())
+		}
+
+		fmt.Printf("%d invalid rows, nothing imported\n", len(importErrs))
+		return nil
+	}
+
+	if c.Bool("dry-run") {
+		fmt.Printf("%d clients, %d pagers and %d users would be imported\n", len(inventory.Clients), len(inventory.Pagers), len(inventory.Users))
+		return nil
+	}
+
+	fmt.Printf("%d clients, %d pagers and %d users successfully imported!\n", len(inventory.Clients), len(inventory.Pagers), len(inventory.Users))
+	return nil
+}
+
+func runExport(c *cli.Context, s service.Service, db database.DB) error {
+	format := inventoryFormat(c)
+	kind := model.InventoryKind(c.String("kind"))
+	if err := renderer.ValidInventoryFormat(format, kind); err != nil {
+		fmt.Printf("Export is invalid: %s\n", err.Error())
+		return nil
+	}
+
+	inventory, err := s.ExportInventory()
+	if err != nil {
+		return errors.Wrap(err, "export inventory failed")
+	}
+
+	file := c.String("file")
+	if file == "" || file == "-" {
+		return renderer.WriteInventory(os.Stdout, format, kind, inventory)
+	}
+
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.Wrap(err, "create export file failed")
+	}
+
+	if err := renderer.WriteInventory(f, format, kind, inventory); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return errors.Wrap(err, "close export file failed")
+	}
+
+	fmt.Printf("Inventory successfully exported to %s!\n", file)
+	return nil
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v2"
+)
+
+// formatFlag selects the output of list and show sub-commands
+func formatFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:  "format",
+		Value: "table",
+		Usage: "Output format, either table or json",
+	}
+}
+
+// validFormat reports an unknown output format to the user and exits with failure
+func validFormat(c *cli.Context) error {
+	format := c.String("format")
+	if format != "table" && format != "json" {
+		fmt.Printf("Format %q is unknown, use either table or json\n", format)
+		return cli.Exit("", 1)
+	}
+
+	return nil
+}
+
+// printList prints rows either as aligned columns or as json array of objects keyed by column
+func printList(c *cli.Context, columns []string, rows [][]interface{}) error {
+	if c.String("format") == "json" {
+		list := make([]map[string]interface{}, len(rows))
+		for i, row := range rows {
+			list[i] = object(columns, row)
+		}
+
+		return printJSON(list)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.ToUpper(strings.Join(columns, "\t")))
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, value := range row {
+			cells[i] = cell(value)
+		}
+		fmt.Fprintln(w, strings.Join(cells, "\t"))
+	}
+
+	return errors.Wrap(w.Flush(), "write table failed")
+}
+
+// printOne prints a single row either as column per line or as json object keyed by column
+func printOne(c *cli.Context, columns []string, row []interface{}) error {
+	if c.String("format") == "json" {
+		return printJSON(object(columns, row))
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for i, column := range columns {
+		fmt.Fprintf(w, "%s:\t%s\n", column, cell(row[i]))
+	}
+
+	return errors.Wrap(w.Flush(), "write table failed")
+}
+
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+
+	return errors.Wrap(encoder.Encode(v), "encode json failed")
+}
+
+func object(columns []string, row []interface{}) map[string]interface{} {
+	obj := make(map[string]interface{}, len(columns))
+	for i, column := range columns {
+		obj[column] = row[i]
+	}
+
+	return obj
+}
+
+// cell formats a value for tables, missing values are shown as dash
+func cell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "-"
+	case *uint:
+		if v == nil {
+			return "-"
+		}
+		return fmt.Sprint(*v)
+	case *time.Time:
+		if v == nil {
+			return "-"
+		}
+		return cell(*v)
+	case time.Time:
+		if v.IsZero() {
+			return "-"
+		}
+		return v.Format(time.RFC3339)
+	case string:
+		if v == "" {
+			return "-"
+		}
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
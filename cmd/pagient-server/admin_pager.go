@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/pagient/pagient-server/internal/database"
+	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/service"
+
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v2"
+)
+
+var pagerColumns = []string{"id", "name", "easyCallId"}
+
+// pagerCommands provides the sub-commands to list, show, update and delete pagers
+func pagerCommands() []*cli.Command {
+	return []*cli.Command{
+		{
+			Name:   "list-pagers",
+			Usage:  "List all pagers",
+			Action: cliEnvSetup(runListPagers),
+			Flags:  []cli.Flag{formatFlag()},
+		},
+		{
+			Name:   "show-pager",
+			Usage:  "Show a pager",
+			Action: cliEnvSetup(runShowPager),
+			Flags: []cli.Flag{
+				&cli.UintFlag{
+					Name:  "id",
+					Usage: "Pager ID",
+				},
+				formatFlag(),
+			},
+		},
+		{
+			Name:   "update-pager",
+			Usage:  "Rename a pager or change it's EasyCall ID",
+			Action: cliEnvSetup(runUpdatePager),
+			Flags: []cli.Flag{
+				&cli.UintFlag{
+					Name:  "id",
+					Usage: "Pager ID",
+				},
+				&cli.StringFlag{
+					Name:  "name",
+					Usage: "New name",
+				},
+				&cli.UintFlag{
+					Name:  "easycall-id",
+					Usage: "New EasyCall ID",
+				},
+			},
+		},
+		{
+			Name:   "delete-pager",
+			Usage:  "Delete a pager not assigned to an open visit",
+			Action: cliEnvSetup(runDeletePager),
+			Flags: []cli.Flag{
+				&cli.UintFlag{
+					Name:  "id",
+					Usage: "Pager ID",
+				},
+			},
+		},
+	}
+}
+
+func pagerRow(pager *model.Pager) []interface{} {
+	return []interface{}{pager.ID, pager.Name, pager.EasyCallID}
+}
+
+func runListPagers(c *cli.Context, s service.Service, db database.DB) error {
+	if err := validFormat(c); err != nil {
+		return err
+	}
+
+	pagers, err := s.ListPagers()
+	if err != nil {
+		return errors.Wrap(err, "list pagers failed")
+	}
+
+	rows := make([][]interface{}, len(pagers))
+	for i, pager := range pagers {
+		rows[i] = pagerRow(pager)
+	}
+
+	return printList(c, pagerColumns, rows)
+}
+
+func runShowPager(c *cli.Context, s service.Service, db database.DB) error {
+	if err := validFormat(c); err != nil {
+		return err
+	}
+
+	pager, err := s.ShowPager(c.Uint("id"))
+	if err != nil {
+		return errors.Wrap(err, "show pager failed")
+	}
+
+	if pager == nil {
+		fmt.Printf("Pager %d doesn't exist\n", c.Uint("id"))
+		return nil
+	}
+
+	return printOne(c, pagerColumns, pagerRow(pager))
+}
+
+func runUpdatePager(c *cli.Context, s service.Service, db database.DB) error {
+	pager, err := s.ShowPager(c.Uint("id"))
+	if err != nil {
+		return errors.Wrap(err, "show pager failed")
+	}
+
+	if pager == nil {
+		fmt.Printf("Pager %d doesn't exist\n", c.Uint("id"))
+		return nil
+	}
+
+	if c.IsSet("name") {
+		pager.Name = c.String("name")
+	}
+	if c.IsSet("easycall-id") {
+		pager.EasyCallID = c.Uint("easycall-id")
+	}
+
+	err = s.UpdatePager(pager)
+	if err != nil && service.IsModelValidationErr(err) {
+		fmt.Printf("Pager is invalid: %s\n", err.Error())
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "update pager failed")
+	}
+
+	fmt.Printf("Pager %d successfully updated!\n", pager.ID)
+	return nil
+}
+
+func runDeletePager(c *cli.Context, s service.Service, db database.DB) error {
+	pager := &model.Pager{
+		ID: c.Uint("id"),
+	}
+
+	err := s.DeletePager(pager)
+	if err != nil && service.IsModelNotExistErr(err) {
+		fmt.Printf("Pager %d doesn't exist\n", pager.ID)
+		return nil
+	}
+
+	if err != nil && service.IsInvalidArgumentErr(err) {
+		fmt.Printf("Pager can't be deleted: %s\n", err.Error())
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "delete pager failed")
+	}
+
+	fmt.Printf("Pager %d successfully deleted!\n", pager.ID)
+	return nil
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/pagient/pagient-server/internal/database"
+	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/service"
+	"github.com/pagient/pagient-server/internal/ui/renderer"
+
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v2"
+)
+
+var patientColumns = []string{"id", "ssn", "name", "anonymizedAt"}
+
+// patientCommands provides the sub-commands to list, show, update and delete patients
+func patientCommands() []*cli.Command {
+	unmaskFlag := func() cli.Flag {
+		return &cli.BoolFlag{
+			Name:  "unmask",
+			Usage: "Show social security numbers in full",
+		}
+	}
+
+	return []*cli.Command{
+		{
+			Name:   "list-patients",
+			Usage:  "List all patients",
+			Action: cliEnvSetup(runListPatients),
+			Flags:  []cli.Flag{formatFlag(), unmaskFlag()},
+		},
+		{
+			Name:   "show-patient",
+			Usage:  "Show a patient",
+			Action: cliEnvSetup(runShowPatient),
+			Flags: []cli.Flag{
+				&cli.UintFlag{
+					Name:  "id",
+					Usage: "Patient ID",
+				},
+				formatFlag(),
+				unmaskFlag(),
+			},
+		},
+		{
+			Name:   "update-patient",
+			Usage:  "Change a patient's name or social security number",
+			Action: cliEnvSetup(runUpdatePatient),
+			Flags: []cli.Flag{
+				&cli.UintFlag{
+					Name:  "id",
+					Usage: "Patient ID",
+				},
+				&cli.StringFlag{
+					Name:  "name",
+					Usage: "New name",
+				},
+				&cli.StringFlag{
+					Name:  "ssn",
+					Usage: "New social security number",
+				},
+			},
+		},
+		{
+			Name:   "delete-patient",
+			Usage:  "Delete a patient and all of it's visits",
+			Action: cliEnvSetup(runDeletePatient),
+			Flags: []cli.Flag{
+				&cli.UintFlag{
+					Name:  "id",
+					Usage: "Patient ID",
+				},
+			},
+		},
+	}
+}
+
+// patientRow masks the social security number unless asked otherwise, as it's personal data
+func patientRow(c *cli.Context, patient *model.Patient) []interface{} {
+	ssn := patient.SocialSecurityNo
+	if !c.Bool("unmask") {
+		ssn = renderer.MaskSocialSecurityNo(ssn)
+	}
+
+	return []interface{}{patient.ID, ssn, patient.Name, patient.AnonymizedAt}
+}
+
+func runListPatients(c *cli.Context, s service.Service, db database.DB) error {
+	if err := validFormat(c); err != nil {
+		return err
+	}
+
+	patients, err := s.ListPatients()
+	if err != nil {
+		return errors.Wrap(err, "list patients failed")
+	}
+
+	rows := make([][]interface{}, len(patients))
+	for i, patient := range patients {
+		rows[i] = patientRow(c, patient)
+	}
+
+	return printList(c, patientColumns, rows)
+}
+
+func runShowPatient(c *cli.Context, s service.Service, db database.DB) error {
+	if err := validFormat(c); err != nil {
+		return err
+	}
+
+	patient, err := s.ShowPatient(c.Uint("id"))
+	if err != nil {
+		return errors.Wrap(err, "show patient failed")
+	}
+
+	if patient == nil {
+		fmt.Printf("Patient %d doesn't exist\n", c.Uint("id"))
+		return nil
+	}
+
+	return printOne(c, patientColumns, patientRow(c, patient))
+}
+
+func runUpdatePatient(c *cli.Context, s service.Service, db database.DB) error {
+	patient, err := s.ShowPatient(c.Uint("id"))
+	if err != nil {
+		return errors.Wrap(err, "show patient failed")
+	}
+
+	if patient == nil {
+		fmt.Printf("Patient %d doesn't exist\n", c.Uint("id"))
+		return nil
+	}
+
+	if c.IsSet("name") {
+		patient.Name = c.String("name")
+	}
+	if c.IsSet("ssn") {
+		patient.SocialSecurityNo = c.String("ssn")
+	}
+
+	err = s.UpdatePatient(patient)
+	if err != nil && (service.IsModelValidationErr(err) || service.IsModelExistErr(err)) {
+		fmt.Printf("Patient is invalid: %s\n", err.Error())
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "update patient failed")
+	}
+
+	fmt.Printf("Patient %d successfully updated!\n", patient.ID)
+	return nil
+}
+
+func runDeletePatient(c *cli.Context, s service.Service, db database.DB) error {
+	patient := &model.Patient{
+		ID: c.Uint("id"),
+	}
+
+	err := s.DeletePatient(patient)
+	if err != nil && service.IsModelNotExistErr(err) {
+		fmt.Printf("Patient %d doesn't exist\n", patient.ID)
+		return nil
+	}
+
+	if err != nil && service.IsInvalidArgumentErr(err) {
+		fmt.Println("Patient can't be deleted while a pager is assigned to it's visit")
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "delete patient failed")
+	}
+
+	fmt.Printf("Patient %d successfully deleted!\n", patient.ID)
+	return nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/pagient/pagient-server/internal/database"
+	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/service"
+
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v2"
+)
+
+var tokenColumns = []string{"id", "session", "createdAt", "lastSeenAt", "expiresAt", "refreshExpiresAt", "ip", "userAgent"}
+
+// tokenCommands provides the sub-commands to list, show and delete the active tokens of users,
+// tokens can't be updated as they are replaced on refresh
+func tokenCommands() []*cli.Command {
+	return []*cli.Command{
+		{
+			Name:   "list-tokens",
+			Usage:  "List the active tokens of a user",
+			Action: cliEnvSetup(runListTokens),
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "username",
+					Usage: "The user to list tokens for",
+				},
+				formatFlag(),
+			},
+		},
+		{
+			Name:   "show-token",
+			Usage:  "Show a token",
+			Action: cliEnvSetup(runShowToken),
+			Flags: []cli.Flag{
+				&cli.UintFlag{
+					Name:  "id",
+					Usage: "Token ID",
+				},
+				formatFlag(),
+			},
+		},
+		{
+			Name:   "delete-token",
+			Usage:  "Delete a token and end it's session",
+			Action: cliEnvSetup(runDeleteToken),
+			Flags: []cli.Flag{
+				&cli.UintFlag{
+					Name:  "id",
+					Usage: "Token ID",
+				},
+			},
+		},
+	}
+}
+
+func tokenRow(token *model.Token) []interface{} {
+	return []interface{}{token.ID, token.Session(), token.CreatedAt, token.LastSeenAt, token.ExpiresAt, token.RefreshExpiresAt, token.IP, token.UserAgent}
+}
+
+func runListTokens(c *cli.Context, s service.Service, db database.DB) error {
+	if err := validFormat(c); err != nil {
+		return err
+	}
+
+	tokens, err := s.ListTokensByUser(c.String("username"))
+	if err != nil {
+		return errors.Wrap(err, "list tokens failed")
+	}
+
+	rows := make([][]interface{}, len(tokens))
+	for i, token := range tokens {
+		rows[i] = tokenRow(token)
+	}
+
+	return printList(c, tokenColumns, rows)
+}
+
+func runShowToken(c *cli.Context, s service.Service, db database.DB) error {
+	if err := validFormat(c); err != nil {
+		return err
+	}
+
+	token, err := s.ShowTokenByID(c.Uint("id"))
+	if err != nil {
+		return errors.Wrap(err, "show token failed")
+	}
+
+	if token == nil {
+		fmt.Printf("Token %d doesn't exist\n", c.Uint("id"))
+		return nil
+	}
+
+	return printOne(c, tokenColumns, tokenRow(token))
+}
+
+func runDeleteToken(c *cli.Context, s service.Service, db database.DB) error {
+	// the token is loaded to end it's whole session
+	token, err := s.ShowTokenByID(c.Uint("id"))
+	if err != nil {
+		return errors.Wrap(err, "show token failed")
+	}
+
+	if token == nil {
+		fmt.Printf("Token %d doesn't exist\n", c.Uint("id"))
+		return nil
+	}
+
+	err = s.DeleteToken(token)
+	if err != nil && service.IsModelNotExistErr(err) {
+		fmt.Printf("Token %d doesn't exist\n", token.ID)
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "delete token failed")
+	}
+
+	fmt.Printf("Token %d successfully deleted!\n", token.ID)
+	return nil
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/pagient/pagient-server/internal/database"
+	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/service"
+
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v2"
+)
+
+var userColumns = []string{"id", "username", "role", "clientId", "origin", "lockedUntil"}
+
+// userCommands provides the sub-commands to list, show, update and delete users
+func userCommands() []*cli.Command {
+	return []*cli.Command{
+		{
+			Name:   "list-users",
+			Usage:  "List all users",
+			Action: cliEnvSetup(runListUsers),
+			Flags:  []cli.Flag{formatFlag()},
+		},
+		{
+			Name:   "show-user",
+			Usage:  "Show a user",
+			Action: cliEnvSetup(runShowUser),
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "username",
+					Usage: "The user to show",
+				},
+				formatFlag(),
+			},
+		},
+		{
+			Name:   "update-user",
+			Usage:  "Change a user's role or client",
+			Action: cliEnvSetup(runUpdateUser),
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "username",
+					Usage: "The user to update",
+				},
+				&cli.StringFlag{
+					Name:  "role",
+					Usage: "New role, either admin, user or display",
+				},
+				&cli.UintFlag{
+					Name:  "client",
+					Usage: "New client ID",
+				},
+				&cli.BoolFlag{
+					Name:  "no-client",
+					Usage: "Remove the client of the user",
+				},
+			},
+		},
+		{
+			Name:   "link-user",
+			Usage:  "Link a user to it's subject at the openid connect provider, so it can login by single sign-on",
+			Action: cliEnvSetup(runLinkUser),
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "username",
+					Usage: "The user to link",
+				},
+				&cli.StringFlag{
+					Name:  "subject",
+					Usage: "Subject of the user at the openid connect provider",
+				},
+			},
+		},
+		{
+			Name:   "delete-user",
+			Usage:  "Delete a user and end all of it's sessions",
+			Action: cliEnvSetup(runDeleteUser),
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "username",
+					Usage: "The user to delete",
+				},
+			},
+		},
+	}
+}
+
+func userRow(user *model.User) []interface{} {
+	return []interface{}{user.ID, user.Username, user.Role, user.ClientID, user.Origin, user.LockedUntil}
+}
+
+func runListUsers(c *cli.Context, s service.Service, db database.DB) error {
+	if err := validFormat(c); err != nil {
+		return err
+	}
+
+	users, err := s.ListUsers()
+	if err != nil {
+		return errors.Wrap(err, "list users failed")
+	}
+
+	rows := make([][]interface{}, len(users))
+	for i, user := range users {
+		rows[i] = userRow(user)
+	}
+
+	return printList(c, userColumns, rows)
+}
+
+func runShowUser(c *cli.Context, s service.Service, db database.DB) error {
+	if err := validFormat(c); err != nil {
+		return err
+	}
+
+	user, err := s.ShowUser(c.String("username"))
+	if err != nil {
+		return errors.Wrap(err, "show user failed")
+	}
+
+	if user == nil {
+		fmt.Printf("User %s doesn't exist\n", c.String("username"))
+		return nil
+	}
+
+	return printOne(c, userColumns, userRow(user))
+}
+
+func runUpdateUser(c *cli.Context, s service.Service, db database.DB) error {
+	user, err := s.ShowUser(c.String("username"))
+	if err != nil {
+		return errors.Wrap(err, "show user failed")
+	}
+
+	if user == nil {
+		fmt.Printf("User %s doesn't exist\n", c.String("username"))
+		return nil
+	}
+
+	if c.IsSet("role") {
+		user.Role = model.UserRole(c.String("role"))
+	}
+
+	switch {
+	case c.Bool("no-client"):
+		user.ClientID = nil
+	case c.IsSet("client"):
+		clientID := c.Uint("client")
+		user.ClientID = &clientID
+	}
+
+	err = s.UpdateUser(user)
+	if err != nil && service.IsModelValidationErr(err) {
+		fmt.Printf("User is invalid: %s\n", err.Error())
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "update user failed")
+	}
+
+	fmt.Printf("User %s successfully updated!\n", user.Username)
+	return nil
+}
+
+func runLinkUser(c *cli.Context, s service.Service, db database.DB) error {
+	subject := c.String("subject")
+	user := &model.User{
+		Username: c.String("username"),
+		Subject:  &subject,
+	}
+
+	err := s.LinkUser(user)
+	if err != nil && service.IsModelNotExistErr(err) {
+		fmt.Printf("User %s doesn't exist\n", user.Username)
+		return nil
+	}
+
+	if err != nil && (service.IsModelValidationErr(err) || service.IsModelExistErr(err)) {
+		fmt.Printf("User is invalid: %s\n", err.Error())
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "link user failed")
+	}
+
+	fmt.Printf("User %s successfully linked!\n", user.Username)
+	return nil
+}
+
+func runDeleteUser(c *cli.Context, s service.Service, db database.DB) error {
+	user := &model.User{
+		Username: c.String("username"),
+	}
+
+	err := s.DeleteUser(user)
+	if err != nil && service.IsModelNotExistErr(err) {
+		fmt.Printf("User %s doesn't exist\n", user.Username)
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "delete user failed")
+	}
+
+	fmt.Printf("User %s successfully deleted!\n", user.Username)
+	return nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pagient/pagient-server/internal/config"
+
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v2"
+)
+
+// Config provides the sub-command to check and print the configuration
+func Config() *cli.Command {
+	subcmdCheck := &cli.Command{
+		Name:   "check",
+		Usage:  "Validate the config and report all problems at once",
+		Action: runConfigCheck,
+	}
+
+	subcmdPrint := &cli.Command{
+		Name:   "print",
+		Usage:  "Print the effective config including overrides, secrets are redacted",
+		Action: runConfigPrint,
+	}
+
+	return &cli.Command{
+		Name:  "config",
+		Usage: "check and print the configuration",
+		Subcommands: []*cli.Command{
+			subcmdCheck,
+			subcmdPrint,
+		},
+	}
+}
+
+func runConfigCheck(c *cli.Context) error {
+	if err := config.Read(); err != nil {
+		printConfigErr(err)
+		return cli.Exit("", 1)
+	}
+
+	fmt.Printf("Config %s is valid!\n", config.Path)
+	return nil
+}
+
+func runConfigPrint(c *cli.Context) error {
+	// invalid configs are printed as well, so their problems can be located
+	err := config.Read()
+	if _, invalid := errors.Cause(err).(*config.ValidationError); err != nil && !invalid {
+		printConfigErr(err)
+		return cli.Exit("", 1)
+	}
+
+	if err := config.Print(os.Stdout); err != nil {
+		return errors.Wrap(err, "print config failed")
+	}
+
+	if err != nil {
+		printConfigErr(err)
+		return cli.Exit("", 1)
+	}
+
+	return nil
+}
+
+// printConfigErr prints all problems of an invalid config or why it couldn't be read
+func printConfigErr(err error) {
+	validationErr, ok := errors.Cause(err).(*config.ValidationError)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Config %s could not be read: %s\n", config.Path, err.Error())
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "Config %s is invalid:\n", config.Path)
+	for _, problem := range validationErr.Problems {
+		fmt.Fprintf(os.Stderr, "  %s\n", problem)
+	}
+}
@@ -29,17 +29,24 @@ func main() {
 				Name:        "config",
 				Value:       "/conf/app.ini",
 				Usage:       "set config path",
+				EnvVars:     []string{"PAGIENT_CONFIG"},
 				Destination: &config.Path,
 			},
+			&cli.StringSliceFlag{
+				Name:  "set",
+				Usage: "override a config value formatted section.KEY=value, e.g. server.ADDRESS=:8080, takes precedence over PAGIENT_<SECTION>_<KEY> environment variables and the config file",
+			},
 		},
 
 		Before: func(c *cli.Context) error {
+			config.Overrides = c.StringSlice("set")
 			return nil
 		},
 
 		Commands: []*cli.Command{
 			Web(),
 			Admin(),
+			Config(),
 		},
 	}
 
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/pagient/pagient-server/internal/caller"
+	"github.com/pagient/pagient-server/internal/config"
+	"github.com/pagient/pagient-server/internal/logger"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/rs/zerolog/log"
+)
+
+// reloadDelay collects the writes of an editor saving the config file into a single reload
+const reloadDelay = 500 * time.Millisecond
+
+// watchConfig reloads the config on SIGHUP and on changes of the config file until stop is closed
+func watchConfig(c *caller.Caller, stop <-chan struct{}) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	// editors replace the file on save, so the folder of it is watched
+	var events <-chan fsnotify.Event
+	var errs <-chan error
+
+	watcher, err := fsnotify.NewWatcher()
+	if err == nil {
+		defer watcher.Close()
+		err = watcher.Add(filepath.Dir(config.Path))
+	}
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Msg("watch config file failed, reload by SIGHUP only")
+	} else {
+		events, errs = watcher.Events, watcher.Errors
+	}
+
+	delay := time.NewTimer(reloadDelay)
+	delay.Stop()
+
+	for {
+		select {
+		case <-hup:
+			reloadConfig(c)
+		case event := <-events:
+			if filepath.Clean(event.Name) == filepath.Clean(config.Path) && event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
+				delay.Reset(reloadDelay)
+			}
+		case <-delay.C:
+			reloadConfig(c)
+		case err := <-errs:
+			log.Warn().
+				Err(err).
+				Msg("watch config file failed")
+		case <-stop:
+			delay.Stop()
+			return
+		}
+	}
+}
+
+// reloadConfig applies the reloadable settings to the logger and the caller,
+// the pager gateway looks them up on every call
+func reloadConfig(c *caller.Caller) {
+	changes, err := config.Reload()
+	if err != nil {
+		log.Error().
+			Err(err).
+			Msg("config reload refused, running config is kept")
+
+		return
+	}
+
+	var applied, pending []string
+	for _, change := range changes {
+		if change.Applied {
+			applied = append(applied, change.String())
+		} else {
+			pending = append(pending, change.String())
+		}
+	}
+
+	if err := logger.SetLevel(config.LogLevel()); err != nil {
+		log.Error().
+			Err(err).
+			Msg("set log level failed")
+	}
+	c.SetInterval(config.PollingInterval())
+
+	log.Info().
+		Strs("changes", applied).
+		Msg("config reloaded")
+
+	if len(pending) > 0 {
+		log.Warn().
+			Strs("changes", pending).
+			Msg("config changes take effect on restart only")
+	}
+}
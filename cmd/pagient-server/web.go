@@ -15,16 +15,30 @@ import (
 	"github.com/pagient/pagient-server/internal/caller"
 	"github.com/pagient/pagient-server/internal/config"
 	"github.com/pagient/pagient-server/internal/database"
+	"github.com/pagient/pagient-server/internal/directory"
+	"github.com/pagient/pagient-server/internal/health"
 	"github.com/pagient/pagient-server/internal/logger"
+	"github.com/pagient/pagient-server/internal/metrics"
+	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/notifier"
 	"github.com/pagient/pagient-server/internal/service"
+	"github.com/pagient/pagient-server/internal/tracing"
 	"github.com/pagient/pagient-server/internal/ui/router"
 	"github.com/pagient/pagient-server/internal/ui/websocket"
 
 	"github.com/oklog/run"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rs/zerolog/log"
 	"gopkg.in/urfave/cli.v2"
 )
 
+const (
+	// readinessTimeout limits the check of a single dependency on readiness probes
+	readinessTimeout = 3 * time.Second
+	// callerMaxMissedPolls is the count of polling intervals without a successful poll until the caller isn't ready
+	callerMaxMissedPolls = 3
+)
+
 // Web provides the sub-command to start the server.
 func Web() *cli.Command {
 	return &cli.Command{
@@ -54,6 +68,16 @@ func Web() *cli.Command {
 			}
 			defer logger.Close()
 
+			// Setup Tracing
+			if err := tracing.Init(); err != nil {
+				log.Fatal().
+					Err(err).
+					Msg("tracing initialization failed")
+
+				os.Exit(1)
+			}
+			defer tracing.Close()
+
 			// Setup Database Connection
 			db, err := database.Open()
 			if err != nil {
@@ -65,11 +89,41 @@ func Web() *cli.Command {
 			}
 			defer db.Close()
 
-			// Initialize notifier (websocket hub)
-			hub := websocket.NewHub()
+			// Initialize notifiers (websocket hub, server-sent events, webhooks, ...)
+			hub := websocket.NewHub(config.Notifier.QueueSize)
+			events := notifier.NewSSE(config.Notifier.QueueSize)
+
+			sinks := []service.UINotifier{hub, events}
+			for _, url := range config.Notifier.Webhooks {
+				sinks = append(sinks, notifier.NewWebhook(url, config.Notifier.WebhookTimeout, config.Notifier.WebhookReveal))
+			}
+			if config.Notifier.Log {
+				sinks = append(sinks, notifier.NewLogger())
+			}
+
+			// Changes of other processes (e.g. admin commands) are relayed through the database
+			local := notifier.NewFanout(config.Notifier.QueueSize, sinks...)
+			relay := notifier.NewRelay(service.NewService(db, nil), local)
+			fanout := notifier.NewFanout(config.Notifier.QueueSize, append(sinks, relay)...)
+
+			var authenticators []service.Authenticator
+			if config.LDAP.Enabled {
+				authenticators = append(authenticators, directory.NewLDAP())
+			}
 
 			// Setup Business Layer
-			s := service.NewService(db, hub)
+			s := service.NewService(db, fanout, authenticators...)
+
+			// Expose the open visits per status to prometheus
+			prometheus.MustRegister(metrics.NewVisitCollector(s))
+
+			// Check dependencies on readiness probes
+			checker := health.NewChecker(readinessTimeout)
+			checker.Add("database", db.Ping)
+			checker.Add("pager_gateway", health.HTTPCheck(func() string {
+				url, _, _, _ := config.PagerGateway()
+				return url
+			}, readinessTimeout))
 
 			var gr run.Group
 
@@ -102,6 +156,138 @@ func Web() *cli.Command {
 				})
 			}
 
+			{
+				stop := make(chan struct{}, 1)
+
+				gr.Add(func() error {
+					log.Info().
+						Msg("starting notifiers")
+
+					local.Run(stop)
+					fanout.Run(stop)
+
+					return relay.Run(config.Notifier.RelayInterval, stop)
+				}, func(reason error) {
+					close(stop)
+				})
+			}
+
+			{
+				stop := make(chan struct{}, 1)
+
+				gr.Add(func() error {
+					log.Info().
+						Msg("starting token cleanup")
+
+					ticker := time.NewTicker(config.Auth.TokenCleanupInterval)
+					defer ticker.Stop()
+
+					for {
+						select {
+						case <-ticker.C:
+							if err := s.DeleteExpiredTokens(); err != nil {
+								log.Error().
+									Err(err).
+									Msg("remove expired tokens failed")
+							}
+						case <-stop:
+							return nil
+						}
+					}
+				}, func(reason error) {
+					close(stop)
+				})
+			}
+
+			if config.Retention.Patients > 0 {
+				stop := make(chan struct{}, 1)
+
+				gr.Add(func() error {
+					log.Info().
+						Dur("retention", config.Retention.Patients).
+						Msg("starting patient janitor")
+
+					ticker := time.NewTicker(config.Retention.Interval)
+					defer ticker.Stop()
+
+					for {
+						select {
+						case <-ticker.C:
+							patients, err := s.PurgeFinishedPatients(time.Now().Add(-config.Retention.Patients), model.PurgeMode(config.Retention.Mode), "janitor", false)
+							if err != nil {
+								log.Error().
+									Err(err).
+									Msg("purge finished patients failed")
+
+								continue
+							}
+
+							if len(patients) > 0 {
+								log.Info().
+									Int("count", len(patients)).
+									Str("mode", config.Retention.Mode).
+									Msg("finished patients purged")
+							}
+						case <-stop:
+							return nil
+						}
+					}
+				}, func(reason error) {
+					close(stop)
+				})
+			}
+
+			if config.Backup.Enabled {
+				stop := make(chan struct{}, 1)
+
+				gr.Add(func() error {
+					log.Info().
+						Dur("interval", config.Backup.Interval).
+						Str("path", config.Backup.Path).
+						Msg("starting scheduled backups")
+
+					ticker := time.NewTicker(config.Backup.Interval)
+					defer ticker.Stop()
+
+					for {
+						select {
+						case <-ticker.C:
+							file := database.BackupFile(config.Backup.Path, time.Now())
+							if err := backup(db, file); err != nil {
+								log.Error().
+									Err(err).
+									Msg("scheduled backup failed")
+
+								continue
+							}
+
+							log.Info().
+								Str("file", file).
+								Msg("database backed up")
+
+							pruned, err := database.PruneBackups(config.Backup.Path, config.Backup.Keep)
+							if err != nil {
+								log.Error().
+									Err(err).
+									Msg("prune backups failed")
+
+								continue
+							}
+
+							if len(pruned) > 0 {
+								log.Info().
+									Int("count", len(pruned)).
+									Msg("old backups pruned")
+							}
+						case <-stop:
+							return nil
+						}
+					}
+				}, func(reason error) {
+					close(stop)
+				})
+			}
+
 			{
 				// Setup Bridge Database Connection
 				db, err := bridgeDB.Open()
@@ -121,12 +307,17 @@ func Web() *cli.Command {
 				c := caller.NewCaller(s, b)
 				stop := make(chan struct{}, 1)
 
+				checker.Add("bridge_database", db.Ping)
+				// a few polls may fail in a row before paging counts as stopped
+				checker.Add("caller", health.FreshnessCheck(c.LastPoll, func() time.Duration {
+					return callerMaxMissedPolls * c.Interval()
+				}))
+
 				gr.Add(func() error {
 					log.Info().
 						Msg("starting caller")
 
-					every := time.Duration(config.Bridge.PollingInterval) * time.Second
-					return c.Run(every, stop)
+					return c.Run(config.PollingInterval(), stop)
 				}, func(reason error) {
 					close(stop)
 
@@ -134,6 +325,20 @@ func Web() *cli.Command {
 						AnErr("reason", reason).
 						Msg("caller stopped gracefully")
 				})
+
+				reloadStop := make(chan struct{}, 1)
+
+				gr.Add(func() error {
+					log.Info().
+						Str("path", config.Path).
+						Msg("starting config watcher")
+
+					watchConfig(c, reloadStop)
+
+					return nil
+				}, func(reason error) {
+					close(reloadStop)
+				})
 			}
 
 			if config.Server.Cert != "" && config.Server.Key != "" {
@@ -153,7 +358,7 @@ func Web() *cli.Command {
 				{
 					server := &http.Server{
 						Addr:         config.Server.Address,
-						Handler:      router.Load(s, hub),
+						Handler:      router.Load(s, hub, events, checker),
 						ReadTimeout:  5 * time.Second,
 						WriteTimeout: 10 * time.Second,
 						TLSConfig: &tls.Config{
@@ -195,7 +400,7 @@ func Web() *cli.Command {
 			{
 				server := &http.Server{
 					Addr:         config.Server.Address,
-					Handler:      router.Load(s, hub),
+					Handler:      router.Load(s, hub, events, checker),
 					ReadTimeout:  5 * time.Second,
 					WriteTimeout: 10 * time.Second,
 				}
@@ -1,6 +1,7 @@
 package bridge
 
 import (
+	"context"
 	"sort"
 
 	bridgeModel "github.com/pagient/pagient-server/internal/bridge/model"
@@ -12,7 +13,8 @@ import (
 
 // DB interface
 type DB interface {
-	GetRoomAssignments(string, ...uint) ([]*bridgeModel.RoomAssignment, error)
+	GetRoomAssignments(context.Context, string, ...uint) ([]*bridgeModel.RoomAssignment, error)
+	GetQueuedRoomAssignments(context.Context) ([]*bridgeModel.RoomAssignment, error)
 }
 
 // DefaultBridge struct encapsulates the surgery software bridge
@@ -27,8 +29,9 @@ func NewBridge(db DB) *DefaultBridge {
 }
 
 // GetToBeExaminedPatients returns all patients that are queued to be examined next
-func (b *DefaultBridge) GetToBeExaminedPatients() ([]*model.Patient, error) {
-	assignments, err := b.db.GetRoomAssignments(config.Bridge.CallActionWZ, config.Bridge.CallActionQueuePosition)
+func (b *DefaultBridge) GetToBeExaminedPatients(ctx context.Context) ([]*model.Patient, error) {
+	room, position := config.CallAction()
+	assignments, err := b.db.GetRoomAssignments(ctx, room, position)
 	if err != nil {
 		return nil, errors.Wrap(err, "get patients by room assignment failed")
 	}
@@ -39,8 +42,9 @@ func (b *DefaultBridge) GetToBeExaminedPatients() ([]*model.Patient, error) {
 }
 
 // GetExaminedPatients returns all patients that have been examined and are finished now since last call
-func (b *DefaultBridge) GetExaminedPatients() ([]*model.Patient, error) {
-	assignments, err := b.db.GetRoomAssignments(config.Bridge.CallActionWZ, config.Bridge.CallActionQueuePosition)
+func (b *DefaultBridge) GetExaminedPatients(ctx context.Context) ([]*model.Patient, error) {
+	room, position := config.CallAction()
+	assignments, err := b.db.GetRoomAssignments(ctx, room, position)
 	if err != nil {
 		return nil, errors.Wrap(err, "get patients by room assignment failed")
 	}
@@ -56,6 +60,34 @@ func (b *DefaultBridge) GetExaminedPatients() ([]*model.Patient, error) {
 	return patients, nil
 }
 
+// GetQueuePositions returns the positions of all queued patients in the queues of their rooms
+func (b *DefaultBridge) GetQueuePositions(ctx context.Context) ([]*model.QueuePosition, error) {
+	assignments, err := b.db.GetQueuedRoomAssignments(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "get queued room assignments failed")
+	}
+
+	positions := make([]*model.QueuePosition, 0, len(assignments))
+	var room string
+	var position uint
+	for _, assignment := range assignments {
+		// assignments are ordered by room, so the position restarts with every room
+		if assignment.Room != room {
+			room = assignment.Room
+			position = 0
+		}
+		position++
+
+		positions = append(positions, &model.QueuePosition{
+			PatientID: assignment.PID,
+			Room:      assignment.Room,
+			Position:  position,
+		})
+	}
+
+	return positions, nil
+}
+
 func subtractSet(assignmentsA, assignmentsB []*bridgeModel.RoomAssignment) []*bridgeModel.RoomAssignment {
 	sortAssignmentsByPID(assignmentsB)
 
@@ -1,6 +1,7 @@
 package bridge
 
 import (
+	"context"
 	"testing"
 
 	bridgeModel "github.com/pagient/pagient-server/internal/bridge/model"
@@ -63,11 +64,11 @@ func TestDefaultBridge_GetToBeExaminedPatients(t *testing.T) {
 		t.Logf("Running test case: %s", name)
 
 		db := &MockDB{}
-		db.On("GetRoomAssignments", mock.AnythingOfType("string"), mock.AnythingOfType("uint")).Return(test.roomAssignments, test.dbError).Once()
+		db.On("GetRoomAssignments", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("uint")).Return(test.roomAssignments, test.dbError).Once()
 
 		bridge := NewBridge(db)
 
-		patientsExaminedNext, err := bridge.GetToBeExaminedPatients()
+		patientsExaminedNext, err := bridge.GetToBeExaminedPatients(context.Background())
 		assert.ElementsMatch(t, test.patients, patientsExaminedNext)
 		if test.dbError != nil {
 			assert.Error(t, err)
@@ -215,8 +216,8 @@ func TestDefaultBridge_GetExaminedPatients(t *testing.T) {
 
 		db := &MockDB{}
 		callCount := 0
-		db.On("GetRoomAssignments", mock.AnythingOfType("string"), mock.AnythingOfType("uint")).
-			Return(func(s string, u ...uint) []*bridgeModel.RoomAssignment {
+		db.On("GetRoomAssignments", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("uint")).
+			Return(func(ctx context.Context, s string, u ...uint) []*bridgeModel.RoomAssignment {
 				callCount++
 				if callCount == 1 {
 					return test.lastAssignments
@@ -227,10 +228,10 @@ func TestDefaultBridge_GetExaminedPatients(t *testing.T) {
 
 		bridge := NewBridge(db)
 
-		patientsExamined, err := bridge.GetExaminedPatients()
+		patientsExamined, err := bridge.GetExaminedPatients(context.Background())
 		assert.ElementsMatch(t, nil, patientsExamined)
 
-		patientsExamined, err = bridge.GetExaminedPatients()
+		patientsExamined, err = bridge.GetExaminedPatients(context.Background())
 		assert.ElementsMatch(t, test.patients, patientsExamined)
 		if test.dbError != nil {
 			assert.Error(t, err)
@@ -240,3 +241,74 @@ func TestDefaultBridge_GetExaminedPatients(t *testing.T) {
 		}
 	}
 }
+
+func TestDefaultBridge_GetQueuePositions(t *testing.T) {
+	tests := map[string]struct {
+		roomAssignments []*bridgeModel.RoomAssignment
+		dbError         error
+		positions       []*model.QueuePosition
+	}{
+		"no room assignments": {
+			roomAssignments: nil,
+			dbError:         nil,
+			positions:       []*model.QueuePosition{},
+		},
+		"room assignments of multiple rooms": {
+			roomAssignments: []*bridgeModel.RoomAssignment{
+				{
+					PID:  4,
+					Room: "A",
+				},
+				{
+					PID:  2,
+					Room: "A",
+				},
+				{
+					PID:  3,
+					Room: "B",
+				},
+			},
+			dbError: nil,
+			positions: []*model.QueuePosition{
+				{
+					PatientID: 4,
+					Room:      "A",
+					Position:  1,
+				},
+				{
+					PatientID: 2,
+					Room:      "A",
+					Position:  2,
+				},
+				{
+					PatientID: 3,
+					Room:      "B",
+					Position:  1,
+				},
+			},
+		},
+		"database error": {
+			roomAssignments: nil,
+			dbError:         errors.New("sample test error"),
+			positions:       nil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		db := &MockDB{}
+		db.On("GetQueuedRoomAssignments", mock.Anything).Return(test.roomAssignments, test.dbError).Once()
+
+		bridge := NewBridge(db)
+
+		positions, err := bridge.GetQueuePositions(context.Background())
+		assert.Equal(t, test.positions, positions)
+		if test.dbError != nil {
+			assert.Error(t, err)
+			assert.EqualError(t, test.dbError, errors.Cause(err).Error())
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"net/url"
@@ -14,7 +15,9 @@ import (
 
 // DB interface
 type DB interface {
-	GetRoomAssignments(string, ...uint) ([]*bridgeModel.RoomAssignment, error)
+	GetRoomAssignments(context.Context, string, ...uint) ([]*bridgeModel.RoomAssignment, error)
+	GetQueuedRoomAssignments(context.Context) ([]*bridgeModel.RoomAssignment, error)
+	Ping() error
 	Close() error
 }
 
@@ -1,33 +1,44 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"time"
 
 	"github.com/pagient/pagient-server/internal/bridge/model"
+	"github.com/pagient/pagient-server/internal/metrics"
+	"github.com/pagient/pagient-server/internal/tracing"
 
 	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
 )
 
 // GetRoomAssignments returns current assignments of patients to surgery rooms
-func (db *db) GetRoomAssignments(roomSymbol string, limit ...uint) ([]*model.RoomAssignment, error) {
+func (db *db) GetRoomAssignments(ctx context.Context, roomSymbol string, limit ...uint) (assignments []*model.RoomAssignment, err error) {
+	defer observeQuery("room_assignments", time.Now())
+
 	top := 0
 	if len(limit) > 0 {
 		top = int(limit[0])
 	}
 
+	ctx, span := tracing.Start(ctx, "bridge.room_assignments",
+		trace.StringAttribute("room", roomSymbol),
+		trace.Int64Attribute("limit", int64(top)),
+	)
+	defer func() { tracing.End(span, err) }()
+
 	var rows *sql.Rows
-	var err error
 	if top == 0 {
-		rows, err = db.Query("SELECT pds6_wz.PID FROM pds6_wz JOIN pds6_stwz ON pds6_wz.wzid = pds6_stwz.wzid WHERE pds6_stwz.code = @p1 ORDER BY pds6_wz.flgnr ASC", roomSymbol)
+		rows, err = db.QueryContext(ctx, "SELECT pds6_wz.PID FROM pds6_wz JOIN pds6_stwz ON pds6_wz.wzid = pds6_stwz.wzid WHERE pds6_stwz.code = @p1 ORDER BY pds6_wz.flgnr ASC", roomSymbol)
 	} else {
-		rows, err = db.Query("SELECT TOP(@p1) pds6_wz.PID FROM pds6_wz JOIN pds6_stwz ON pds6_wz.wzid = pds6_stwz.wzid WHERE pds6_stwz.code = @p2 ORDER BY pds6_wz.flgnr ASC", top, roomSymbol)
+		rows, err = db.QueryContext(ctx, "SELECT TOP(@p1) pds6_wz.PID FROM pds6_wz JOIN pds6_stwz ON pds6_wz.wzid = pds6_stwz.wzid WHERE pds6_stwz.code = @p2 ORDER BY pds6_wz.flgnr ASC", top, roomSymbol)
 	}
 	if err != nil {
 		return nil, errors.Wrap(err, "could not query database")
 	}
 	defer rows.Close()
 
-	var assignments []*model.RoomAssignment
 	for rows.Next() {
 		entry := &model.RoomAssignment{}
 		err := rows.Scan(&entry.PID)
@@ -40,3 +51,34 @@ func (db *db) GetRoomAssignments(roomSymbol string, limit ...uint) ([]*model.Roo
 	err = rows.Err()
 	return assignments, errors.Wrap(err, "row got an error")
 }
+
+// GetQueuedRoomAssignments returns current assignments of patients to all surgery rooms ordered by room and queue order
+func (db *db) GetQueuedRoomAssignments(ctx context.Context) (assignments []*model.RoomAssignment, err error) {
+	defer observeQuery("queued_room_assignments", time.Now())
+
+	ctx, span := tracing.Start(ctx, "bridge.queued_room_assignments")
+	defer func() { tracing.End(span, err) }()
+
+	rows, err := db.QueryContext(ctx, "SELECT pds6_wz.PID, pds6_stwz.code FROM pds6_wz JOIN pds6_stwz ON pds6_wz.wzid = pds6_stwz.wzid ORDER BY pds6_stwz.code ASC, pds6_wz.flgnr ASC")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not query database")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entry := &model.RoomAssignment{}
+		err := rows.Scan(&entry.PID, &entry.Room)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not scan database row")
+		}
+		assignments = append(assignments, entry)
+	}
+
+	err = rows.Err()
+	return assignments, errors.Wrap(err, "row got an error")
+}
+
+// observeQuery records the latency of a query started at start
+func observeQuery(query string, start time.Time) {
+	metrics.BridgeQueryDuration.WithLabelValues(query).Observe(metrics.Since(start))
+}
@@ -3,6 +3,7 @@
 package bridge
 
 import mock "github.com/stretchr/testify/mock"
+import context "context"
 import model "github.com/pagient/pagient-server/internal/bridge/model"
 
 // MockDB is an autogenerated mock type for the DB type
@@ -10,20 +11,43 @@ type MockDB struct {
 	mock.Mock
 }
 
-// GetRoomAssignments provides a mock function with given fields: _a0, _a1
-func (_m *MockDB) GetRoomAssignments(_a0 string, _a1 ...uint) ([]*model.RoomAssignment, error) {
-	_va := make([]interface{}, len(_a1))
-	for _i := range _a1 {
-		_va[_i] = _a1[_i]
+// GetQueuedRoomAssignments provides a mock function with given fields: _a0
+func (_m *MockDB) GetQueuedRoomAssignments(_a0 context.Context) ([]*model.RoomAssignment, error) {
+	ret := _m.Called(_a0)
+
+	var r0 []*model.RoomAssignment
+	if rf, ok := ret.Get(0).(func(context.Context) []*model.RoomAssignment); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.RoomAssignment)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRoomAssignments provides a mock function with given fields: _a0, _a1, _a2
+func (_m *MockDB) GetRoomAssignments(_a0 context.Context, _a1 string, _a2 ...uint) ([]*model.RoomAssignment, error) {
+	_va := make([]interface{}, len(_a2))
+	for _i := range _a2 {
+		_va[_i] = _a2[_i]
 	}
 	var _ca []interface{}
-	_ca = append(_ca, _a0)
+	_ca = append(_ca, _a0, _a1)
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
 	var r0 []*model.RoomAssignment
-	if rf, ok := ret.Get(0).(func(string, ...uint) []*model.RoomAssignment); ok {
-		r0 = rf(_a0, _a1...)
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...uint) []*model.RoomAssignment); ok {
+		r0 = rf(_a0, _a1, _a2...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*model.RoomAssignment)
@@ -31,8 +55,8 @@ func (_m *MockDB) GetRoomAssignments(_a0 string, _a1 ...uint) ([]*model.RoomAssi
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, ...uint) error); ok {
-		r1 = rf(_a0, _a1...)
+	if rf, ok := ret.Get(1).(func(context.Context, string, ...uint) error); ok {
+		r1 = rf(_a0, _a1, _a2...)
 	} else {
 		r1 = ret.Error(1)
 	}
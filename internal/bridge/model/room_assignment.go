@@ -2,5 +2,6 @@ package model
 
 // RoomAssignment model of internal system's database
 type RoomAssignment struct {
-	PID uint
+	PID  uint
+	Room string
 }
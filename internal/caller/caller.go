@@ -1,96 +1,88 @@
 package caller
 
 import (
+	"context"
 	"sort"
+	"sync/atomic"
 	"time"
 
+	"github.com/pagient/pagient-server/internal/metrics"
 	"github.com/pagient/pagient-server/internal/model"
 	"github.com/pagient/pagient-server/internal/service"
+	"github.com/pagient/pagient-server/internal/tracing"
 
 	"github.com/pkg/errors"
+	"github.com/rs/xid"
 	"github.com/rs/zerolog/log"
+	"go.opencensus.io/trace"
 )
 
 // SoftwareBridge provides abstraction for different practitioner software
 type SoftwareBridge interface {
-	GetToBeExaminedPatients() ([]*model.Patient, error)
-	GetExaminedPatients() ([]*model.Patient, error)
+	GetToBeExaminedPatients(context.Context) ([]*model.Patient, error)
+	GetExaminedPatients(context.Context) ([]*model.Patient, error)
+	GetQueuePositions(context.Context) ([]*model.QueuePosition, error)
 }
 
 // Caller struct encapsulates the surgery software bridge
 type Caller struct {
-	service service.PatientService
+	service service.VisitService
 	bridge  SoftwareBridge
+	// lastPoll holds the unix nanoseconds of the last successful poll
+	lastPoll int64
+	// every holds the polling interval, reset signals the running loop to pick up a changed one
+	every int64
+	reset chan struct{}
 }
 
 // NewCaller returns a surgery software bridge struct
-func NewCaller(s service.PatientService, bridge SoftwareBridge) *Caller {
+func NewCaller(s service.VisitService, bridge SoftwareBridge) *Caller {
 	return &Caller{
 		service: s,
 		bridge:  bridge,
+		reset:   make(chan struct{}, 1),
 	}
 }
 
 // Run runs the bridge functionality in a new goroutine repeated by given every every
 func (c *Caller) Run(every time.Duration, stop <-chan struct{}) error {
+	// the caller counts as fresh until it had the chance to poll
+	atomic.StoreInt64(&c.lastPoll, time.Now().UnixNano())
+	atomic.StoreInt64(&c.every, int64(every))
+
 	ticker := time.NewTicker(every)
 	go func() {
 		for {
 			select {
 			case <-ticker.C:
-				patients, err := c.service.ListPagerPatientsByStatus(model.PatientStatusPending)
-				if err != nil {
-					log.Error().
-						Err(err).
-						Msg("get not yet alerted patients having pagers failed")
-
-					continue
-				}
-
-				queuedPatients, err := c.bridge.GetToBeExaminedPatients()
-				if err != nil {
-					log.Error().
-						Err(err).
-						Msg("get to be examined patients from software bridge failed")
-
-					continue
-				}
+				start := time.Now()
 
-				toBeCalledPatients := intersectionSet(patients, queuedPatients)
-				if err := c.callPatients(toBeCalledPatients); err != nil {
-					log.Error().
-						Err(err).
-						Msg("call patients failed")
+				// log entries of a poll are correlated by it's id, including those of the service layer
+				pollID := xid.New().String()
+				logger := log.With().Str("poll_id", pollID).Logger()
 
-					continue
-				}
+				// the trace of a poll shows the time spent from querying the bridge until sending the pages
+				ctx, span := tracing.Start(context.Background(), "caller.poll", trace.StringAttribute("poll_id", pollID))
 
-				patients, err = c.service.ListPagerPatientsByStatus(model.PatientStatusPending, model.PatientStatusCall, model.PatientStatusCalled)
+				step, err := c.poll(ctx, c.service.WithLogger(logger).WithContext(ctx))
 				if err != nil {
-					log.Error().
-						Err(err).
-						Msg("get examined/finished patients having pagers failed")
-
-					continue
-				}
+					metrics.CallerPollErrors.WithLabelValues(step).Inc()
+					span.AddAttributes(trace.StringAttribute("step", step))
 
-				finishedPatients, err := c.bridge.GetExaminedPatients()
-				if err != nil {
-					log.Error().
+					logger.Error().
 						Err(err).
-						Msg("get examined patients from software bridge failed")
-
-					continue
+						Str("step", step).
+						Msg("poll practitioner software failed")
+				} else {
+					atomic.StoreInt64(&c.lastPoll, time.Now().UnixNano())
+					metrics.CallerLastSuccess.SetToCurrentTime()
 				}
+				tracing.End(span, err)
 
-				notReturnedPagerPatients := intersectionSet(patients, finishedPatients)
-				if err := c.markExaminedPatientsFinished(notReturnedPagerPatients); err != nil {
-					log.Error().
-						Err(err).
-						Msg("set patients finished failed")
-
-					continue
-				}
+				metrics.CallerPollDuration.Observe(metrics.Since(start))
+			case <-c.reset:
+				ticker.Stop()
+				ticker = time.NewTicker(c.Interval())
 			case <-stop:
 				// close goroutine
 				ticker.Stop()
@@ -103,39 +95,106 @@ func (c *Caller) Run(every time.Duration, stop <-chan struct{}) error {
 	return nil
 }
 
-func (c *Caller) callPatients(patients []*model.Patient) error {
-	for _, patient := range patients {
-		if err := c.service.CallPatient(patient); err != nil {
-			return errors.Wrap(err, "call patient failed")
+// SetInterval changes the polling interval of the running caller
+func (c *Caller) SetInterval(every time.Duration) {
+	atomic.StoreInt64(&c.every, int64(every))
+
+	select {
+	case c.reset <- struct{}{}:
+	default:
+		// a reset is pending already and picks up the latest interval
+	}
+}
+
+// Interval returns the current polling interval
+func (c *Caller) Interval() time.Duration {
+	return time.Duration(atomic.LoadInt64(&c.every))
+}
+
+// LastPoll returns the time of the last successful poll of the practitioner software
+func (c *Caller) LastPoll() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&c.lastPoll))
+}
+
+// poll calls the pagers of queued patients, finishes examined patients and updates queue positions,
+// it returns the step that failed along with the error
+func (c *Caller) poll(ctx context.Context, svc service.VisitService) (string, error) {
+	visits, err := svc.ListPagerVisitsByStatus(model.VisitStatusPending)
+	if err != nil {
+		return "list_pending_visits", errors.Wrap(err, "get not yet alerted visits having pagers failed")
+	}
+
+	queuedPatients, err := c.bridge.GetToBeExaminedPatients(ctx)
+	if err != nil {
+		return "get_queued_patients", errors.Wrap(err, "get to be examined patients from software bridge failed")
+	}
+
+	toBeCalledVisits := intersectionSet(visits, queuedPatients)
+	if err := c.callVisits(svc, toBeCalledVisits); err != nil {
+		return "call_visits", errors.Wrap(err, "call visits failed")
+	}
+
+	visits, err = svc.ListPagerVisitsByStatus(model.VisitStatusPending, model.VisitStatusCall, model.VisitStatusCalled)
+	if err != nil {
+		return "list_called_visits", errors.Wrap(err, "get examined/finished visits having pagers failed")
+	}
+
+	finishedPatients, err := c.bridge.GetExaminedPatients(ctx)
+	if err != nil {
+		return "get_examined_patients", errors.Wrap(err, "get examined patients from software bridge failed")
+	}
+
+	notReturnedPagerVisits := intersectionSet(visits, finishedPatients)
+	if err := c.markExaminedVisitsFinished(svc, notReturnedPagerVisits); err != nil {
+		return "finish_visits", errors.Wrap(err, "set visits finished failed")
+	}
+
+	positions, err := c.bridge.GetQueuePositions(ctx)
+	if err != nil {
+		return "get_queue_positions", errors.Wrap(err, "get queue positions from software bridge failed")
+	}
+
+	if err := svc.UpdateQueuePositions(positions); err != nil {
+		return "update_queue_positions", errors.Wrap(err, "update queue positions failed")
+	}
+
+	return "", nil
+}
+
+func (c *Caller) callVisits(svc service.VisitService, visits []*model.Visit) error {
+	for _, visit := range visits {
+		if err := svc.CallVisit(visit); err != nil {
+			return errors.Wrap(err, "call visit failed")
 		}
 	}
 
 	return nil
 }
 
-func (c *Caller) markExaminedPatientsFinished(patients []*model.Patient) error {
-	for _, patient := range patients {
-		patient.Status = model.PatientStatusFinished
-		if err := c.service.UpdatePatient(patient); err != nil {
-			return errors.Wrap(err, "update patient failed")
+func (c *Caller) markExaminedVisitsFinished(svc service.VisitService, visits []*model.Visit) error {
+	for _, visit := range visits {
+		visit.Status = model.VisitStatusFinished
+		if err := svc.UpdateVisit(visit); err != nil {
+			return errors.Wrap(err, "update visit failed")
 		}
 	}
 
 	return nil
 }
 
-func intersectionSet(patientsA, patientsB []*model.Patient) []*model.Patient {
-	sortPatientsByID(patientsB)
+// intersectionSet returns the visits of the given patients
+func intersectionSet(visits []*model.Visit, patients []*model.Patient) []*model.Visit {
+	sortPatientsByID(patients)
 
-	intersectionSet := make([]*model.Patient, 0, min(len(patientsA), len(patientsB)))
-	for _, patientA := range patientsA {
-		for _, patientB := range patientsB {
-			if patientA.ID < patientB.ID {
+	intersectionSet := make([]*model.Visit, 0, min(len(visits), len(patients)))
+	for _, visit := range visits {
+		for _, patient := range patients {
+			if visit.PatientID < patient.ID {
 				break
 			}
 
-			if patientA.ID == patientB.ID {
-				intersectionSet = append(intersectionSet, patientA)
+			if visit.PatientID == patient.ID {
+				intersectionSet = append(intersectionSet, visit)
 			}
 
 		}
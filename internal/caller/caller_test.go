@@ -8,6 +8,7 @@ import (
 	"github.com/pagient/pagient-server/internal/service"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestCaller_Run(t *testing.T) {
@@ -33,32 +34,53 @@ func TestCaller_Run(t *testing.T) {
 		},
 	}
 
+	visitPool := map[int]*model.Visit{
+		1: {
+			ID:        11,
+			PatientID: 1,
+		},
+		2: {
+			ID:        12,
+			PatientID: 2,
+		},
+		3: {
+			ID:        13,
+			PatientID: 3,
+		},
+		4: {
+			ID:        14,
+			PatientID: 4,
+		},
+	}
+
 	tests := map[string]struct {
 		every            time.Duration
 		repeats          int
-		patients         []*model.Patient
+		visits           []*model.Visit
 		toBeExamined     []*model.Patient
-		calledPatients   []*model.Patient
+		calledVisits     []*model.Visit
 		haveBeenExamined []*model.Patient
-		finishedPatients []*model.Patient
+		finishedVisits   []*model.Visit
+		positions        []*model.QueuePosition
 	}{
 		"should run every given every": {
 			every:            time.Duration(50) * time.Millisecond,
 			repeats:          2,
-			patients:         nil,
+			visits:           nil,
 			toBeExamined:     nil,
-			calledPatients:   nil,
+			calledVisits:     nil,
 			haveBeenExamined: nil,
-			finishedPatients: nil,
+			finishedVisits:   nil,
+			positions:        nil,
 		},
-		"should call \"pending\" patients that are examined next": {
-			every:   time.Duration(1) * time.Millisecond,
+		"should call \"pending\" visits of patients that are examined next": {
+			every:   time.Duration(10) * time.Millisecond,
 			repeats: 1,
-			patients: []*model.Patient{
-				patientPool[1],
-				patientPool[2],
-				patientPool[3],
-				patientPool[4],
+			visits: []*model.Visit{
+				visitPool[1],
+				visitPool[2],
+				visitPool[3],
+				visitPool[4],
 			},
 			toBeExamined: []*model.Patient{
 				patientPool[3],
@@ -66,33 +88,48 @@ func TestCaller_Run(t *testing.T) {
 				patientPool[1],
 				patientPool[5],
 			},
-			calledPatients: []*model.Patient{
-				patientPool[3],
-				patientPool[1],
+			calledVisits: []*model.Visit{
+				visitPool[3],
+				visitPool[1],
 			},
 			haveBeenExamined: nil,
-			finishedPatients: nil,
+			finishedVisits:   nil,
+			positions:        nil,
 		},
-		"should set status \"finished\" for examined patients": {
-			every:   time.Duration(1) * time.Millisecond,
+		"should set status \"finished\" for visits of examined patients": {
+			every:   time.Duration(10) * time.Millisecond,
 			repeats: 1,
-			patients: []*model.Patient{
-				patientPool[1],
-				patientPool[2],
-				patientPool[3],
-				patientPool[4],
+			visits: []*model.Visit{
+				visitPool[1],
+				visitPool[2],
+				visitPool[3],
+				visitPool[4],
 			},
-			toBeExamined:   nil,
-			calledPatients: nil,
+			toBeExamined: nil,
+			calledVisits: nil,
 			haveBeenExamined: []*model.Patient{
 				patientPool[3],
 				patientPool[6],
 				patientPool[1],
 				patientPool[5],
 			},
-			finishedPatients: []*model.Patient{
-				patientPool[3],
-				patientPool[1],
+			finishedVisits: []*model.Visit{
+				visitPool[3],
+				visitPool[1],
+			},
+			positions: nil,
+		},
+		"should update queue positions": {
+			every:            time.Duration(10) * time.Millisecond,
+			repeats:          1,
+			visits:           nil,
+			toBeExamined:     nil,
+			calledVisits:     nil,
+			haveBeenExamined: nil,
+			finishedVisits:   nil,
+			positions: []*model.QueuePosition{
+				{PatientID: 1, Room: "A", Position: 1},
+				{PatientID: 2, Room: "A", Position: 2},
 			},
 		},
 	}
@@ -101,28 +138,33 @@ func TestCaller_Run(t *testing.T) {
 		t.Logf("Running test case: %s", name)
 
 		s := &service.MockService{}
-		s.On("ListPagerPatientsByStatus", model.PatientStatusPending).Return(test.patients, nil)
-		s.On("ListPagerPatientsByStatus", model.PatientStatusPending, model.PatientStatusCall, model.PatientStatusCalled).Return(test.patients, nil)
+		s.On("WithLogger", mock.Anything).Return(s)
+		s.On("WithContext", mock.Anything).Return(s)
+		s.On("ListPagerVisitsByStatus", model.VisitStatusPending).Return(test.visits, nil)
+		s.On("ListPagerVisitsByStatus", model.VisitStatusPending, model.VisitStatusCall, model.VisitStatusCalled).Return(test.visits, nil)
 
-		for _, patient := range test.calledPatients {
+		// the ticker may fire once more before the caller stops, so calls aren't limited
+		for _, visit := range test.calledVisits {
 			s.
-				On("CallPatient", patient).
-				Return(nil).
-				Times(test.repeats)
+				On("CallVisit", visit).
+				Return(nil)
 		}
 
-		for _, patient := range test.finishedPatients {
+		for _, visit := range test.finishedVisits {
 			s.
-				On("UpdatePatient", patient).
-				Return(nil).
-				Times(test.repeats)
+				On("UpdateVisit", visit).
+				Return(nil)
 		}
 
+		s.On("UpdateQueuePositions", test.positions).Return(nil)
+
 		b := &MockSoftwareBridge{}
-		b.On("GetToBeExaminedPatients").
+		b.On("GetToBeExaminedPatients", mock.Anything).
 			Return(test.toBeExamined, nil)
-		b.On("GetExaminedPatients").
+		b.On("GetExaminedPatients", mock.Anything).
 			Return(test.haveBeenExamined, nil)
+		b.On("GetQueuePositions", mock.Anything).
+			Return(test.positions, nil)
 
 		caller := NewCaller(s, b)
 		stop := make(chan struct{}, 1)
@@ -136,5 +178,15 @@ func TestCaller_Run(t *testing.T) {
 		maxDur := test.every*time.Duration(test.repeats) + test.every/2
 		<-time.After(maxDur)
 		close(stop)
+
+		for _, visit := range test.calledVisits {
+			s.AssertCalled(t, "CallVisit", visit)
+		}
+
+		for _, visit := range test.finishedVisits {
+			s.AssertCalled(t, "UpdateVisit", visit)
+		}
+
+		s.AssertCalled(t, "UpdateQueuePositions", test.positions)
 	}
 }
@@ -3,6 +3,7 @@
 package caller
 
 import mock "github.com/stretchr/testify/mock"
+import context "context"
 import model "github.com/pagient/pagient-server/internal/model"
 
 // MockSoftwareBridge is an autogenerated mock type for the SoftwareBridge type
@@ -10,13 +11,13 @@ type MockSoftwareBridge struct {
 	mock.Mock
 }
 
-// GetExaminedPatients provides a mock function with given fields:
-func (_m *MockSoftwareBridge) GetExaminedPatients() ([]*model.Patient, error) {
-	ret := _m.Called()
+// GetExaminedPatients provides a mock function with given fields: _a0
+func (_m *MockSoftwareBridge) GetExaminedPatients(_a0 context.Context) ([]*model.Patient, error) {
+	ret := _m.Called(_a0)
 
 	var r0 []*model.Patient
-	if rf, ok := ret.Get(0).(func() []*model.Patient); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []*model.Patient); ok {
+		r0 = rf(_a0)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*model.Patient)
@@ -24,8 +25,8 @@ func (_m *MockSoftwareBridge) GetExaminedPatients() ([]*model.Patient, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -33,13 +34,36 @@ func (_m *MockSoftwareBridge) GetExaminedPatients() ([]*model.Patient, error) {
 	return r0, r1
 }
 
-// GetToBeExaminedPatients provides a mock function with given fields:
-func (_m *MockSoftwareBridge) GetToBeExaminedPatients() ([]*model.Patient, error) {
-	ret := _m.Called()
+// GetQueuePositions provides a mock function with given fields: _a0
+func (_m *MockSoftwareBridge) GetQueuePositions(_a0 context.Context) ([]*model.QueuePosition, error) {
+	ret := _m.Called(_a0)
+
+	var r0 []*model.QueuePosition
+	if rf, ok := ret.Get(0).(func(context.Context) []*model.QueuePosition); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.QueuePosition)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetToBeExaminedPatients provides a mock function with given fields: _a0
+func (_m *MockSoftwareBridge) GetToBeExaminedPatients(_a0 context.Context) ([]*model.Patient, error) {
+	ret := _m.Called(_a0)
 
 	var r0 []*model.Patient
-	if rf, ok := ret.Get(0).(func() []*model.Patient); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []*model.Patient); ok {
+		r0 = rf(_a0)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*model.Patient)
@@ -47,8 +71,8 @@ func (_m *MockSoftwareBridge) GetToBeExaminedPatients() ([]*model.Patient, error
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -7,10 +7,10 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	_ "github.com/kardianos/minwinsvc" // import minwinsvc for windows services
 	"github.com/pkg/errors"
-	"github.com/rs/zerolog"
 	"gopkg.in/ini.v1"
 )
 
@@ -19,28 +19,145 @@ var (
 	Path string
 
 	// General config
-	General = &general{}
+	General = initial.General
 	// Server config
-	Server = &server{}
+	Server = initial.Server
 	// DB config
-	DB = &db{}
+	DB = initial.DB
 	// Log config
-	Log = &log{}
-
+	Log = initial.Log
+	// Auth config
+	Auth = initial.Auth
+	// LDAP directory config
+	LDAP = initial.LDAP
+	// OIDC single sign-on config
+	OIDC = initial.OIDC
+	// Retention of personal data config
+	Retention = initial.Retention
 	// Bridge to internal system config
-	Bridge = &bridge{}
+	Bridge = initial.Bridge
 	// EasyCall config
-	EasyCall = &easyCall{}
+	EasyCall = initial.EasyCall
+	// Notifier config
+	Notifier = initial.Notifier
+	// Metrics config
+	Metrics = initial.Metrics
+	// Tracing config
+	Tracing = initial.Tracing
+	// Backup of the database config
+	Backup = initial.Backup
 
 	// AppWorkPath of binary
 	AppWorkPath string
 	isWindows   bool
+
+	initial = defaults()
 )
 
+// settings holds the configs of all sections
+type settings struct {
+	General   *general
+	Server    *server
+	DB        *db
+	Log       *log
+	Auth      *auth
+	LDAP      *ldap
+	OIDC      *oidc
+	Retention *retention
+	Bridge    *bridge
+	EasyCall  *easyCall
+	Notifier  *notifier
+	Metrics   *metrics
+	Tracing   *tracing
+	Backup    *backup
+}
+
+// defaults returns new settings holding the default values
+func defaults() *settings {
+	return &settings{
+		General: &general{},
+		Server:  &server{},
+		DB:      &db{},
+		Log: &log{
+			Outputs:   []string{"file"},
+			File:      "pagient.log",
+			MaxSize:   100,
+			SyslogTag: "pagient-server",
+		},
+		Auth: &auth{
+			AccessTokenLifetime:  15 * time.Minute,
+			RefreshTokenLifetime: 12 * time.Hour,
+			TokenCleanupInterval: time.Hour,
+			LoginRateLimit:       10,
+			LoginRateWindow:      time.Minute,
+			LockoutThreshold:     5,
+			LockoutDuration:      15 * time.Minute,
+			PasswordMinLength:    8,
+		},
+		LDAP: &ldap{
+			UserFilter:     "(&(objectClass=person)(sAMAccountName=%s))",
+			GroupAttribute: "memberOf",
+			Timeout:        5 * time.Second,
+			ClientGroups:   map[string]string{},
+		},
+		OIDC: &oidc{
+			Scopes:        []string{"openid", "profile", "email"},
+			UsernameClaim: "preferred_username",
+			GroupsClaim:   "groups",
+			ClientGroups:  map[string]string{},
+		},
+		Retention: &retention{
+			Mode:     "delete",
+			Interval: time.Hour,
+		},
+		Bridge:   &bridge{},
+		EasyCall: &easyCall{},
+		Notifier: &notifier{
+			QueueSize:      100,
+			WebhookTimeout: 5 * time.Second,
+			RelayInterval:  time.Second,
+		},
+		Metrics: &metrics{
+			Enabled: true,
+		},
+		Tracing: &tracing{
+			Exporter:    "otlp",
+			Endpoint:    "localhost:55678",
+			ServiceName: "pagient-server",
+			SampleRatio: 1,
+		},
+		Backup: &backup{
+			Path:     "backups",
+			Interval: 24 * time.Hour,
+			Keep:     7,
+		},
+	}
+}
+
+// current returns the settings of the package-level configs
+func current() *settings {
+	return &settings{
+		General:   General,
+		Server:    Server,
+		DB:        DB,
+		Log:       Log,
+		Auth:      Auth,
+		LDAP:      LDAP,
+		OIDC:      OIDC,
+		Retention: Retention,
+		Bridge:    Bridge,
+		EasyCall:  EasyCall,
+		Notifier:  Notifier,
+		Metrics:   Metrics,
+		Tracing:   Tracing,
+		Backup:    Backup,
+	}
+}
+
 // General defines the general configuration.
 type general struct {
 	Root   string `ini:"ROOT"`
-	Secret string `ini:"SECRET"`
+	Secret string `ini:"SECRET" secret:"true"`
 }
 
 // Server defines the server configuration.
@@ -61,76 +178,163 @@ type db struct {
 	Port     int    `ini:"DB_PORT,omitempty"`
 	Name     string `ini:"DB_NAME,omitempty"`
 	User     string `ini:"DB_USER,omitempty"`
-	Password string `ini:"DB_PASSWORD,omitempty"`
+	Password string `ini:"DB_PASSWORD,omitempty" secret:"true"`
 	// in case of sqlite
 	Path string `ini:"DB_PATH,omitempty"`
 }
 
 // Log defines the logging configuration.
 type log struct {
-	Level   string `ini:"LEVEL"`
+	Level   string `ini:"LEVEL" reload:"true"`
 	Colored bool   `ini:"COLORED"`
 	Pretty  bool   `ini:"PRETTY"`
+	// Outputs of log entries, any of stdout, stderr, file and syslog
+	Outputs []string `ini:"OUTPUTS" delim:","`
+
+	File           string        `ini:"FILE"`
+	MaxSize        int           `ini:"MAX_SIZE"`
+	MaxAge         time.Duration `ini:"MAX_AGE"`
+	MaxBackups     int           `ini:"MAX_BACKUPS"`
+	RotateInterval time.Duration `ini:"ROTATE_INTERVAL"`
+	Compress       bool          `ini:"COMPRESS"`
+
+	// syslog is local if network and address are empty
+	SyslogNetwork string `ini:"SYSLOG_NETWORK"`
+	SyslogAddress string `ini:"SYSLOG_ADDRESS"`
+	SyslogTag     string `ini:"SYSLOG_TAG"`
+}
+
+// Auth defines the authentication configuration
+type auth struct {
+	AccessTokenLifetime  time.Duration `ini:"ACCESS_TOKEN_LIFETIME"`
+	RefreshTokenLifetime time.Duration `ini:"REFRESH_TOKEN_LIFETIME"`
+	TokenCleanupInterval time.Duration `ini:"TOKEN_CLEANUP_INTERVAL"`
+
+	LoginRateLimit   int           `ini:"LOGIN_RATE_LIMIT"`
+	LoginRateWindow  time.Duration `ini:"LOGIN_RATE_WINDOW"`
+	LockoutThreshold int           `ini:"LOCKOUT_THRESHOLD"`
+	LockoutDuration  time.Duration `ini:"LOCKOUT_DURATION"`
+
+	PasswordMinLength     int  `ini:"PASSWORD_MIN_LENGTH"`
+	PasswordRequireUpper  bool `ini:"PASSWORD_REQUIRE_UPPER"`
+	PasswordRequireLower  bool `ini:"PASSWORD_REQUIRE_LOWER"`
+	PasswordRequireDigit  bool `ini:"PASSWORD_REQUIRE_DIGIT"`
+	PasswordRequireSymbol bool `ini:"PASSWORD_REQUIRE_SYMBOL"`
+}
+
+// Retention defines how long personal data is kept
+type retention struct {
+	// Patients is the duration finished patients are kept, zero keeps them forever
+	Patients time.Duration `ini:"PATIENTS"`
+	Mode     string        `ini:"MODE"`
+	Interval time.Duration `ini:"INTERVAL"`
 }
 
 // Bridge defines the surgery software bridge configuration
 type bridge struct {
 	DB                      db     `ini:"bridge"`
-	PollingInterval         int    `ini:"POLLING_INTERVAL"`
-	CallActionWZ            string `ini:"CALL_ACTION_WZ"`
-	CallActionQueuePosition uint   `ini:"CALL_ACTION_QUEUE_POSITION"`
-	RemoveActionWZ          string `ini:"REMOVE_ACTION_WZ"`
+	PollingInterval         int    `ini:"POLLING_INTERVAL" reload:"true"`
+	CallActionWZ            string `ini:"CALL_ACTION_WZ" reload:"true"`
+	CallActionQueuePosition uint   `ini:"CALL_ACTION_QUEUE_POSITION" reload:"true"`
+	RemoveActionWZ          string `ini:"REMOVE_ACTION_WZ" reload:"true"`
+}
+
+// LDAP defines the ldap / active directory authentication configuration
+type ldap struct {
+	Enabled            bool          `ini:"ENABLED"`
+	URL                string        `ini:"URL"`
+	StartTLS           bool          `ini:"START_TLS"`
+	InsecureSkipVerify bool          `ini:"INSECURE_SKIP_VERIFY"`
+	Timeout            time.Duration `ini:"TIMEOUT"`
+	BindDN             string        `ini:"BIND_DN"`
+	BindPassword       string        `ini:"BIND_PASSWORD" secret:"true"`
+	BaseDN             string        `ini:"BASE_DN"`
+	UserFilter         string        `ini:"USER_FILTER"`
+	GroupAttribute     string        `ini:"GROUP_ATTRIBUTE"`
+	// group dns contain commas, so they are separated by semicolons
+	AdminGroups []string `ini:"ADMIN_GROUPS" delim:";"`
+	UserGroups  []string `ini:"USER_GROUPS" delim:";"`
+	// ClientGroups maps client names to the group dn of their users
+	ClientGroups map[string]string `ini:"-"`
+}
+
+// OIDC defines the openid connect single sign-on configuration
+type oidc struct {
+	Enabled      bool     `ini:"ENABLED"`
+	Issuer       string   `ini:"ISSUER"`
+	ClientID     string   `ini:"CLIENT_ID"`
+	ClientSecret string   `ini:"CLIENT_SECRET" secret:"true"`
+	RedirectURL  string   `ini:"REDIRECT_URL"`
+	Scopes       []string `ini:"SCOPES" delim:","`
+	// claims of the id token holding username and groups of the user
+	UsernameClaim string   `ini:"USERNAME_CLAIM"`
+	GroupsClaim   string   `ini:"GROUPS_CLAIM"`
+	AdminGroups   []string `ini:"ADMIN_GROUPS" delim:";"`
+	UserGroups    []string `ini:"USER_GROUPS" delim:";"`
+	// ClientGroups maps client names to the group of their users
+	ClientGroups map[string]string `ini:"-"`
 }
 
 // EasyCall defines the easycall pager backend configuration
 type easyCall struct {
-	URL      string `ini:"URL"`
-	User     string `ini:"USER"`
-	Password string `ini:"PASSWORD"`
-	Port     string `ini:"PORT"`
+	URL      string `ini:"URL" reload:"true"`
+	User     string `ini:"USER" reload:"true"`
+	Password string `ini:"PASSWORD" secret:"true" reload:"true"`
+	Port     string `ini:"PORT" reload:"true"`
 }
 
-// Load loads the configuration from `Path`
-func Load() error {
-	isWindows = runtime.GOOS == "windows"
+// Notifier defines the ui notification configuration
+type notifier struct {
+	QueueSize      int           `ini:"QUEUE_SIZE"`
+	Webhooks       []string      `ini:"WEBHOOKS" delim:","`
+	WebhookTimeout time.Duration `ini:"WEBHOOK_TIMEOUT"`
+	// WebhookReveal sends full social security numbers to the webhooks instead of masked ones
+	WebhookReveal bool          `ini:"WEBHOOK_REVEAL"`
+	Log           bool          `ini:"LOG"`
+	RelayInterval time.Duration `ini:"RELAY_INTERVAL"`
+}
 
-	var appPath string
-	var err error
-	if appPath, err = getAppPath(); err != nil {
-		return errors.Wrap(err, "could not get application path")
-	}
-	AppWorkPath = getWorkPath(appPath)
+// Metrics defines the prometheus metrics configuration
+type metrics struct {
+	Enabled bool `ini:"ENABLED"`
+	// Token scrapers have to send as bearer token, metrics are public if empty
+	Token string `ini:"TOKEN" secret:"true"`
+}
 
-	if !filepath.IsAbs(Path) {
-		Path = path.Join(AppWorkPath, Path)
-	}
+// Tracing defines the opencensus tracing configuration
+type tracing struct {
+	Enabled bool `ini:"ENABLED"`
+	// Exporter of spans, either otlp or stdout for testing
+	Exporter string `ini:"EXPORTER"`
+	// Endpoint of the collector accepting spans by grpc, e.g. localhost:55678
+	Endpoint    string  `ini:"ENDPOINT"`
+	Insecure    bool    `ini:"INSECURE"`
+	ServiceName string  `ini:"SERVICE_NAME"`
+	SampleRatio float64 `ini:"SAMPLE_RATIO"`
+}
 
-	config, err := ini.Load(Path)
-	if err != nil {
-		return errors.Wrap(err, "could not load ini config")
-	}
+// Backup defines the scheduled backups of the database
+type backup struct {
+	Enabled bool `ini:"ENABLED"`
+	// Path of the folder backups are written to
+	Path     string        `ini:"PATH"`
+	Interval time.Duration `ini:"INTERVAL"`
+	// Keep is the count of scheduled backups kept, zero keeps all of them
+	Keep int `ini:"KEEP"`
+}
 
-	if err = config.Section("general").MapTo(General); err != nil {
-		return errors.Wrap(err, "could not map general section")
+// Load loads the configuration from `Path` and creates the folders of stored data
+func Load() error {
+	if err := Read(); err != nil {
+		return err
 	}
 
-	if !filepath.IsAbs(General.Root) {
-		General.Root = path.Join(AppWorkPath, General.Root)
-	}
 	if err := os.MkdirAll(General.Root, os.ModePerm); err != nil {
 		return errors.Wrap(err, "could not create folders of root path")
 	}
 
-	if err = config.Section("server").MapTo(Server); err != nil {
-		return errors.Wrap(err, "read config server section failed")
-	}
-
-	if err = config.Section("db").MapTo(DB); err != nil {
-		return errors.Wrap(err, "could not map db section")
-	}
-	DB.Path = sanitizePath(DB.Path)
 	// TODO: move to db package
-	dbFolder, err := getDBDirectory()
+	dbFolder, err := getDBDirectory(DB.Path)
 	if err != nil {
 		return err
 	}
@@ -138,24 +342,85 @@ func Load() error {
 		return errors.Wrap(err, "could not create folder of database path")
 	}
 
-	if err = config.Section("log").MapTo(Log); err != nil {
-		return errors.Wrap(err, "could not map log section")
+	return nil
+}
+
+// Read reads the configuration from `Path` and the overrides without any side effects,
+// all invalid values are reported at once by a ValidationError
+func Read() error {
+	s, err := read()
+	if err != nil {
+		return err
 	}
 
-	if _, err := zerolog.ParseLevel(Log.Level); err != nil {
-		return errors.Wrap(err, "could not parse log level")
+	mu.Lock()
+	defer mu.Unlock()
+
+	General, Server, DB, Log = s.General, s.Server, s.DB, s.Log
+	Auth, LDAP, OIDC, Retention = s.Auth, s.LDAP, s.OIDC, s.Retention
+	Bridge, EasyCall, Notifier, Metrics = s.Bridge, s.EasyCall, s.Notifier, s.Metrics
+	Tracing, Backup = s.Tracing, s.Backup
+
+	return nil
+}
+
+// read reads the configuration from `Path` and the overrides into new settings
+func read() (*settings, error) {
+	isWindows = runtime.GOOS == "windows"
+
+	var appPath string
+	var err error
+	if appPath, err = getAppPath(); err != nil {
+		return nil, errors.Wrap(err, "could not get application path")
 	}
+	AppWorkPath = getWorkPath(appPath)
 
-	if err = config.Section("bridge").MapTo(Bridge); err != nil {
-		return errors.Wrap(err, "read config bridge section failed")
+	if !filepath.IsAbs(Path) {
+		Path = path.Join(AppWorkPath, Path)
 	}
 
-	if err = config.Section("easycall").MapTo(EasyCall); err != nil {
-		return errors.Wrap(err, "read config easycall section failed")
+	var config *ini.File
+	if _, err := os.Stat(Path); os.IsNotExist(err) && hasOverrides(os.Environ()) {
+		// containers may be configured by overrides only
+		config = ini.Empty()
+	} else if config, err = ini.Load(Path); err != nil {
+		return nil, errors.Wrap(err, "could not load ini config")
 	}
-	EasyCall.URL = strings.TrimSuffix(EasyCall.URL, "/")
 
-	return nil
+	if err := applyOverrides(config, os.Environ(), Overrides); err != nil {
+		return nil, errors.Wrap(err, "could not apply config overrides")
+	}
+
+	// values not matching the type of their field are skipped by mapping, so they are reported beforehand
+	problems := checkTypes(config)
+
+	s := defaults()
+	for _, section := range s.sections() {
+		if err := config.Section(section.name).MapTo(section.config); err != nil {
+			return nil, errors.Wrapf(err, "could not map %s section", section.name)
+		}
+	}
+
+	s.LDAP.ClientGroups = config.Section("ldap.clients").KeysHash()
+	s.OIDC.ClientGroups = config.Section("oidc.clients").KeysHash()
+
+	if !filepath.IsAbs(s.General.Root) {
+		s.General.Root = path.Join(AppWorkPath, s.General.Root)
+	}
+	s.DB.Path = sanitizePath(s.General.Root, s.DB.Path)
+	s.Log.File = sanitizePath(s.General.Root, s.Log.File)
+	s.Backup.Path = sanitizePath(s.General.Root, s.Backup.Path)
+	if s.OIDC.RedirectURL == "" {
+		s.OIDC.RedirectURL = strings.TrimSuffix(s.Server.Host, "/") + "/oauth/callback"
+	}
+	s.EasyCall.URL = strings.TrimSuffix(s.EasyCall.URL, "/")
+
+	problems = append(problems, validate(s)...)
+	if len(problems) > 0 {
+		return nil, &ValidationError{problems}
+	}
+
+	return s, nil
 }
 
 func getAppPath() (string, error) {
@@ -196,18 +461,18 @@ func getWorkPath(appPath string) string {
 	return strings.Replace(workPath, "\\", "/", -1)
 }
 
-func sanitizePath(dirtyPath string) string {
+func sanitizePath(root, dirtyPath string) string {
 	if !filepath.IsAbs(dirtyPath) {
-		return path.Join(General.Root, dirtyPath)
+		return path.Join(root, dirtyPath)
 	}
 
 	return dirtyPath
 }
 
-func getDBDirectory() (string, error) {
+func getDBDirectory(dbPath string) (string, error) {
 	// Note: we don't use path.Dir here because it does not handle case
 	//		 which path starts with two "/" in Windows: "//psf/Home/..."
-	dbPath := strings.Replace(DB.Path, "\\", "/", -1)
+	dbPath = strings.Replace(dbPath, "\\", "/", -1)
 
 	i := strings.LastIndex(dbPath, "/")
 	if i == -1 {
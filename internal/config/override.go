@@ -0,0 +1,173 @@
+package config
+
+import (
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/ini.v1"
+)
+
+const (
+	// envPrefix marks environment variables overriding config values, e.g. PAGIENT_DB_DB_PASSWORD
+	envPrefix = "PAGIENT_"
+	// fileSuffix marks environment variables holding the path of a file containing the value, e.g. for secrets
+	fileSuffix = "_FILE"
+)
+
+// Overrides of config values passed as command-line flags, formatted section.KEY=value
+var Overrides []string
+
+type section struct {
+	name   string
+	config interface{}
+}
+
+// sections returns the configs of all sections in the order of the sample config
+func (s *settings) sections() []*section {
+	return []*section{
+		{"general", s.General},
+		{"db", s.DB},
+		{"server", s.Server},
+		{"log", s.Log},
+		{"auth", s.Auth},
+		{"ldap", s.LDAP},
+		{"oidc", s.OIDC},
+		{"retention", s.Retention},
+		{"easycall", s.EasyCall},
+		{"bridge", s.Bridge},
+		{"notifier", s.Notifier},
+		{"metrics", s.Metrics},
+		{"tracing", s.Tracing},
+		{"backup", s.Backup},
+	}
+}
+
+type field struct {
+	key   string
+	value reflect.Value
+	tag   reflect.StructTag
+}
+
+// fields returns the fields of a section config by the keys of their ini tags,
+// fields of nested structs belong to the same section
+func fields(config interface{}) []*field {
+	val := reflect.Indirect(reflect.ValueOf(config))
+	typ := val.Type()
+
+	var list []*field
+	for i := 0; i < typ.NumField(); i++ {
+		structField := typ.Field(i)
+
+		key := strings.Split(structField.Tag.Get("ini"), ",")[0]
+		if key == "-" || key == "" {
+			continue
+		}
+
+		if structField.Type.Kind() == reflect.Struct && structField.Type != reflect.TypeOf(time.Time{}) {
+			list = append(list, fields(val.Field(i).Addr().Interface())...)
+			continue
+		}
+
+		list = append(list, &field{key, val.Field(i), structField.Tag})
+	}
+
+	return list
+}
+
+// sectionKeys returns the keys of a section config
+func sectionKeys(config interface{}) map[string]bool {
+	keys := map[string]bool{}
+	for _, f := range fields(config) {
+		keys[f.key] = true
+	}
+
+	return keys
+}
+
+// hasOverrides returns whether any config value is overridden by environment variables or command-line flags
+func hasOverrides(environ []string) bool {
+	if len(Overrides) > 0 {
+		return true
+	}
+
+	for _, env := range environ {
+		if strings.HasPrefix(env, envPrefix) && !strings.HasPrefix(env, envPrefix+"CONFIG=") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// applyOverrides sets the values of environment variables and command-line flags in the ini config,
+// the precedence is command-line flags over environment variables over the ini file over defaults
+func applyOverrides(config *ini.File, environ []string, flags []string) error {
+	known := map[string]map[string]bool{}
+	for _, section := range defaults().sections() {
+		known[section.name] = sectionKeys(section.config)
+	}
+
+	set := map[string]string{}
+	for _, env := range environ {
+		pair := strings.SplitN(env, "=", 2)
+		if len(pair) != 2 || !strings.HasPrefix(pair[0], envPrefix) {
+			continue
+		}
+		name, value := pair[0], pair[1]
+
+		parts := strings.SplitN(strings.TrimPrefix(name, envPrefix), "_", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		section, key := strings.ToLower(parts[0]), parts[1]
+
+		// other variables of the prefix aren't config values, e.g. PAGIENT_CONFIG
+		if _, ok := known[section]; !ok {
+			continue
+		}
+
+		if strings.HasSuffix(key, fileSuffix) && !known[section][key] {
+			key = strings.TrimSuffix(key, fileSuffix)
+
+			content, err := ioutil.ReadFile(value)
+			if err != nil {
+				return errors.Wrapf(err, "read file of %s failed", name)
+			}
+			value = strings.TrimRight(string(content), "\r\n")
+		}
+
+		if !known[section][key] {
+			return errors.Errorf("environment variable %s overrides unknown config key %s in section %s", name, key, section)
+		}
+
+		if other, ok := set[section+"."+key]; ok {
+			return errors.Errorf("environment variables %s and %s override the same config key", other, name)
+		}
+		set[section+"."+key] = name
+
+		config.Section(section).Key(key).SetValue(value)
+	}
+
+	for _, flag := range flags {
+		pair := strings.SplitN(flag, "=", 2)
+		parts := strings.SplitN(pair[0], ".", 2)
+		if len(pair) != 2 || len(parts) != 2 {
+			return errors.Errorf("override %q must be formatted section.KEY=value", flag)
+		}
+		section, key := strings.ToLower(parts[0]), strings.ToUpper(parts[1])
+
+		if _, ok := known[section]; !ok {
+			return errors.Errorf("override %q of unknown config section %s", flag, section)
+		}
+		if !known[section][key] {
+			return errors.Errorf("override %q of unknown config key %s in section %s", flag, key, section)
+		}
+
+		config.Section(section).Key(key).SetValue(pair[1])
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/ini.v1"
+)
+
+func TestApplyOverrides(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pagient-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	secretFile := filepath.Join(dir, "secret")
+	if err := ioutil.WriteFile(secretFile, []byte("from-file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		file    string
+		environ []string
+		flags   []string
+		values  map[string]string
+		err     bool
+	}{
+		"file value": {
+			file:   "[server]\nADDRESS = :8080\n",
+			values: map[string]string{"server.ADDRESS": ":8080"},
+		},
+		"environment over file": {
+			file:    "[server]\nADDRESS = :8080\n",
+			environ: []string{"PAGIENT_SERVER_ADDRESS=:9090", "HOME=/root"},
+			values:  map[string]string{"server.ADDRESS": ":9090"},
+		},
+		"flag over environment": {
+			environ: []string{"PAGIENT_SERVER_ADDRESS=:9090"},
+			flags:   []string{"server.ADDRESS=:7070"},
+			values:  map[string]string{"server.ADDRESS": ":7070"},
+		},
+		"key containing underscores": {
+			environ: []string{"PAGIENT_DB_DB_PASSWORD=secret", "PAGIENT_BRIDGE_DB_HOST=mssql"},
+			values:  map[string]string{"db.DB_PASSWORD": "secret", "bridge.DB_HOST": "mssql"},
+		},
+		"value read from file": {
+			environ: []string{"PAGIENT_GENERAL_SECRET_FILE=" + secretFile},
+			values:  map[string]string{"general.SECRET": "from-file"},
+		},
+		"missing file": {
+			environ: []string{"PAGIENT_GENERAL_SECRET_FILE=" + filepath.Join(dir, "missing")},
+			err:     true,
+		},
+		"value and file of same key": {
+			environ: []string{"PAGIENT_GENERAL_SECRET=plain", "PAGIENT_GENERAL_SECRET_FILE=" + secretFile},
+			err:     true,
+		},
+		"variables not being config values": {
+			environ: []string{"PAGIENT_CONFIG=/conf/app.ini", "PAGIENT_UNKNOWN_KEY=value"},
+			values:  map[string]string{},
+		},
+		"unknown key": {
+			environ: []string{"PAGIENT_SERVER_ADRESS=:9090"},
+			err:     true,
+		},
+		"malformed flag": {
+			flags: []string{"server.ADDRESS"},
+			err:   true,
+		},
+		"flag of unknown section": {
+			flags: []string{"unknown.KEY=value"},
+			err:   true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		cfg, err := ini.Load([]byte(test.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = applyOverrides(cfg, test.environ, test.flags)
+		if test.err {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+
+		for key, value := range test.values {
+			parts := strings.SplitN(key, ".", 2)
+			assert.Equal(t, value, cfg.Section(parts[0]).Key(parts[1]).String(), key)
+		}
+	}
+}
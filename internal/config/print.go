@@ -0,0 +1,79 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// redacted replaces secrets when printing the config
+const redacted = "<redacted>"
+
+// Print writes the effective config in ini format, values of fields tagged as secret are redacted
+func Print(w io.Writer) error {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	children := map[string]map[string]string{
+		"ldap": LDAP.ClientGroups,
+		"oidc": OIDC.ClientGroups,
+	}
+
+	for i, section := range current().sections() {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+
+		fmt.Fprintf(w, "[%s]\n", section.name)
+		for _, f := range fields(section.config) {
+			if _, err := fmt.Fprintf(w, "%s = %s\n", f.key, formatValue(f)); err != nil {
+				return err
+			}
+		}
+
+		if child, ok := children[section.name]; ok {
+			printMap(w, section.name+".clients", child)
+		}
+	}
+
+	return nil
+}
+
+func printMap(w io.Writer, name string, values map[string]string) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fmt.Fprintf(w, "\n[%s]\n", name)
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s = %s\n", key, values[key])
+	}
+}
+
+func formatValue(f *field) string {
+	if f.tag.Get("secret") == "true" && !isZero(f.value) {
+		return redacted
+	}
+
+	switch value := f.value.Interface().(type) {
+	case time.Duration:
+		return value.String()
+	case []string:
+		delim := f.tag.Get("delim")
+		if delim == "" {
+			delim = ","
+		}
+		return strings.Join(value, delim)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func isZero(value reflect.Value) bool {
+	return reflect.DeepEqual(value.Interface(), reflect.Zero(value.Type()).Interface())
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// mu guards the settings tagged as reloadable, which have to be read by their getters while the server runs
+var mu sync.RWMutex
+
+// Change describes a config value differing between the running and the reloaded config
+type Change struct {
+	// Key of the value, formatted section.KEY
+	Key string
+	// Old and New value, secrets are redacted
+	Old string
+	New string
+	// Applied is false if the value only takes effect on restart
+	Applied bool
+}
+
+func (c *Change) String() string {
+	return fmt.Sprintf("%s: %q -> %q", c.Key, c.Old, c.New)
+}
+
+// Reload reads the configuration again and applies the values tagged as reloadable at once,
+// an invalid config is refused and none of its values are applied
+func Reload() ([]*Change, error) {
+	s, err := read()
+	if err != nil {
+		return nil, err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	return apply(current(), s), nil
+}
+
+// apply sets the reloadable values of next in running and returns all differing values
+func apply(running, next *settings) []*Change {
+	var changes []*Change
+
+	nextSections := next.sections()
+	for i, section := range running.sections() {
+		nextFields := fields(nextSections[i].config)
+
+		for j, f := range fields(section.config) {
+			if reflect.DeepEqual(f.value.Interface(), nextFields[j].value.Interface()) {
+				continue
+			}
+
+			change := &Change{
+				Key: section.name + "." + f.key,
+				Old: formatValue(f),
+				New: formatValue(nextFields[j]),
+			}
+
+			if f.tag.Get("reload") == "true" {
+				f.value.Set(nextFields[j].value)
+				change.Applied = true
+			}
+
+			changes = append(changes, change)
+		}
+	}
+
+	children := []struct {
+		name          string
+		running, next map[string]string
+	}{
+		{"ldap.clients", running.LDAP.ClientGroups, next.LDAP.ClientGroups},
+		{"oidc.clients", running.OIDC.ClientGroups, next.OIDC.ClientGroups},
+	}
+	for _, child := range children {
+		if !reflect.DeepEqual(child.running, child.next) {
+			changes = append(changes, &Change{
+				Key: child.name,
+				Old: fmt.Sprint(child.running),
+				New: fmt.Sprint(child.next),
+			})
+		}
+	}
+
+	return changes
+}
+
+// PollingInterval returns the interval of polling the practitioner software
+func PollingInterval() time.Duration {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return time.Duration(Bridge.PollingInterval) * time.Second
+}
+
+// CallAction returns the room and the queue position in it patients are called at
+func CallAction() (string, uint) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return Bridge.CallActionWZ, Bridge.CallActionQueuePosition
+}
+
+// PagerGateway returns the settings of the easycall pager gateway
+func PagerGateway() (url, user, password, port string) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return EasyCall.URL, EasyCall.User, EasyCall.Password, EasyCall.Port
+}
+
+// LogLevel returns the minimum level of log entries
+func LogLevel() string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return Log.Level
+}
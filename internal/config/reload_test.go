@@ -0,0 +1,97 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApply(t *testing.T) {
+	tests := map[string]struct {
+		change   func(*settings)
+		changes  []*Change
+		interval int
+		address  string
+	}{
+		"nothing changed": {
+			change:   func(s *settings) {},
+			changes:  nil,
+			interval: 5,
+			address:  ":8080",
+		},
+		"reloadable values": {
+			change: func(s *settings) {
+				s.Bridge.PollingInterval = 10
+				s.EasyCall.Password = "n3w"
+			},
+			changes: []*Change{
+				{Key: "easycall.PASSWORD", Old: redacted, New: redacted, Applied: true},
+				{Key: "bridge.POLLING_INTERVAL", Old: "5", New: "10", Applied: true},
+			},
+			interval: 10,
+			address:  ":8080",
+		},
+		"values requiring a restart": {
+			change: func(s *settings) {
+				s.Server.Address = ":9090"
+				s.LDAP.ClientGroups = map[string]string{"reception": "cn=reception"}
+			},
+			changes: []*Change{
+				{Key: "server.ADDRESS", Old: ":8080", New: ":9090"},
+				{Key: "ldap.clients", Old: "map[]", New: "map[reception:cn=reception]"},
+			},
+			interval: 5,
+			address:  ":8080",
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		running, next := defaults(), defaults()
+		for _, s := range []*settings{running, next} {
+			s.Server.Address = ":8080"
+			s.Bridge.PollingInterval = 5
+			s.EasyCall.Password = "0ld"
+		}
+		test.change(next)
+
+		assert.Equal(t, test.changes, apply(running, next))
+		assert.Equal(t, test.interval, running.Bridge.PollingInterval)
+		assert.Equal(t, test.address, running.Server.Address)
+	}
+}
+
+func TestReload(t *testing.T) {
+	sample, err := filepath.Abs("../../conf/app.ini.sample")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Path = sample
+	Overrides = []string{"general.SECRET=s3cret"}
+	if err := Read(); err != nil {
+		t.Fatal(err)
+	}
+
+	// invalid configs are refused as a whole
+	Overrides = []string{"general.SECRET=s3cret", "bridge.CALL_ACTION_WZ=Wartezimmer", "bridge.POLLING_INTERVAL=0"}
+	changes, err := Reload()
+	assert.IsType(t, &ValidationError{}, err)
+	assert.Nil(t, changes)
+	assert.NotEqual(t, "Wartezimmer", Bridge.CallActionWZ)
+
+	Overrides = []string{"general.SECRET=s3cret", "bridge.POLLING_INTERVAL=7", "server.ADDRESS=:9090"}
+	changes, err = Reload()
+	assert.NoError(t, err)
+	assert.Equal(t, []*Change{
+		{Key: "server.ADDRESS", Old: "0.0.0.0:8080", New: ":9090"},
+		{Key: "bridge.POLLING_INTERVAL", Old: "5", New: "7", Applied: true},
+	}, changes)
+	assert.Equal(t, 7*time.Second, PollingInterval())
+	assert.Equal(t, "0.0.0.0:8080", Server.Address)
+
+	Overrides = nil
+}
@@ -0,0 +1,249 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"gopkg.in/ini.v1"
+)
+
+// ValidationError lists all invalid values of the config
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "config is invalid: " + strings.Join(e.Problems, "; ")
+}
+
+type problems []string
+
+func (p *problems) addf(key string, format string, args ...interface{}) {
+	*p = append(*p, key+": "+fmt.Sprintf(format, args...))
+}
+
+// checkTypes reports values that can't be parsed into the type of their field,
+// every value is mapped on it's own, so all of them are reported at once
+func checkTypes(config *ini.File) []string {
+	var p problems
+	for _, section := range defaults().sections() {
+		types := map[string]reflect.Type{}
+		for _, f := range fields(section.config) {
+			types[f.key] = f.value.Type()
+		}
+
+		for _, key := range config.Section(section.name).Keys() {
+			typ, ok := types[key.Name()]
+			if !ok {
+				continue
+			}
+
+			single := ini.Empty()
+			single.Section(section.name).Key(key.Name()).SetValue(key.Value())
+
+			target := reflect.New(reflect.TypeOf(section.config).Elem()).Interface()
+			if err := single.Section(section.name).StrictMapTo(target); err != nil {
+				p.addf(section.name+"."+key.Name(), "%q is not a valid %s", key.Value(), typeName(typ))
+			}
+		}
+	}
+
+	return p
+}
+
+func typeName(typ reflect.Type) string {
+	switch {
+	case typ == reflect.TypeOf(time.Duration(0)):
+		return "duration, e.g. 30s, 5m or 1h"
+	case typ.Kind() == reflect.Bool:
+		return "boolean, either true or false"
+	case typ.Kind() == reflect.Uint:
+		return "non-negative integer"
+	case typ.Kind() == reflect.Int:
+		return "integer"
+	case typ.Kind() == reflect.Float64:
+		return "number, e.g. 0.5"
+	default:
+		return typ.String()
+	}
+}
+
+// validate reports invalid values of all sections
+func validate(s *settings) []string {
+	var p problems
+
+	if s.General.Secret == "" {
+		p.addf("general.SECRET", "is required to sign tokens and encrypt patient data")
+	}
+
+	if s.Server.Address == "" {
+		p.addf("server.ADDRESS", "is required")
+	}
+	if !isURL(s.Server.Host) {
+		p.addf("server.HOST", "%q is not an absolute url, e.g. http://localhost:8080", s.Server.Host)
+	}
+	if (s.Server.Cert == "") != (s.Server.Key == "") {
+		p.addf("server.CERT", "is required along with server.KEY to serve https")
+	}
+
+	if s.DB.Driver != "sqlite3" {
+		p.addf("db.DB_DRIVER", "%q is not supported, only sqlite3 is supported at the moment", s.DB.Driver)
+	}
+	if _, err := getDBDirectory(s.DB.Path); err != nil {
+		p.addf("db.DB_PATH", "has to specify the sqlite database file")
+	}
+
+	if _, err := zerolog.ParseLevel(s.Log.Level); err != nil {
+		p.addf("log.LEVEL", "%q is unknown, use debug, info, warn or error", s.Log.Level)
+	}
+	if len(s.Log.Outputs) == 0 {
+		p.addf("log.OUTPUTS", "is required")
+	}
+	for _, output := range s.Log.Outputs {
+		if output != "stdout" && output != "stderr" && output != "file" && output != "syslog" {
+			p.addf("log.OUTPUTS", "%q is unknown, use stdout, stderr, file or syslog", output)
+		}
+	}
+	if s.Log.MaxSize < 1 {
+		p.addf("log.MAX_SIZE", "has to be at least 1 megabyte")
+	}
+	if s.Log.MaxAge < 0 {
+		p.addf("log.MAX_AGE", "can't be negative, 0 keeps rotated files forever")
+	}
+	if s.Log.MaxBackups < 0 {
+		p.addf("log.MAX_BACKUPS", "can't be negative, 0 keeps all rotated files")
+	}
+	if s.Log.RotateInterval < 0 {
+		p.addf("log.ROTATE_INTERVAL", "can't be negative, 0 rotates by size only")
+	}
+	if (s.Log.SyslogNetwork == "") != (s.Log.SyslogAddress == "") {
+		p.addf("log.SYSLOG_NETWORK", "is required along with log.SYSLOG_ADDRESS to log to a remote syslog")
+	}
+
+	if s.Auth.AccessTokenLifetime <= 0 {
+		p.addf("auth.ACCESS_TOKEN_LIFETIME", "has to be positive")
+	}
+	if s.Auth.RefreshTokenLifetime <= 0 {
+		p.addf("auth.REFRESH_TOKEN_LIFETIME", "has to be positive")
+	}
+	if s.Auth.TokenCleanupInterval <= 0 {
+		p.addf("auth.TOKEN_CLEANUP_INTERVAL", "has to be positive")
+	}
+	if s.Auth.LoginRateLimit < 0 {
+		p.addf("auth.LOGIN_RATE_LIMIT", "can't be negative, 0 disables the limit")
+	}
+	if s.Auth.LoginRateLimit > 0 && s.Auth.LoginRateWindow <= 0 {
+		p.addf("auth.LOGIN_RATE_WINDOW", "has to be positive")
+	}
+	if s.Auth.LockoutThreshold < 0 {
+		p.addf("auth.LOCKOUT_THRESHOLD", "can't be negative, 0 disables lockouts")
+	}
+	if s.Auth.LockoutThreshold > 0 && s.Auth.LockoutDuration <= 0 {
+		p.addf("auth.LOCKOUT_DURATION", "has to be positive")
+	}
+	if s.Auth.PasswordMinLength < 1 {
+		p.addf("auth.PASSWORD_MIN_LENGTH", "has to be at least 1")
+	}
+
+	if s.LDAP.Enabled {
+		if u, err := url.Parse(s.LDAP.URL); err != nil || (u.Scheme != "ldap" && u.Scheme != "ldaps") || u.Host == "" {
+			p.addf("ldap.URL", "%q is not an ldap url, e.g. ldap://dc.example.local:389", s.LDAP.URL)
+		}
+		if s.LDAP.BaseDN == "" {
+			p.addf("ldap.BASE_DN", "is required")
+		}
+		if !strings.Contains(s.LDAP.UserFilter, "%s") {
+			p.addf("ldap.USER_FILTER", "has to contain %%s for the username")
+		}
+	}
+
+	if s.OIDC.Enabled {
+		if !isURL(s.OIDC.Issuer) {
+			p.addf("oidc.ISSUER", "%q is not an absolute url", s.OIDC.Issuer)
+		}
+		if s.OIDC.ClientID == "" {
+			p.addf("oidc.CLIENT_ID", "is required")
+		}
+		if !isURL(s.OIDC.RedirectURL) {
+			p.addf("oidc.REDIRECT_URL", "%q is not an absolute url", s.OIDC.RedirectURL)
+		}
+	}
+
+	if s.Retention.Patients < 0 {
+		p.addf("retention.PATIENTS", "can't be negative, 0 keeps patients forever")
+	}
+	if s.Retention.Mode != "delete" && s.Retention.Mode != "anonymize" {
+		p.addf("retention.MODE", "%q is unknown, use delete or anonymize", s.Retention.Mode)
+	}
+	if s.Retention.Patients > 0 && s.Retention.Interval <= 0 {
+		p.addf("retention.INTERVAL", "has to be positive")
+	}
+
+	if !isURL(s.EasyCall.URL) {
+		p.addf("easycall.URL", "%q is not an absolute url of the pager gateway", s.EasyCall.URL)
+	}
+	if s.EasyCall.Port == "" {
+		p.addf("easycall.PORT", "is required")
+	}
+
+	if s.Bridge.DB.Driver != "sqlserver" {
+		p.addf("bridge.DB_DRIVER", "%q is not supported, only sqlserver is supported at the moment", s.Bridge.DB.Driver)
+	}
+	if s.Bridge.DB.Host == "" {
+		p.addf("bridge.DB_HOST", "is required")
+	}
+	if s.Bridge.DB.Port < 1 || s.Bridge.DB.Port > 65535 {
+		p.addf("bridge.DB_PORT", "%d is not a valid port", s.Bridge.DB.Port)
+	}
+	if s.Bridge.PollingInterval < 1 {
+		p.addf("bridge.POLLING_INTERVAL", "has to be at least 1 second")
+	}
+	if s.Bridge.CallActionWZ == "" {
+		p.addf("bridge.CALL_ACTION_WZ", "is required")
+	}
+
+	if s.Notifier.QueueSize < 1 {
+		p.addf("notifier.QUEUE_SIZE", "has to be at least 1")
+	}
+	for _, webhook := range s.Notifier.Webhooks {
+		if !isURL(webhook) {
+			p.addf("notifier.WEBHOOKS", "%q is not an absolute url", webhook)
+		}
+	}
+	if s.Notifier.WebhookTimeout <= 0 {
+		p.addf("notifier.WEBHOOK_TIMEOUT", "has to be positive")
+	}
+	if s.Notifier.RelayInterval <= 0 {
+		p.addf("notifier.RELAY_INTERVAL", "has to be positive")
+	}
+
+	if s.Tracing.Enabled {
+		if s.Tracing.Exporter != "otlp" && s.Tracing.Exporter != "stdout" {
+			p.addf("tracing.EXPORTER", "%q is unknown, use otlp or stdout", s.Tracing.Exporter)
+		}
+		if s.Tracing.Exporter == "otlp" && s.Tracing.Endpoint == "" {
+			p.addf("tracing.ENDPOINT", "is required to export spans to a collector")
+		}
+		if s.Tracing.SampleRatio < 0 || s.Tracing.SampleRatio > 1 {
+			p.addf("tracing.SAMPLE_RATIO", "%v has to be between 0 and 1", s.Tracing.SampleRatio)
+		}
+	}
+
+	if s.Backup.Enabled && s.Backup.Interval <= 0 {
+		p.addf("backup.INTERVAL", "has to be positive")
+	}
+	if s.Backup.Keep < 0 {
+		p.addf("backup.KEEP", "can't be negative, 0 keeps all backups")
+	}
+
+	return p
+}
+
+func isURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/ini.v1"
+)
+
+func TestCheckTypes(t *testing.T) {
+	tests := map[string]struct {
+		file     string
+		problems []string
+	}{
+		"valid values": {
+			file:     "[bridge]\nPOLLING_INTERVAL = 5\nCALL_ACTION_QUEUE_POSITION = 0\n[auth]\nACCESS_TOKEN_LIFETIME = 15m\n",
+			problems: nil,
+		},
+		"invalid values": {
+			file: "[bridge]\nPOLLING_INTERVAL = five\nCALL_ACTION_QUEUE_POSITION = -1\n[auth]\nACCESS_TOKEN_LIFETIME = soon\n[log]\nCOLORED = maybe\n",
+			problems: []string{
+				`auth.ACCESS_TOKEN_LIFETIME: "soon" is not a valid duration, e.g. 30s, 5m or 1h`,
+				`bridge.POLLING_INTERVAL: "five" is not a valid integer`,
+				`bridge.CALL_ACTION_QUEUE_POSITION: "-1" is not a valid non-negative integer`,
+				`log.COLORED: "maybe" is not a valid boolean, either true or false`,
+			},
+		},
+		"unknown keys": {
+			file:     "[server]\nUNKNOWN = value\n",
+			problems: nil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		cfg, err := ini.Load([]byte(test.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.ElementsMatch(t, test.problems, checkTypes(cfg))
+	}
+}
+
+func TestRead(t *testing.T) {
+	sample, err := filepath.Abs("../../conf/app.ini.sample")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Path = sample
+	Overrides = nil
+
+	// the sample only lacks a secret, so it can't be used by accident
+	err = Read()
+	if assert.IsType(t, &ValidationError{}, err) {
+		assert.Equal(t, []string{"general.SECRET: is required to sign tokens and encrypt patient data"}, err.(*ValidationError).Problems)
+	}
+
+	Overrides = []string{"general.SECRET=s3cret", "bridge.POLLING_INTERVAL=0"}
+	err = Read()
+	if assert.IsType(t, &ValidationError{}, err) {
+		assert.Equal(t, []string{"bridge.POLLING_INTERVAL: has to be at least 1 second"}, err.(*ValidationError).Problems)
+	}
+
+	Overrides = []string{"general.SECRET=s3cret", "easycall.PASSWORD=pa55word"}
+	assert.NoError(t, Read())
+
+	var out bytes.Buffer
+	assert.NoError(t, Print(&out))
+	assert.Contains(t, out.String(), "SECRET = <redacted>")
+	assert.Contains(t, out.String(), "PASSWORD = <redacted>")
+	assert.Contains(t, out.String(), "POLLING_INTERVAL = 5")
+	assert.NotContains(t, out.String(), "s3cret")
+	assert.NotContains(t, out.String(), "pa55word")
+
+	Overrides = nil
+}
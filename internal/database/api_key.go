@@ -0,0 +1,70 @@
+package database
+
+import (
+	"github.com/pagient/pagient-server/internal/model"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// GetAPIKeys returns all api keys including their users, optionally restricted to a user
+func (t *tx) GetAPIKeys(username string) ([]*model.APIKey, error) {
+	var keys []*model.APIKey
+	query := t.Preload("User")
+	if username != "" {
+		query = query.Joins("JOIN users ON users.id = api_keys.user_id").
+			Where("users.username = ?", username)
+	}
+
+	err := query.Order("api_keys.id ASC").Find(&keys).Error
+
+	return keys, errors.Wrap(err, "select api keys failed")
+}
+
+// GetAPIKey returns the api key including it's user by id
+func (t *tx) GetAPIKey(id uint) (*model.APIKey, error) {
+	key := &model.APIKey{}
+	err := t.Preload("User").First(key, id).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	}
+
+	return key, errors.Wrap(err, "select api key failed")
+}
+
+// GetAPIKeyByHash returns the api key including it's user by the hash of the plain key
+func (t *tx) GetAPIKeyByHash(hash string) (*model.APIKey, error) {
+	key := &model.APIKey{}
+	err := t.Preload("User").Where(&model.APIKey{
+		Hash: hash,
+	}).First(key).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	}
+
+	return key, errors.Wrap(err, "select api key by hash failed")
+}
+
+// AddAPIKey adds an api key
+func (t *tx) AddAPIKey(key *model.APIKey) error {
+	err := t.Create(key).Error
+
+	return errors.Wrap(err, "create api key failed")
+}
+
+// UpdateAPIKeyLastUsed updates only the last usage of an api key
+func (t *tx) UpdateAPIKeyLastUsed(key *model.APIKey) error {
+	err := t.Model(key).UpdateColumn("last_used_at", key.LastUsedAt).Error
+
+	return errors.Wrap(err, "update api key last used failed")
+}
+
+// RemoveAPIKey removes an api key
+func (t *tx) RemoveAPIKey(key *model.APIKey) error {
+	err := t.Delete(key).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return &entryNotExistErr{"api key not found"}
+	}
+
+	return errors.Wrap(err, "delete api key failed")
+}
@@ -0,0 +1,24 @@
+package database
+
+import (
+	"time"
+
+	"github.com/pagient/pagient-server/internal/model"
+
+	"github.com/pkg/errors"
+)
+
+// GetAuditEntries returns the audit entries since the given time, newest first
+func (t *tx) GetAuditEntries(since time.Time) ([]*model.AuditEntry, error) {
+	var entries []*model.AuditEntry
+	err := t.Where("created_at >= ?", since).Order("created_at DESC").Find(&entries).Error
+
+	return entries, errors.Wrap(err, "select audit entries failed")
+}
+
+// AddAuditEntry records an audited operation
+func (t *tx) AddAuditEntry(entry *model.AuditEntry) error {
+	err := t.Create(entry).Error
+
+	return errors.Wrap(err, "create audit entry failed")
+}
@@ -0,0 +1,164 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pagient/pagient-server/internal/config"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// backupPrefix and backupSuffix enclose the time of a backup in it's file name, so backups sort by time
+	backupPrefix     = "pagient-"
+	backupSuffix     = ".db"
+	backupTimeFormat = "20060102-150405"
+)
+
+// Backup writes a consistent snapshot of the database to path while it's in use,
+// the file must not exist yet
+func (db *db) Backup(path string) error {
+	return errors.Wrap(db.DB.Exec("VACUUM INTO ?", path).Error, "vacuum into backup file failed")
+}
+
+// BackupFile returns the path of a backup in dir taken at given time
+func BackupFile(dir string, at time.Time) string {
+	return filepath.Join(dir, backupPrefix+at.Format(backupTimeFormat)+backupSuffix)
+}
+
+// PruneBackups removes the oldest backups in dir exceeding keep and returns their paths,
+// other files in dir are left untouched
+func PruneBackups(dir string, keep int) ([]string, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "read backup folder failed")
+	}
+
+	var backups []string
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || !strings.HasPrefix(name, backupPrefix) || !strings.HasSuffix(name, backupSuffix) {
+			continue
+		}
+
+		if _, err := time.Parse(backupTimeFormat, strings.TrimSuffix(strings.TrimPrefix(name, backupPrefix), backupSuffix)); err != nil {
+			continue
+		}
+
+		backups = append(backups, filepath.Join(dir, name))
+	}
+
+	if keep <= 0 || len(backups) <= keep {
+		return nil, nil
+	}
+
+	sort.Strings(backups)
+	pruned := backups[:len(backups)-keep]
+	for _, backup := range pruned {
+		if err := os.Remove(backup); err != nil {
+			return nil, errors.Wrapf(err, "remove backup %s failed", backup)
+		}
+	}
+
+	return pruned, nil
+}
+
+// Restore replaces the database at the configured path by the backup at src,
+// the replaced database is kept next to it and it's path is returned.
+// The database must not be in use, e.g. by a running web server.
+func Restore(src string) (string, error) {
+	if err := verifyBackup(src); err != nil {
+		return "", err
+	}
+
+	dst := config.DB.Path
+
+	// the backup is copied next to the database first, so the database is replaced at once
+	tmp, err := ioutil.TempFile(filepath.Dir(dst), filepath.Base(dst)+".restore")
+	if err != nil {
+		return "", errors.Wrap(err, "create temporary file failed")
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := copyFile(tmp, src); err != nil {
+		return "", err
+	}
+
+	var kept string
+	if _, err := os.Stat(dst); err == nil {
+		kept = fmt.Sprintf("%s.%s.bak", dst, time.Now().Format(backupTimeFormat))
+		if err := os.Rename(dst, kept); err != nil {
+			return "", errors.Wrap(err, "keep replaced database failed")
+		}
+	}
+
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		return "", errors.Wrap(err, "replace database failed")
+	}
+
+	// a journal left by the replaced database would be rolled back into the restored one
+	if err := os.Remove(dst + "-journal"); err != nil && !os.IsNotExist(err) {
+		return kept, errors.Wrap(err, "remove journal of replaced database failed")
+	}
+
+	return kept, nil
+}
+
+// verifyBackup checks the backup at path is an intact pagient database
+func verifyBackup(path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return errors.Wrap(err, "backup not found")
+	}
+
+	conn, err := sql.Open("sqlite3", "file:"+path+"?mode=ro")
+	if err != nil {
+		return errors.Wrap(err, "open backup failed")
+	}
+	defer conn.Close()
+
+	var result string
+	if err := conn.QueryRow("PRAGMA integrity_check").Scan(&result); err != nil {
+		return errors.Wrap(err, "check integrity of backup failed")
+	}
+	if result != "ok" {
+		return errors.Errorf("backup is corrupted: %s", result)
+	}
+
+	var tables int
+	if err := conn.QueryRow("SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name IN ('users', 'pagers')").Scan(&tables); err != nil {
+		return errors.Wrap(err, "read tables of backup failed")
+	}
+	if tables != 2 {
+		return errors.New("backup is no pagient database")
+	}
+
+	return nil
+}
+
+func copyFile(dst *os.File, src string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return errors.Wrap(err, "open backup failed")
+	}
+	defer in.Close()
+
+	if _, err := io.Copy(dst, in); err != nil {
+		dst.Close()
+		return errors.Wrap(err, "copy backup failed")
+	}
+
+	if err := dst.Sync(); err != nil {
+		dst.Close()
+		return errors.Wrap(err, "sync restored database failed")
+	}
+
+	return errors.Wrap(dst.Close(), "close restored database failed")
+}
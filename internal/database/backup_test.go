@@ -0,0 +1,127 @@
+package database
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pagient/pagient-server/internal/config"
+	"github.com/pagient/pagient-server/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDB_BackupRestore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pagient-backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	config.General.Secret = "secret"
+	config.DB.Driver = "sqlite3"
+	config.DB.Path = filepath.Join(dir, "pagient.sqlite3")
+
+	db, err := Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addPager := func(name string, id uint) {
+		tx, err := db.Begin()
+		assert.NoError(t, err)
+		assert.NoError(t, tx.AddPager(&model.Pager{Name: name, EasyCallID: id}))
+		assert.NoError(t, tx.Commit())
+	}
+
+	addPager("before backup", 1)
+
+	backup := BackupFile(dir, time.Now())
+	assert.NoError(t, db.Backup(backup))
+	// backups never overwrite files
+	assert.Error(t, db.Backup(backup))
+
+	addPager("after backup", 2)
+	assert.NoError(t, db.Close())
+
+	kept, err := Restore(backup)
+	assert.NoError(t, err)
+	assert.FileExists(t, kept)
+
+	db, err = Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+	pagers, err := tx.GetPagers()
+	assert.NoError(t, err)
+	tx.Commit()
+
+	if assert.Len(t, pagers, 1) {
+		assert.Equal(t, "before backup", pagers[0].Name)
+	}
+
+	// files other than databases are refused
+	other := filepath.Join(dir, "other.db")
+	assert.NoError(t, ioutil.WriteFile(other, []byte("no database"), 0600))
+	_, err = Restore(other)
+	assert.Error(t, err)
+}
+
+func TestPruneBackups(t *testing.T) {
+	tests := map[string]struct {
+		files  []string
+		keep   int
+		pruned []string
+	}{
+		"keep newest backups": {
+			files:  []string{"pagient-20180101-120000.db", "pagient-20180103-120000.db", "pagient-20180102-120000.db"},
+			keep:   2,
+			pruned: []string{"pagient-20180101-120000.db"},
+		},
+		"keep all backups": {
+			files:  []string{"pagient-20180101-120000.db", "pagient-20180102-120000.db"},
+			keep:   0,
+			pruned: nil,
+		},
+		"ignore other files": {
+			files:  []string{"pagient-20180101-120000.db", "pagient-manual.db", "notes.txt"},
+			keep:   1,
+			pruned: nil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		dir, err := ioutil.TempDir("", "pagient-prune")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, file := range test.files {
+			assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, file), nil, 0600))
+		}
+
+		var expected []string
+		for _, file := range test.pruned {
+			expected = append(expected, filepath.Join(dir, file))
+		}
+
+		pruned, err := PruneBackups(dir, test.keep)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, pruned)
+
+		for _, file := range pruned {
+			_, err := os.Stat(file)
+			assert.True(t, os.IsNotExist(err))
+		}
+
+		os.RemoveAll(dir)
+	}
+}
@@ -45,3 +45,21 @@ func (t *tx) AddClient(client *model.Client) error {
 
 	return errors.Wrap(err, "create client failed")
 }
+
+// UpdateClient updates all fields of provided client
+func (t *tx) UpdateClient(client *model.Client) error {
+	// FIXME: handle sql constraint errors
+	err := t.Save(client).Error
+
+	return errors.Wrap(err, "update client failed")
+}
+
+// RemoveClient deletes the client from the repository, closed visits keep referring to it
+func (t *tx) RemoveClient(client *model.Client) error {
+	err := t.Delete(client).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return &entryNotExistErr{"client not found"}
+	}
+
+	return errors.Wrap(err, "delete client failed")
+}
@@ -0,0 +1,96 @@
+package database
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// encryptedPrefix marks encrypted values, values without it are stored in plain
+const encryptedPrefix = "v1:"
+
+// fieldCipher encrypts sensitive fields at rest and computes blind indexes to look them up
+type fieldCipher struct {
+	aead     cipher.AEAD
+	indexKey []byte
+}
+
+// newFieldCipher derives separate encryption and index keys from the secret
+func newFieldCipher(secret string) (*fieldCipher, error) {
+	if secret == "" {
+		return nil, errors.New("secret for encryption of sensitive data is empty")
+	}
+
+	block, err := aes.NewCipher(deriveKey(secret, "pagient field encryption"))
+	if err != nil {
+		return nil, errors.Wrap(err, "create block cipher failed")
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "create gcm cipher failed")
+	}
+
+	return &fieldCipher{
+		aead:     aead,
+		indexKey: deriveKey(secret, "pagient blind index"),
+	}, nil
+}
+
+// encrypt returns the encrypted value with a random nonce
+func (c *fieldCipher) encrypt(plain string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", errors.Wrap(err, "generate nonce failed")
+	}
+
+	sealed := c.aead.Seal(nonce, nonce, []byte(plain), nil)
+
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decrypt returns the plain value, values stored before encryption are returned unchanged
+func (c *fieldCipher) decrypt(value string) (string, error) {
+	if !strings.HasPrefix(value, encryptedPrefix) {
+		return value, nil
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedPrefix))
+	if err != nil {
+		return "", errors.Wrap(err, "decode encrypted value failed")
+	}
+
+	if len(sealed) < c.aead.NonceSize() {
+		return "", errors.New("encrypted value too short")
+	}
+
+	nonce, sealed := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	plain, err := c.aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "decrypt value failed, has the secret been changed?")
+	}
+
+	return string(plain), nil
+}
+
+// index returns a deterministic keyed hash of the value, so equal values can be found without decryption
+func (c *fieldCipher) index(plain string) string {
+	mac := hmac.New(sha256.New, c.indexKey)
+	mac.Write([]byte(plain))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func deriveKey(secret, purpose string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(purpose))
+
+	return mac.Sum(nil)
+}
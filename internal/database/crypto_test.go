@@ -0,0 +1,74 @@
+package database
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFieldCipher_Decrypt(t *testing.T) {
+	cipher, err := newFieldCipher("secret")
+	assert.NoError(t, err)
+
+	encrypted, err := cipher.encrypt("1234010180")
+	assert.NoError(t, err)
+
+	otherCipher, err := newFieldCipher("other secret")
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		cipher *fieldCipher
+		value  string
+		plain  string
+		err    bool
+	}{
+		"decrypt encrypted value": {
+			cipher: cipher,
+			value:  encrypted,
+			plain:  "1234010180",
+			err:    false,
+		},
+		"keep value stored before encryption": {
+			cipher: cipher,
+			value:  "1234010180",
+			plain:  "1234010180",
+			err:    false,
+		},
+		"fail with other secret": {
+			cipher: otherCipher,
+			value:  encrypted,
+			plain:  "",
+			err:    true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		plain, err := test.cipher.decrypt(test.value)
+
+		assert.Equal(t, test.err, err != nil)
+		assert.Equal(t, test.plain, plain)
+	}
+}
+
+func TestFieldCipher_Encrypt(t *testing.T) {
+	cipher, err := newFieldCipher("secret")
+	assert.NoError(t, err)
+
+	first, err := cipher.encrypt("1234010180")
+	assert.NoError(t, err)
+	second, err := cipher.encrypt("1234010180")
+	assert.NoError(t, err)
+
+	// equal values are encrypted differently but share their blind index
+	assert.True(t, strings.HasPrefix(first, encryptedPrefix))
+	assert.NotEqual(t, first, second)
+	assert.NotContains(t, first, "1234010180")
+	assert.Equal(t, cipher.index("1234010180"), cipher.index("1234010180"))
+	assert.NotEqual(t, cipher.index("1234010180"), cipher.index("1234010181"))
+
+	_, err = newFieldCipher("")
+	assert.Error(t, err)
+}
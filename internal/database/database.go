@@ -1,33 +1,40 @@
 package database
 
 import (
-	"log"
-	"os"
+	"time"
 
 	"github.com/pagient/pagient-server/internal/config"
+	"github.com/pagient/pagient-server/internal/metrics"
 	"github.com/pagient/pagient-server/internal/model"
 	"github.com/pagient/pagient-server/internal/service"
 
 	"github.com/jinzhu/gorm"
 	_ "github.com/jinzhu/gorm/dialects/sqlite" // import sqlite for database connection
 	"github.com/pkg/errors"
-	"github.com/rs/zerolog"
 )
 
 // DB interface
 type DB interface {
 	Begin() (service.Tx, error)
+	Backup(string) error
+	Ping() error
 	Close() error
 }
 
 type db struct {
 	*gorm.DB
+	cipher *fieldCipher
 }
 
 // Begin starts an returns a new transaction.
 func (db *db) Begin() (service.Tx, error) {
 	t := db.DB.Begin()
-	return &tx{t}, errors.Wrap(t.Error, "begin new transaction failed")
+	return &tx{t, db.cipher, time.Now()}, errors.Wrap(t.Error, "begin new transaction failed")
+}
+
+// Ping checks the connection to the database
+func (db *db) Ping() error {
+	return db.DB.DB().Ping()
 }
 
 // Close closes the database
@@ -37,13 +44,18 @@ func (db *db) Close() error {
 
 type tx struct {
 	*gorm.DB
+	cipher *fieldCipher
+	// start of the transaction to observe it's duration
+	start time.Time
 }
 
 func (t *tx) Commit() error {
+	metrics.DBTransactionDuration.WithLabelValues("commit").Observe(metrics.Since(t.start))
 	return t.DB.Commit().Error
 }
 
 func (t *tx) Rollback() error {
+	metrics.DBTransactionDuration.WithLabelValues("rollback").Observe(metrics.Since(t.start))
 	return t.DB.Rollback().Error
 }
 
@@ -54,30 +66,88 @@ func Open() (DB, error) {
 		return nil, errors.New("only sqlite3 is supported at the moment")
 	}
 
+	cipher, err := newFieldCipher(config.General.Secret)
+	if err != nil {
+		return nil, errors.Wrap(err, "initialize encryption failed")
+	}
+
 	dbConn, err := gorm.Open(config.DB.Driver, config.DB.Path)
 	if err != nil {
 		return nil, errors.New("establish database connection failed")
 	}
 
-	dbConn.LogMode(zerolog.GlobalLevel() <= zerolog.DebugLevel)
-	dbConn.SetLogger(log.New(os.Stdout, "\r\n", 0))
+	// statements are logged at debug level, so they follow the log level changed on reload
+	dbConn.LogMode(true)
+	dbConn.SetLogger(gormLogger{})
+
+	// Visits have been split off patients, so existing patients get their visit once
+	migrateVisits := dbConn.HasTable(&model.Patient{}) && !dbConn.HasTable(&model.Visit{})
 
 	// Create database tables etc.
 	if err := createTables(dbConn); err != nil {
 		return nil, errors.New("create database tables failed")
 	}
 
-	return &db{dbConn}, nil
+	if migrateVisits {
+		if err := createVisitsOfPatients(dbConn); err != nil {
+			return nil, errors.Wrap(err, "create visits of patients failed")
+		}
+	}
+
+	// Visits finished before their finish time has been recorded are kept a full retention period from now on
+	if err := dbConn.Model(&model.Visit{}).
+		Where("status = ? AND finished_at IS NULL", model.VisitStatusFinished).
+		UpdateColumn("finished_at", time.Now()).
+		Error; err != nil {
+		return nil, errors.Wrap(err, "set finish time of finished visits failed")
+	}
+
+	// Visits checked in before tickets have been introduced are numbered by their id, so open ones stay distinguishable
+	if err := dbConn.Model(&model.Visit{}).
+		Where("ticket = ?", 0).
+		UpdateColumn("ticket", gorm.Expr("id")).
+		Error; err != nil {
+		return nil, errors.Wrap(err, "set ticket of visits failed")
+	}
+
+	// Sessions issued before refresh tokens have been introduced are kept a full refresh token lifetime from now on,
+	// instead of ending all of them on the first cleanup of expired tokens
+	if err := dbConn.Model(&model.Token{}).
+		Where("refresh_expires_at IS NULL").
+		UpdateColumn("refresh_expires_at", time.Now().Add(config.Auth.RefreshTokenLifetime)).
+		Error; err != nil {
+		return nil, errors.Wrap(err, "set refresh expiry of tokens failed")
+	}
+
+	// Users without a local password have been provisioned from the ldap directory before origins have been introduced
+	if err := dbConn.Model(&model.User{}).
+		Where("origin = ? AND password = ?", model.UserOriginLocal, "").
+		UpdateColumn("origin", model.UserOriginLDAP).
+		Error; err != nil {
+		return nil, errors.Wrap(err, "set origin of directory users failed")
+	}
+
+	// Encrypt data stored before encryption at rest has been introduced
+	if err := encryptPlainData(dbConn, cipher); err != nil {
+		return nil, errors.Wrap(err, "encrypt plain data failed")
+	}
+
+	return &db{dbConn, cipher}, nil
 }
 
-// creates necessary database tables
+// creates necessary database tables and adds missing columns
 func createTables(db *gorm.DB) error {
 	tables := []interface{}{
+		&model.APIKey{},
+		&model.AuditEntry{},
 		&model.Client{},
+		&model.Event{},
+		&model.LoginAttempt{},
 		&model.Pager{},
 		&model.Patient{},
 		&model.Token{},
 		&model.User{},
+		&model.Visit{},
 	}
 
 	for _, table := range tables {
@@ -88,5 +158,79 @@ func createTables(db *gorm.DB) error {
 		}
 	}
 
+	// new columns of existing tables must be nullable or have a default value
+	if err := db.AutoMigrate(tables...).Error; err != nil {
+		return errors.Wrap(err, "migrate tables failed")
+	}
+
 	return nil
 }
+
+// creates a visit of every patient from the visit state stored on patients before visits have been split off
+func createVisitsOfPatients(db *gorm.DB) error {
+	finishedAt := "NULL"
+	if db.Dialect().HasColumn("patients", "finished_at") {
+		finishedAt = "finished_at"
+	}
+
+	now := time.Now()
+	err := db.Exec("INSERT INTO visits (patient_id, pager_id, client_id, status, active, created_at, updated_at, finished_at) "+
+		"SELECT id, COALESCE(pager_id, 0), COALESCE(client_id, 0), status, active, ?, ?, "+finishedAt+" FROM patients", now, now).Error
+
+	return errors.Wrap(err, "copy visit state of patients failed")
+}
+
+// encrypts social security numbers and relayed events stored in plain
+func encryptPlainData(db *gorm.DB, cipher *fieldCipher) error {
+	t := &tx{db.Begin(), cipher, time.Now()}
+	if t.Error != nil {
+		return errors.Wrap(t.Error, "begin new transaction failed")
+	}
+
+	var patients []*model.Patient
+	if err := t.Where("(ssn_index IS NULL OR ssn_index = '') AND anonymized_at IS NULL").Find(&patients).Error; err != nil {
+		t.Rollback()
+		return errors.Wrap(err, "select unencrypted patients failed")
+	}
+
+	if err := t.decryptPatients(patients...); err != nil {
+		t.Rollback()
+		return err
+	}
+
+	for _, patient := range patients {
+		if err := t.encryptPatient(patient); err != nil {
+			t.Rollback()
+			return err
+		}
+
+		if err := t.Model(patient).UpdateColumns(map[string]interface{}{
+			"ssn":       patient.EncryptedSocialSecurityNo,
+			"ssn_index": patient.SocialSecurityNoIndex,
+		}).Error; err != nil {
+			t.Rollback()
+			return errors.Wrap(err, "update unencrypted patient failed")
+		}
+	}
+
+	var events []*model.Event
+	if err := t.Where("payload NOT LIKE ?", encryptedPrefix+"%").Find(&events).Error; err != nil {
+		t.Rollback()
+		return errors.Wrap(err, "select unencrypted events failed")
+	}
+
+	for _, event := range events {
+		payload, err := cipher.encrypt(event.Payload)
+		if err != nil {
+			t.Rollback()
+			return errors.Wrap(err, "encrypt event payload failed")
+		}
+
+		if err := t.Model(event).UpdateColumn("payload", payload).Error; err != nil {
+			t.Rollback()
+			return errors.Wrap(err, "update unencrypted event failed")
+		}
+	}
+
+	return t.Commit()
+}
@@ -0,0 +1,57 @@
+package database
+
+import (
+	"time"
+
+	"github.com/pagient/pagient-server/internal/model"
+
+	"github.com/pkg/errors"
+)
+
+// GetEventsAfter returns all events with a higher id than the given one
+func (t *tx) GetEventsAfter(id uint) ([]*model.Event, error) {
+	var events []*model.Event
+	if err := t.Where("id > ?", id).Order("id ASC").Find(&events).Error; err != nil {
+		return nil, errors.Wrap(err, "select events after id failed")
+	}
+
+	// payloads contain patient data, so they are encrypted at rest
+	for _, event := range events {
+		payload, err := t.cipher.decrypt(event.Payload)
+		if err != nil {
+			return nil, errors.Wrap(err, "decrypt event payload failed")
+		}
+
+		event.Payload = payload
+	}
+
+	return events, nil
+}
+
+// AddEvent creates a new event
+func (t *tx) AddEvent(event *model.Event) error {
+	payload, err := t.cipher.encrypt(event.Payload)
+	if err != nil {
+		return errors.Wrap(err, "encrypt event payload failed")
+	}
+
+	// keep the plain payload on the given event
+	encrypted := *event
+	encrypted.Payload = payload
+
+	if err := t.Create(&encrypted).Error; err != nil {
+		return errors.Wrap(err, "create event failed")
+	}
+
+	event.ID = encrypted.ID
+	event.CreatedAt = encrypted.CreatedAt
+
+	return nil
+}
+
+// RemoveEventsBefore deletes all events created before the given time
+func (t *tx) RemoveEventsBefore(before time.Time) error {
+	err := t.Where("created_at < ?", before).Delete(model.Event{}).Error
+
+	return errors.Wrap(err, "delete events before time failed")
+}
@@ -0,0 +1,50 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// gormLogger writes the log entries of gorm to the global logger,
+// the values of sql statements are left out as they may contain personal data
+type gormLogger struct{}
+
+// Print receives the level, the source and the values of a gorm log entry
+func (gormLogger) Print(values ...interface{}) {
+	if len(values) < 2 {
+		return
+	}
+	source := fmt.Sprint(values[1])
+
+	switch values[0] {
+	case "sql":
+		if len(values) < 6 {
+			return
+		}
+
+		duration, _ := values[2].(time.Duration)
+		rows, _ := values[5].(int64)
+
+		log.Debug().
+			Str("source", source).
+			Dur("duration", duration).
+			Str("sql", fmt.Sprint(values[3])).
+			Int64("rows", rows).
+			Msg("sql statement executed")
+	default:
+		// errors are logged along with other messages, e.g. failed statements
+		event := log.Debug()
+		for _, value := range values[2:] {
+			if _, ok := value.(error); ok {
+				event = log.Error()
+				break
+			}
+		}
+
+		event.
+			Str("source", source).
+			Msg(fmt.Sprint(values[2:]...))
+	}
+}
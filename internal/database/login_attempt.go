@@ -0,0 +1,30 @@
+package database
+
+import (
+	"time"
+
+	"github.com/pagient/pagient-server/internal/model"
+
+	"github.com/pkg/errors"
+)
+
+// GetLoginAttempts returns the failed logins since the given time, newest first,
+// optionally restricted to a username
+func (t *tx) GetLoginAttempts(username string, since time.Time) ([]*model.LoginAttempt, error) {
+	var attempts []*model.LoginAttempt
+	query := t.Where("created_at >= ?", since)
+	if username != "" {
+		query = query.Where("username = ?", username)
+	}
+
+	err := query.Order("created_at DESC").Find(&attempts).Error
+
+	return attempts, errors.Wrap(err, "select login attempts failed")
+}
+
+// AddLoginAttempt records a failed login
+func (t *tx) AddLoginAttempt(attempt *model.LoginAttempt) error {
+	err := t.Create(attempt).Error
+
+	return errors.Wrap(err, "create login attempt failed")
+}
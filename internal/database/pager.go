@@ -15,11 +15,11 @@ func (t *tx) GetPagers() ([]*model.Pager, error) {
 	return pagers, errors.Wrap(err, "select all pagers failed")
 }
 
-// GetUnassignedPagers returns all unassigned pagers
+// GetUnassignedPagers returns all pagers not assigned to an open visit
 func (t *tx) GetUnassignedPagers() ([]*model.Pager, error) {
 	var pagers []*model.Pager
-	err := t.Joins("LEFT JOIN patients ON patients.pager_id = pagers.id").
-		Where("patients.id IS NULL").Find(&pagers).Error
+	err := t.Joins("LEFT JOIN visits ON visits.pager_id = pagers.id AND visits.closed_at IS NULL").
+		Where("visits.id IS NULL").Find(&pagers).Error
 
 	return pagers, errors.Wrap(err, "select unassigned pagers failed")
 }
@@ -42,3 +42,21 @@ func (t *tx) AddPager(pager *model.Pager) error {
 
 	return errors.Wrap(err, "create pager failed")
 }
+
+// UpdatePager updates all fields of provided pager
+func (t *tx) UpdatePager(pager *model.Pager) error {
+	// FIXME: handle sql constraint errors
+	err := t.Save(pager).Error
+
+	return errors.Wrap(err, "update pager failed")
+}
+
+// RemovePager deletes the pager from the repository, closed visits keep referring to it
+func (t *tx) RemovePager(pager *model.Pager) error {
+	err := t.Delete(pager).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return &entryNotExistErr{"pager not found"}
+	}
+
+	return errors.Wrap(err, "delete pager failed")
+}
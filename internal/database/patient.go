@@ -1,65 +1,22 @@
 package database
 
 import (
+	"time"
+
 	"github.com/pagient/pagient-server/internal/model"
 
 	"github.com/jinzhu/gorm"
 	"github.com/pkg/errors"
 )
 
-func activeScope(active bool) func(*gorm.DB) *gorm.DB {
-	return func(db *gorm.DB) *gorm.DB {
-		return db.Where("active = ?", active)
-	}
-}
-
-func pagerScope(hasPager bool) func(*gorm.DB) *gorm.DB {
-	return func(db *gorm.DB) *gorm.DB {
-		if hasPager {
-			return db.Where("pager_id != ?", 0)
-		}
-		return db.Where("pager_id = ?", 0)
-	}
-}
-
 // GetPatients lists all patients
 func (t *tx) GetPatients() ([]*model.Patient, error) {
 	var patients []*model.Patient
-	err := t.Find(&patients).Error
-
-	return patients, errors.Wrap(err, "select all patients failed")
-}
-
-// GetPatientsWithPagerByStatus returns all patients having pagers and one of the specified states
-func (t *tx) GetPatientsWithPagerByStatus(statuses ...model.PatientStatus) ([]*model.Patient, error) {
-	var patients []*model.Patient
-	stmt := t.Where("pager_id != 0")
-
-	if len(statuses) > 0 {
-		stmt.Where("status IN (?)", statuses)
+	if err := t.Find(&patients).Error; err != nil {
+		return nil, errors.Wrap(err, "select all patients failed")
 	}
 
-	err := stmt.Find(&patients).Error
-
-	return patients, errors.Wrap(err, "select patients with pagers failed")
-}
-
-// GetPatientsByClient returns all patients from client and activity status (first of slice) and assignment of a pager (second of slice)
-func (t *tx) GetPatientsByClient(clientID uint, optionals ...bool) ([]*model.Patient, error) {
-	var patients []*model.Patient
-	stmt := t.Where("client_id = ?", clientID)
-
-	if len(optionals) > 0 {
-		stmt = stmt.Scopes(activeScope(optionals[0]))
-	}
-
-	if len(optionals) > 1 {
-		stmt = stmt.Scopes(pagerScope(optionals[1]))
-	}
-
-	err := stmt.Find(&patients).Error
-
-	return patients, errors.Wrap(err, "select patients by client, activity and pager assignment failed")
+	return patients, t.decryptPatients(patients...)
 }
 
 // GetPatient returns a patient by ID
@@ -69,12 +26,40 @@ func (t *tx) GetPatient(id uint) (*model.Patient, error) {
 	if gorm.IsRecordNotFoundError(err) {
 		return nil, nil
 	}
+	if err != nil {
+		return nil, errors.Wrap(err, "select patient by id failed")
+	}
 
-	return patient, errors.Wrap(err, "select patient by id failed")
+	return patient, t.decryptPatients(patient)
+}
+
+// GetFinishedPatientsBefore returns all patients not yet anonymized whose visits all ended before the given time,
+// a visit ends when it's finished or closed
+func (t *tx) GetFinishedPatientsBefore(before time.Time) ([]*model.Patient, error) {
+	var patients []*model.Patient
+	err := t.Where("anonymized_at IS NULL").
+		Where("EXISTS (SELECT 1 FROM visits WHERE visits.patient_id = patients.id)").
+		Where("NOT EXISTS (SELECT 1 FROM visits WHERE visits.patient_id = patients.id AND "+
+			"(COALESCE(visits.finished_at, visits.closed_at) IS NULL OR COALESCE(visits.finished_at, visits.closed_at) >= ?))", before).
+		Find(&patients).
+		Error
+	if err != nil {
+		return nil, errors.Wrap(err, "select finished patients failed")
+	}
+
+	return patients, t.decryptPatients(patients...)
 }
 
 // AddPatient stores the values in the repository
 func (t *tx) AddPatient(patient *model.Patient) error {
+	if err := t.encryptPatient(patient); err != nil {
+		return err
+	}
+
+	if err := t.checkUniqueSocialSecurityNo(patient); err != nil {
+		return err
+	}
+
 	// FIXME: handle sql constraint errors
 	err := t.Create(patient).Error
 
@@ -83,6 +68,14 @@ func (t *tx) AddPatient(patient *model.Patient) error {
 
 // UpdatePatient updates the values in the repository
 func (t *tx) UpdatePatient(patient *model.Patient) error {
+	if err := t.encryptPatient(patient); err != nil {
+		return err
+	}
+
+	if err := t.checkUniqueSocialSecurityNo(patient); err != nil {
+		return err
+	}
+
 	// FIXME: handle sql constraint errors
 	err := t.Save(patient).Error
 	if gorm.IsRecordNotFoundError(err) {
@@ -92,21 +85,37 @@ func (t *tx) UpdatePatient(patient *model.Patient) error {
 	return errors.Wrap(err, "update patient failed")
 }
 
-// MarkPatientsInactiveByClient sets active to false for every patient by that client
-func (t *tx) MarkPatientsInactiveByClient(clientID uint) error {
-	err := t.Where(&model.Patient{
-		ClientID: clientID,
-		Active:   true,
-	}).
-		Model(model.Patient{}).
-		Updates(map[string]interface{}{"active": false}).
-		Error
+// AnonymizePatient removes the personal data of a patient
+func (t *tx) AnonymizePatient(patient *model.Patient) error {
+	now := time.Now()
+	patient.Name = ""
+	patient.SocialSecurityNo = ""
+	patient.AnonymizedAt = &now
+
+	// the blind index is removed, so anonymized patients don't collide on it's unique constraint
+	encrypted, err := t.cipher.encrypt(patient.SocialSecurityNo)
+	if err != nil {
+		return errors.Wrap(err, "encrypt social security number failed")
+	}
+	patient.EncryptedSocialSecurityNo = encrypted
+	patient.SocialSecurityNoIndex = ""
 
-	return errors.Wrap(err, "upate patients to inactive by client failed")
+	err = t.Model(patient).UpdateColumns(map[string]interface{}{
+		"name":          patient.Name,
+		"ssn":           patient.EncryptedSocialSecurityNo,
+		"ssn_index":     gorm.Expr("NULL"),
+		"anonymized_at": patient.AnonymizedAt,
+	}).Error
+
+	return errors.Wrap(err, "anonymize patient failed")
 }
 
-// RemovePatient deletes the values from the repository
+// RemovePatient deletes the patient and all of it's visits from the repository
 func (t *tx) RemovePatient(patient *model.Patient) error {
+	if err := t.Where("patient_id = ?", patient.ID).Delete(model.Visit{}).Error; err != nil {
+		return errors.Wrap(err, "delete visits of patient failed")
+	}
+
 	err := t.Delete(patient).Error
 	if gorm.IsRecordNotFoundError(err) {
 		return &entryNotExistErr{"patient not found"}
@@ -115,19 +124,48 @@ func (t *tx) RemovePatient(patient *model.Patient) error {
 	return errors.Wrap(err, "delete patient failed")
 }
 
-// RemovePatientsByClient removes all patients from client and activity status (first of slice) and assignment of a pager (second of slice)
-func (t *tx) RemovePatientsByClient(clientID uint, optionals ...bool) error {
-	stmt := t.Where("client_id = ?", clientID)
+// encryptPatient sets the encrypted social security number and it's blind index
+func (t *tx) encryptPatient(patient *model.Patient) error {
+	encrypted, err := t.cipher.encrypt(patient.SocialSecurityNo)
+	if err != nil {
+		return errors.Wrap(err, "encrypt social security number failed")
+	}
+
+	patient.EncryptedSocialSecurityNo = encrypted
+	patient.SocialSecurityNoIndex = t.cipher.index(patient.SocialSecurityNo)
+
+	return nil
+}
 
-	if len(optionals) > 0 {
-		stmt = stmt.Scopes(activeScope(optionals[0]))
+// decryptPatients sets the plain social security number of loaded patients
+func (t *tx) decryptPatients(patients ...*model.Patient) error {
+	for _, patient := range patients {
+		ssn, err := t.cipher.decrypt(patient.EncryptedSocialSecurityNo)
+		if err != nil {
+			return errors.Wrap(err, "decrypt social security number failed")
+		}
+
+		patient.SocialSecurityNo = ssn
 	}
 
-	if len(optionals) > 1 {
-		stmt = stmt.Scopes(pagerScope(optionals[1]))
+	return nil
+}
+
+// checkUniqueSocialSecurityNo looks up other patients with the same social security number by it's blind index,
+// the encrypted values differ even for equal numbers
+func (t *tx) checkUniqueSocialSecurityNo(patient *model.Patient) error {
+	var count int
+	err := t.Model(&model.Patient{}).
+		Where("ssn_index = ? AND id != ?", patient.SocialSecurityNoIndex, patient.ID).
+		Count(&count).
+		Error
+	if err != nil {
+		return errors.Wrap(err, "count patients by social security number failed")
 	}
 
-	err := stmt.Delete(model.Patient{}).Error
+	if count > 0 {
+		return &entryExistErr{"patient with social security number already exists"}
+	}
 
-	return errors.Wrap(err, "delete patients by client, activity and pager assignment failed")
+	return nil
 }
@@ -1,6 +1,8 @@
 package database
 
 import (
+	"time"
+
 	"github.com/pagient/pagient-server/internal/model"
 
 	"github.com/jinzhu/gorm"
@@ -20,11 +22,37 @@ func (t *tx) GetToken(rawToken string) (*model.Token, error) {
 	return token, errors.Wrap(err, "select token failed")
 }
 
-// GetTokensByUser returns all tokens from a user
+// GetTokenByID returns the token by it's id
+func (t *tx) GetTokenByID(id uint) (*model.Token, error) {
+	token := &model.Token{}
+	err := t.First(token, id).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	}
+
+	return token, errors.Wrap(err, "select token by id failed")
+}
+
+// GetTokenByRefreshHash returns the token by the hash of it's refresh token
+func (t *tx) GetTokenByRefreshHash(hash string) (*model.Token, error) {
+	token := &model.Token{}
+	err := t.Where(&model.Token{
+		RefreshHash: hash,
+	}).First(token).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	}
+
+	return token, errors.Wrap(err, "select token by refresh hash failed")
+}
+
+// GetTokensByUser returns all not rotated tokens from a user
 func (t *tx) GetTokensByUser(username string) ([]*model.Token, error) {
 	var tokens []*model.Token
 	err := t.Joins("JOIN users ON users.id = tokens.user_id").
-		Where("users.username = ?", username).Find(&tokens).Error
+		Where("users.username = ?", username).
+		Where("tokens.rotated = ?", false).
+		Find(&tokens).Error
 
 	return tokens, errors.Wrap(err, "select tokens by user failed")
 }
@@ -36,6 +64,27 @@ func (t *tx) AddToken(token *model.Token) error {
 	return errors.Wrap(err, "create token failed")
 }
 
+// UpdateToken updates a token
+func (t *tx) UpdateToken(token *model.Token) error {
+	err := t.Save(token).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return &entryNotExistErr{"token not found"}
+	}
+
+	return errors.Wrap(err, "update token failed")
+}
+
+// UpdateTokenLastSeen updates only the last access information of a token
+func (t *tx) UpdateTokenLastSeen(token *model.Token) error {
+	err := t.Model(token).UpdateColumns(map[string]interface{}{
+		"last_seen_at": token.LastSeenAt,
+		"ip":           token.IP,
+		"user_agent":   token.UserAgent,
+	}).Error
+
+	return errors.Wrap(err, "update token last seen failed")
+}
+
 // RemoveToken removes a token
 func (t *tx) RemoveToken(token *model.Token) error {
 	err := t.Delete(token).Error
@@ -45,3 +94,18 @@ func (t *tx) RemoveToken(token *model.Token) error {
 
 	return errors.Wrap(err, "delete token failed")
 }
+
+// RemoveTokensByFamily removes all tokens of a session
+func (t *tx) RemoveTokensByFamily(family string) error {
+	err := t.Where("family = ?", family).Delete(model.Token{}).Error
+
+	return errors.Wrap(err, "delete tokens by family failed")
+}
+
+// RemoveExpiredTokens removes all tokens which can't be refreshed anymore
+func (t *tx) RemoveExpiredTokens(now time.Time) error {
+	err := t.Where("refresh_expires_at < ?", now).
+		Delete(model.Token{}).Error
+
+	return errors.Wrap(err, "delete expired tokens failed")
+}
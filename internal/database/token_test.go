@@ -0,0 +1,61 @@
+package database
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pagient/pagient-server/internal/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpen_BackfillRefreshExpiry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pagient-tokens")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	config.General.Secret = "secret"
+	config.DB.Driver = "sqlite3"
+	config.DB.Path = filepath.Join(dir, "pagient.sqlite3")
+	config.Auth.RefreshTokenLifetime = time.Hour
+
+	conn, err := Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// tokens issued before refresh tokens have been introduced have no refresh expiry
+	assert.NoError(t, conn.(*db).Exec("INSERT INTO tokens (raw, user_id) VALUES ('session', 1)").Error)
+	assert.NoError(t, conn.Close())
+
+	conn, err = Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	tx, err := conn.Begin()
+	assert.NoError(t, err)
+	assert.NoError(t, tx.RemoveExpiredTokens(time.Now()))
+
+	token, err := tx.GetToken("session")
+	assert.NoError(t, err)
+	if assert.NotNil(t, token) {
+		assert.WithinDuration(t, time.Now().Add(time.Hour), token.RefreshExpiresAt, time.Minute)
+	}
+	tx.Commit()
+
+	tx, err = conn.Begin()
+	assert.NoError(t, err)
+	assert.NoError(t, tx.RemoveExpiredTokens(time.Now().Add(2*time.Hour)))
+
+	token, err = tx.GetToken("session")
+	assert.NoError(t, err)
+	assert.Nil(t, token)
+	tx.Commit()
+}
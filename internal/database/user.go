@@ -28,6 +28,17 @@ func (t *tx) GetUser(username string) (*model.User, error) {
 	return user, errors.Wrap(err, "select user by username failed")
 }
 
+// GetUserBySubject returns a user by it's subject at the openid connect provider
+func (t *tx) GetUserBySubject(subject string) (*model.User, error) {
+	user := &model.User{}
+	err := t.Where("subject = ?", subject).First(user).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	}
+
+	return user, errors.Wrap(err, "select user by subject failed")
+}
+
 // GetUserByToken returns a user with given token
 func (t *tx) GetUserByToken(rawToken string) (*model.User, error) {
 	user := &model.User{}
@@ -55,3 +66,54 @@ func (t *tx) UpdateUserPassword(user *model.User) error {
 
 	return errors.Wrap(err, "update password failed")
 }
+
+// UpdateUserRole updates only the role of provided user
+func (t *tx) UpdateUserRole(user *model.User) error {
+	err := t.Model(user).UpdateColumn("role", user.Role).Error
+
+	return errors.Wrap(err, "update role failed")
+}
+
+// UpdateUserLogin updates only the failed logins and lockout of provided user
+func (t *tx) UpdateUserLogin(user *model.User) error {
+	err := t.Model(user).UpdateColumns(map[string]interface{}{
+		"failed_logins": user.FailedLogins,
+		"locked_until":  user.LockedUntil,
+	}).Error
+
+	return errors.Wrap(err, "update user login failed")
+}
+
+// IncrementUserFailedLogins counts a failed login of provided user in the database,
+// so concurrent failed logins don't overwrite each other
+func (t *tx) IncrementUserFailedLogins(user *model.User) error {
+	err := t.Model(user).UpdateColumn("failed_logins", gorm.Expr("failed_logins + ?", 1)).Error
+
+	return errors.Wrap(err, "increment failed logins failed")
+}
+
+// UpdateUser updates all fields of provided user
+func (t *tx) UpdateUser(user *model.User) error {
+	err := t.Save(user).Error
+
+	return errors.Wrap(err, "update user failed")
+}
+
+// RemoveUser deletes the user including it's tokens and api keys from the repository,
+// login attempts are kept for auditing
+func (t *tx) RemoveUser(user *model.User) error {
+	if err := t.Where("user_id = ?", user.ID).Delete(model.Token{}).Error; err != nil {
+		return errors.Wrap(err, "delete tokens of user failed")
+	}
+
+	if err := t.Where("user_id = ?", user.ID).Delete(model.APIKey{}).Error; err != nil {
+		return errors.Wrap(err, "delete api keys of user failed")
+	}
+
+	err := t.Delete(user).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return &entryNotExistErr{"user not found"}
+	}
+
+	return errors.Wrap(err, "delete user failed")
+}
@@ -0,0 +1,229 @@
+package database
+
+import (
+	"time"
+
+	"github.com/pagient/pagient-server/internal/model"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+func openScope(db *gorm.DB) *gorm.DB {
+	return db.Where("closed_at IS NULL")
+}
+
+func activeScope(active bool) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("active = ?", active)
+	}
+}
+
+func pagerScope(hasPager bool) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if hasPager {
+			return db.Where("pager_id != ?", 0)
+		}
+		return db.Where("pager_id = ?", 0)
+	}
+}
+
+// GetVisits lists all open visits
+func (t *tx) GetVisits() ([]*model.Visit, error) {
+	var visits []*model.Visit
+	if err := t.Scopes(openScope).Preload("Patient").Find(&visits).Error; err != nil {
+		return nil, errors.Wrap(err, "select all open visits failed")
+	}
+
+	return visits, t.decryptVisits(visits...)
+}
+
+// GetVisitsWithPagerByStatus returns all open visits having pagers and one of the specified states
+func (t *tx) GetVisitsWithPagerByStatus(statuses ...model.VisitStatus) ([]*model.Visit, error) {
+	var visits []*model.Visit
+	stmt := t.Scopes(openScope).Where("pager_id != 0")
+
+	if len(statuses) > 0 {
+		stmt = stmt.Where("status IN (?)", statuses)
+	}
+
+	if err := stmt.Preload("Patient").Find(&visits).Error; err != nil {
+		return nil, errors.Wrap(err, "select visits with pagers failed")
+	}
+
+	return visits, t.decryptVisits(visits...)
+}
+
+// CountVisitsByStatus returns the count of open visits per status
+func (t *tx) CountVisitsByStatus() (map[model.VisitStatus]int, error) {
+	var rows []struct {
+		Status model.VisitStatus
+		Count  int
+	}
+	err := t.Model(&model.Visit{}).
+		Scopes(openScope).
+		Select("status, COUNT(*) AS count").
+		Group("status").
+		Scan(&rows).
+		Error
+	if err != nil {
+		return nil, errors.Wrap(err, "count open visits by status failed")
+	}
+
+	counts := make(map[model.VisitStatus]int, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+
+	return counts, nil
+}
+
+// GetVisitsByClient returns all open visits at client by activity status (first of slice) and assignment of a pager (second of slice)
+func (t *tx) GetVisitsByClient(clientID uint, optionals ...bool) ([]*model.Visit, error) {
+	var visits []*model.Visit
+	stmt := t.Scopes(openScope).Where("client_id = ?", clientID)
+
+	if len(optionals) > 0 {
+		stmt = stmt.Scopes(activeScope(optionals[0]))
+	}
+
+	if len(optionals) > 1 {
+		stmt = stmt.Scopes(pagerScope(optionals[1]))
+	}
+
+	if err := stmt.Preload("Patient").Find(&visits).Error; err != nil {
+		return nil, errors.Wrap(err, "select visits by client, activity and pager assignment failed")
+	}
+
+	return visits, t.decryptVisits(visits...)
+}
+
+// GetVisitsByPatient returns all open visits of a patient
+func (t *tx) GetVisitsByPatient(patientID uint) ([]*model.Visit, error) {
+	var visits []*model.Visit
+	err := t.Scopes(openScope).
+		Where("patient_id = ?", patientID).
+		Preload("Patient").
+		Find(&visits).
+		Error
+	if err != nil {
+		return nil, errors.Wrap(err, "select visits by patient failed")
+	}
+
+	return visits, t.decryptVisits(visits...)
+}
+
+// GetVisitsFirstCalledAfter returns all visits whose patients have been called the first time after the given time
+func (t *tx) GetVisitsFirstCalledAfter(after time.Time) ([]*model.Visit, error) {
+	var visits []*model.Visit
+	if err := t.Where("first_called_at > ?", after).Find(&visits).Error; err != nil {
+		return nil, errors.Wrap(err, "select visits first called after time failed")
+	}
+
+	return visits, nil
+}
+
+// GetVisitsCheckedInBetween returns all visits, open and closed, checked in within the given time range
+func (t *tx) GetVisitsCheckedInBetween(from, to time.Time) ([]*model.Visit, error) {
+	var visits []*model.Visit
+	err := t.Where("created_at >= ? AND created_at < ?", from, to).
+		Order("created_at ASC").
+		Preload("Client").
+		Find(&visits).
+		Error
+	if err != nil {
+		return nil, errors.Wrap(err, "select visits checked in between failed")
+	}
+
+	return visits, nil
+}
+
+// GetVisit returns a visit by ID
+func (t *tx) GetVisit(id uint) (*model.Visit, error) {
+	visit := &model.Visit{}
+	err := t.Preload("Patient").Find(visit, id).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "select visit by id failed")
+	}
+
+	return visit, t.decryptVisits(visit)
+}
+
+// NextVisitTicket returns the next ticket of the visits checked in since given time,
+// tickets of visits that are still open are never handed out again
+func (t *tx) NextVisitTicket(since time.Time) (uint, error) {
+	var row struct {
+		Ticket uint
+	}
+	err := t.Model(&model.Visit{}).
+		Select("COALESCE(MAX(ticket), 0) AS ticket").
+		Where("created_at >= ? OR closed_at IS NULL", since).
+		Scan(&row).
+		Error
+	if err != nil {
+		return 0, errors.Wrap(err, "select last ticket failed")
+	}
+
+	return row.Ticket + 1, nil
+}
+
+// AddVisit stores the values in the repository
+func (t *tx) AddVisit(visit *model.Visit) error {
+	err := t.Create(visit).Error
+
+	return errors.Wrap(err, "create visit failed")
+}
+
+// UpdateVisit updates the values in the repository
+func (t *tx) UpdateVisit(visit *model.Visit) error {
+	err := t.Save(visit).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return &entryNotExistErr{"visit not found"}
+	}
+
+	return errors.Wrap(err, "update visit failed")
+}
+
+// MarkVisitsInactiveByClient sets active to false for every open visit at that client
+func (t *tx) MarkVisitsInactiveByClient(clientID uint) error {
+	err := t.Scopes(openScope).
+		Where("client_id = ? AND active = ?", clientID, true).
+		Model(model.Visit{}).
+		Updates(map[string]interface{}{"active": false}).
+		Error
+
+	return errors.Wrap(err, "update visits to inactive by client failed")
+}
+
+// CloseVisitsByClient closes all open visits at client by activity status (first of slice) and assignment of a pager (second of slice)
+func (t *tx) CloseVisitsByClient(clientID uint, optionals ...bool) error {
+	stmt := t.Scopes(openScope).Where("client_id = ?", clientID)
+
+	if len(optionals) > 0 {
+		stmt = stmt.Scopes(activeScope(optionals[0]))
+	}
+
+	if len(optionals) > 1 {
+		stmt = stmt.Scopes(pagerScope(optionals[1]))
+	}
+
+	err := stmt.Model(model.Visit{}).
+		Updates(map[string]interface{}{"closed_at": time.Now()}).
+		Error
+
+	return errors.Wrap(err, "close visits by client, activity and pager assignment failed")
+}
+
+// decryptVisits sets the plain social security number of the patients of loaded visits
+func (t *tx) decryptVisits(visits ...*model.Visit) error {
+	for _, visit := range visits {
+		if err := t.decryptPatients(&visit.Patient); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pagient/pagient-server/internal/config"
+	"github.com/pagient/pagient-server/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTx_NextVisitTicket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pagient-visits")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	config.General.Secret = "secret"
+	config.DB.Driver = "sqlite3"
+	config.DB.Path = filepath.Join(dir, "pagient.sqlite3")
+
+	conn, err := Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	yesterday := today.Add(-12 * time.Hour)
+
+	tx, err := conn.Begin()
+	assert.NoError(t, err)
+
+	ticket, err := tx.NextVisitTicket(today)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), ticket)
+
+	// closed visits of the previous day don't continue the sequence
+	assert.NoError(t, tx.AddVisit(&model.Visit{PatientID: 1, Ticket: 41, CreatedAt: yesterday, ClosedAt: &yesterday}))
+	ticket, err = tx.NextVisitTicket(today)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), ticket)
+
+	assert.NoError(t, tx.AddVisit(&model.Visit{PatientID: 2, Ticket: 1, CreatedAt: now}))
+	ticket, err = tx.NextVisitTicket(today)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), ticket)
+
+	// open visits of the previous day may still be displayed, so their tickets aren't handed out again
+	assert.NoError(t, tx.AddVisit(&model.Visit{PatientID: 3, Ticket: 42, CreatedAt: yesterday}))
+	ticket, err = tx.NextVisitTicket(today)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(43), ticket)
+
+	tx.Commit()
+}
@@ -0,0 +1,56 @@
+package directory
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pagient/pagient-server/internal/model"
+)
+
+// GroupMapping maps the group memberships of directory users to role and client
+type GroupMapping struct {
+	AdminGroups []string
+	// UserGroups restricts the login to their members and admins, everyone may login if empty
+	UserGroups []string
+	// ClientGroups maps client names to the group of their users
+	ClientGroups map[string]string
+}
+
+// Map returns role and client name of a user by it's groups
+// and whether the user is allowed to login at all
+func (m *GroupMapping) Map(groups []string) (model.UserRole, string, bool) {
+	role := model.UserRoleUser
+	if memberOf(groups, m.AdminGroups) {
+		role = model.UserRoleAdmin
+	} else if len(m.UserGroups) > 0 && !memberOf(groups, m.UserGroups) {
+		return "", "", false
+	}
+
+	// sort client names, so a user in multiple client groups always gets the same client
+	clients := make([]string, 0, len(m.ClientGroups))
+	for client := range m.ClientGroups {
+		clients = append(clients, client)
+	}
+	sort.Strings(clients)
+
+	for _, client := range clients {
+		if memberOf(groups, []string{m.ClientGroups[client]}) {
+			return role, client, true
+		}
+	}
+
+	return role, "", true
+}
+
+// memberOf returns whether one of the groups is in the wanted groups, groups are compared case insensitive
+func memberOf(groups, wanted []string) bool {
+	for _, group := range groups {
+		for _, w := range wanted {
+			if strings.EqualFold(strings.TrimSpace(group), strings.TrimSpace(w)) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,80 @@
+package directory
+
+import (
+	"testing"
+
+	"github.com/pagient/pagient-server/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupMapping_Map(t *testing.T) {
+	adminGroups := []string{"cn=admins,ou=groups,dc=example,dc=local"}
+	clientGroups := map[string]string{
+		"Room 1": "cn=room1,ou=groups,dc=example,dc=local",
+		"Room 2": "cn=room2,ou=groups,dc=example,dc=local",
+	}
+
+	tests := map[string]struct {
+		userGroups []string
+		groups     []string
+		role       model.UserRole
+		client     string
+		allowed    bool
+	}{
+		"user without groups": {
+			groups:  nil,
+			role:    model.UserRoleUser,
+			client:  "",
+			allowed: true,
+		},
+		"admin by group ignoring case": {
+			groups:  []string{"CN=Admins,OU=Groups,DC=example,DC=local"},
+			role:    model.UserRoleAdmin,
+			client:  "",
+			allowed: true,
+		},
+		"client by group": {
+			groups:  []string{"cn=room2,ou=groups,dc=example,dc=local"},
+			role:    model.UserRoleUser,
+			client:  "Room 2",
+			allowed: true,
+		},
+		"first client of multiple client groups": {
+			groups:  []string{"cn=room2,ou=groups,dc=example,dc=local", "cn=room1,ou=groups,dc=example,dc=local"},
+			role:    model.UserRoleUser,
+			client:  "Room 1",
+			allowed: true,
+		},
+		"user not in user groups": {
+			userGroups: []string{"cn=staff,ou=groups,dc=example,dc=local"},
+			groups:     []string{"cn=room1,ou=groups,dc=example,dc=local"},
+			allowed:    false,
+		},
+		"admin not in user groups": {
+			userGroups: []string{"cn=staff,ou=groups,dc=example,dc=local"},
+			groups:     []string{"cn=admins,ou=groups,dc=example,dc=local"},
+			role:       model.UserRoleAdmin,
+			client:     "",
+			allowed:    true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		mapping := &GroupMapping{
+			AdminGroups:  adminGroups,
+			UserGroups:   test.userGroups,
+			ClientGroups: clientGroups,
+		}
+
+		role, client, allowed := mapping.Map(test.groups)
+
+		assert.Equal(t, test.allowed, allowed)
+		if test.allowed {
+			assert.Equal(t, test.role, role)
+			assert.Equal(t, test.client, client)
+		}
+	}
+}
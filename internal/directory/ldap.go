@@ -0,0 +1,148 @@
+package directory
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+
+	"github.com/pagient/pagient-server/internal/config"
+	"github.com/pagient/pagient-server/internal/model"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/ldap.v3"
+)
+
+// LDAP authenticates users by binding against an ldap server or active directory
+type LDAP struct{}
+
+// NewLDAP creates an ldap authenticator configured by the ldap config section
+func NewLDAP() *LDAP {
+	return &LDAP{}
+}
+
+// Authenticate searches the user in the directory and binds with it's password,
+// role and client are mapped from the user's groups
+func (l *LDAP) Authenticate(username, password string) (*model.User, error) {
+	// an empty password would be accepted as unauthenticated bind
+	if username == "" || password == "" {
+		return nil, nil
+	}
+
+	conn, err := l.dial()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer conn.Close()
+
+	if config.LDAP.BindDN != "" {
+		if err := conn.Bind(config.LDAP.BindDN, config.LDAP.BindPassword); err != nil {
+			return nil, errors.Wrap(err, "bind search account failed")
+		}
+	}
+
+	result, err := conn.Search(ldap.NewSearchRequest(
+		config.LDAP.BaseDN,
+		ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases,
+		2,
+		int(config.LDAP.Timeout.Seconds()),
+		false,
+		fmt.Sprintf(config.LDAP.UserFilter, ldap.EscapeFilter(username)),
+		[]string{"dn", config.LDAP.GroupAttribute},
+		nil,
+	))
+	if err != nil {
+		return nil, errors.Wrap(err, "search user failed")
+	}
+
+	if len(result.Entries) != 1 {
+		if len(result.Entries) > 1 {
+			log.Warn().
+				Str("user", username).
+				Msg("ambiguous ldap user filter, multiple users found")
+		}
+
+		return nil, nil
+	}
+	entry := result.Entries[0]
+
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, nil
+		}
+
+		return nil, errors.Wrap(err, "bind user failed")
+	}
+
+	mapping := &GroupMapping{
+		AdminGroups:  config.LDAP.AdminGroups,
+		UserGroups:   config.LDAP.UserGroups,
+		ClientGroups: config.LDAP.ClientGroups,
+	}
+
+	role, client, ok := mapping.Map(entry.GetAttributeValues(config.LDAP.GroupAttribute))
+	if !ok {
+		log.Warn().
+			Str("user", username).
+			Msg("ldap user isn't member of any allowed group")
+
+		return nil, nil
+	}
+
+	return &model.User{
+		Username: username,
+		Role:     role,
+		Client:   model.Client{Name: client},
+		Origin:   model.UserOriginLDAP,
+	}, nil
+}
+
+// dial connects to the ldap server and upgrades the connection to tls if configured
+func (l *LDAP) dial() (*ldap.Conn, error) {
+	u, err := url.Parse(config.LDAP.URL)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse ldap url failed")
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         u.Hostname(),
+		InsecureSkipVerify: config.LDAP.InsecureSkipVerify,
+	}
+	dialer := &net.Dialer{Timeout: config.LDAP.Timeout}
+
+	var c net.Conn
+	switch u.Scheme {
+	case "ldap":
+		c, err = dialer.Dial("tcp", hostPort(u, "389"))
+	case "ldaps":
+		c, err = tls.DialWithDialer(dialer, "tcp", hostPort(u, "636"), tlsConfig)
+	default:
+		return nil, errors.Errorf("unsupported ldap url scheme %s", u.Scheme)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "connect to ldap server failed")
+	}
+
+	conn := ldap.NewConn(c, u.Scheme == "ldaps")
+	conn.Start()
+	conn.SetTimeout(config.LDAP.Timeout)
+
+	if config.LDAP.StartTLS && u.Scheme == "ldap" {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, errors.Wrap(err, "start tls failed")
+		}
+	}
+
+	return conn, nil
+}
+
+func hostPort(u *url.URL, defaultPort string) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+
+	return net.JoinHostPort(u.Hostname(), defaultPort)
+}
@@ -0,0 +1,157 @@
+package directory
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"sync"
+
+	"github.com/pagient/pagient-server/internal/config"
+	"github.com/pagient/pagient-server/internal/model"
+
+	"github.com/coreos/go-oidc"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/oauth2"
+)
+
+// OIDC authenticates users through the authorization code flow with pkce of an openid connect provider
+type OIDC struct {
+	mu       sync.Mutex
+	oauth2   *oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+// NewOIDC creates an openid connect authenticator configured by the oidc config section,
+// the provider gets discovered on first use, so it doesn't have to be reachable on startup
+func NewOIDC() *OIDC {
+	return &OIDC{}
+}
+
+// AuthCodeURL returns the url to login at the provider,
+// the code verifier and nonce have to be passed again to exchange the code
+func (o *OIDC) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	oauth2Config, _, err := o.discover(ctx)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	challenge := sha256.Sum256([]byte(codeVerifier))
+
+	return oauth2Config.AuthCodeURL(state,
+		oidc.Nonce(nonce),
+		oauth2.SetAuthURLParam("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:])),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+	), nil
+}
+
+// Exchange exchanges the authorization code for an id token and returns the user described by it's claims,
+// or nil if the user isn't allowed to login
+func (o *OIDC) Exchange(ctx context.Context, code, nonce, codeVerifier string) (*model.User, error) {
+	oauth2Config, verifier, err := o.discover(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	token, err := oauth2Config.Exchange(ctx, code, oauth2.SetAuthURLParam("code_verifier", codeVerifier))
+	if err != nil {
+		return nil, errors.Wrap(err, "exchange authorization code failed")
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, errors.New("id token missing in token response")
+	}
+
+	idToken, err := verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, errors.Wrap(err, "verify id token failed")
+	}
+
+	if idToken.Nonce != nonce {
+		return nil, errors.New("id token nonce mismatch")
+	}
+
+	var claims map[string]interface{}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, errors.Wrap(err, "decode id token claims failed")
+	}
+
+	return mapClaims(claims)
+}
+
+// discover loads the provider configuration once it's reachable
+func (o *OIDC) discover(ctx context.Context) (*oauth2.Config, *oidc.IDTokenVerifier, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.oauth2 != nil {
+		return o.oauth2, o.verifier, nil
+	}
+
+	provider, err := oidc.NewProvider(ctx, config.OIDC.Issuer)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "discover openid connect provider failed")
+	}
+
+	o.oauth2 = &oauth2.Config{
+		ClientID:     config.OIDC.ClientID,
+		ClientSecret: config.OIDC.ClientSecret,
+		RedirectURL:  config.OIDC.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       config.OIDC.Scopes,
+	}
+	o.verifier = provider.Verifier(&oidc.Config{ClientID: config.OIDC.ClientID})
+
+	return o.oauth2, o.verifier, nil
+}
+
+// mapClaims returns the user described by the id token claims
+func mapClaims(claims map[string]interface{}) (*model.User, error) {
+	// the subject identifies the user, the username may be chosen freely at some providers
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return nil, errors.New("id token claim sub missing")
+	}
+
+	username, _ := claims[config.OIDC.UsernameClaim].(string)
+	if username == "" {
+		return nil, errors.Errorf("id token claim %s missing", config.OIDC.UsernameClaim)
+	}
+
+	// groups are either a list or a single value
+	var groups []string
+	switch value := claims[config.OIDC.GroupsClaim].(type) {
+	case string:
+		groups = []string{value}
+	case []interface{}:
+		for _, group := range value {
+			if g, ok := group.(string); ok {
+				groups = append(groups, g)
+			}
+		}
+	}
+
+	mapping := &GroupMapping{
+		AdminGroups:  config.OIDC.AdminGroups,
+		UserGroups:   config.OIDC.UserGroups,
+		ClientGroups: config.OIDC.ClientGroups,
+	}
+
+	role, client, ok := mapping.Map(groups)
+	if !ok {
+		log.Warn().
+			Str("user", username).
+			Msg("openid connect user isn't member of any allowed group")
+
+		return nil, nil
+	}
+
+	return &model.User{
+		Username: username,
+		Role:     role,
+		Client:   model.Client{Name: client},
+		Origin:   model.UserOriginOIDC,
+		Subject:  &subject,
+	}, nil
+}
@@ -0,0 +1,80 @@
+package directory
+
+import (
+	"testing"
+
+	"github.com/pagient/pagient-server/internal/config"
+	"github.com/pagient/pagient-server/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func subject(s string) *string {
+	return &s
+}
+
+func TestMapClaims(t *testing.T) {
+	config.OIDC.UsernameClaim = "preferred_username"
+	config.OIDC.GroupsClaim = "groups"
+	config.OIDC.AdminGroups = []string{"admins"}
+	config.OIDC.UserGroups = []string{"staff"}
+	config.OIDC.ClientGroups = map[string]string{"Room 1": "room1"}
+
+	tests := map[string]struct {
+		claims  map[string]interface{}
+		user    *model.User
+		invalid bool
+	}{
+		"subject missing": {
+			claims:  map[string]interface{}{"preferred_username": "jdoe", "groups": []interface{}{"staff"}},
+			invalid: true,
+		},
+		"username missing": {
+			claims:  map[string]interface{}{"sub": "1", "groups": []interface{}{"staff"}},
+			invalid: true,
+		},
+		"user with client": {
+			claims: map[string]interface{}{
+				"sub":                "1",
+				"preferred_username": "jdoe",
+				"groups":             []interface{}{"staff", "room1"},
+			},
+			user: &model.User{
+				Username: "jdoe",
+				Role:     model.UserRoleUser,
+				Client:   model.Client{Name: "Room 1"},
+				Origin:   model.UserOriginOIDC,
+				Subject:  subject("1"),
+			},
+		},
+		"admin by single group": {
+			claims: map[string]interface{}{
+				"sub":                "2",
+				"preferred_username": "admin",
+				"groups":             "admins",
+			},
+			user: &model.User{
+				Username: "admin",
+				Role:     model.UserRoleAdmin,
+				Origin:   model.UserOriginOIDC,
+				Subject:  subject("2"),
+			},
+		},
+		"user not allowed": {
+			claims: map[string]interface{}{
+				"sub":                "3",
+				"preferred_username": "guest",
+			},
+			user: nil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		user, err := mapClaims(test.claims)
+
+		assert.Equal(t, test.invalid, err != nil)
+		assert.Equal(t, test.user, user)
+	}
+}
@@ -0,0 +1,37 @@
+package health
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// HTTPCheck returns a check whether the server at url responds at all,
+// error statuses count as reachable, e.g. a gateway rejecting unauthenticated requests
+// the url is looked up on every check, so it may change at runtime
+func HTTPCheck(url func() string, timeout time.Duration) Check {
+	client := &http.Client{Timeout: timeout}
+
+	return func() error {
+		resp, err := client.Get(url())
+		if err != nil {
+			return errors.Wrap(err, "request failed")
+		}
+		resp.Body.Close()
+
+		return nil
+	}
+}
+
+// FreshnessCheck returns a check whether the time returned by last is within the age returned by maxAge,
+// e.g. the last run of a background loop
+func FreshnessCheck(last func() time.Time, maxAge func() time.Duration) Check {
+	return func() error {
+		if age, limit := time.Since(last()), maxAge(); age > limit {
+			return errors.Errorf("last run %s ago exceeds %s", age.Round(time.Second), limit)
+		}
+
+		return nil
+	}
+}
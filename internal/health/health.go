@@ -0,0 +1,115 @@
+package health
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Status of a component
+type Status string
+
+const (
+	// StatusUp marks a component that works
+	StatusUp Status = "up"
+	// StatusDown marks a component that failed it's check
+	StatusDown Status = "down"
+)
+
+// Check returns an error if the component isn't healthy
+type Check func() error
+
+// ComponentReport holds the result of checking a single component
+type ComponentReport struct {
+	Status   Status
+	Error    error
+	Duration time.Duration
+}
+
+// Report holds the results of checking all components,
+// it's status is down as soon as one component is down
+type Report struct {
+	Status     Status
+	Components map[string]*ComponentReport
+}
+
+type component struct {
+	name  string
+	check Check
+}
+
+// Checker checks the health of the components the server depends on
+type Checker struct {
+	timeout    time.Duration
+	components []*component
+}
+
+// NewChecker returns a checker aborting checks of single components after timeout
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// Add registers the check of a component, it must be called before the checker is run
+func (c *Checker) Add(name string, check Check) {
+	c.components = append(c.components, &component{name, check})
+}
+
+// Run checks all components concurrently
+func (c *Checker) Run() *Report {
+	report := &Report{
+		Status:     StatusUp,
+		Components: make(map[string]*ComponentReport, len(c.components)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, comp := range c.components {
+		wg.Add(1)
+		go func(comp *component) {
+			defer wg.Done()
+
+			result := c.run(comp.check)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			report.Components[comp.name] = result
+			if result.Status == StatusDown {
+				report.Status = StatusDown
+			}
+		}(comp)
+	}
+	wg.Wait()
+
+	return report
+}
+
+// run runs a single check, checks exceeding the timeout are reported down while they keep running in the background
+func (c *Checker) run(check Check) *ComponentReport {
+	start := time.Now()
+
+	// buffered, so the goroutine of a timed out check can finish
+	done := make(chan error, 1)
+	go func() {
+		done <- check()
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-time.After(c.timeout):
+		err = errors.Errorf("check timed out after %s", c.timeout)
+	}
+
+	result := &ComponentReport{
+		Status:   StatusUp,
+		Duration: time.Since(start),
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err
+	}
+
+	return result
+}
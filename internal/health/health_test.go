@@ -0,0 +1,99 @@
+package health
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecker_Run(t *testing.T) {
+	failing := errors.New("connection refused")
+
+	tests := map[string]struct {
+		checks     map[string]Check
+		status     Status
+		components map[string]Status
+	}{
+		"all components up": {
+			checks: map[string]Check{
+				"db":     func() error { return nil },
+				"bridge": func() error { return nil },
+			},
+			status:     StatusUp,
+			components: map[string]Status{"db": StatusUp, "bridge": StatusUp},
+		},
+		"failing component": {
+			checks: map[string]Check{
+				"db":     func() error { return nil },
+				"bridge": func() error { return failing },
+			},
+			status:     StatusDown,
+			components: map[string]Status{"db": StatusUp, "bridge": StatusDown},
+		},
+		"hanging component": {
+			checks: map[string]Check{
+				"db": func() error {
+					time.Sleep(time.Second)
+					return nil
+				},
+			},
+			status:     StatusDown,
+			components: map[string]Status{"db": StatusDown},
+		},
+		"no components": {
+			checks:     map[string]Check{},
+			status:     StatusUp,
+			components: map[string]Status{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		checker := NewChecker(50 * time.Millisecond)
+		for component, check := range test.checks {
+			checker.Add(component, check)
+		}
+
+		report := checker.Run()
+		assert.Equal(t, test.status, report.Status)
+
+		components := make(map[string]Status, len(report.Components))
+		for component, result := range report.Components {
+			components[component] = result.Status
+			if result.Status == StatusDown {
+				assert.Error(t, result.Error)
+			}
+		}
+		assert.Equal(t, test.components, components)
+	}
+}
+
+func TestFreshnessCheck(t *testing.T) {
+	tests := map[string]struct {
+		last  time.Time
+		fresh bool
+	}{
+		"recent run": {
+			last:  time.Now().Add(-time.Second),
+			fresh: true,
+		},
+		"stale run": {
+			last:  time.Now().Add(-time.Hour),
+			fresh: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		check := FreshnessCheck(func() time.Time { return test.last }, func() time.Duration { return time.Minute })
+		if test.fresh {
+			assert.NoError(t, check())
+		} else {
+			assert.Error(t, check())
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package limiter
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter restricts the count of hits per key within a fixed time window
+type Limiter struct {
+	limit  int
+	window time.Duration
+
+	mu        sync.Mutex
+	windows   map[string]*window
+	lastPrune time.Time
+}
+
+type window struct {
+	start time.Time
+	hits  int
+}
+
+// New creates a limiter allowing limit hits per key within the window,
+// a limit of zero allows all hits
+func New(limit int, every time.Duration) *Limiter {
+	return &Limiter{
+		limit:   limit,
+		window:  every,
+		windows: make(map[string]*window),
+	}
+}
+
+// Allow records a hit for the key and reports whether the key is still within the limit
+func (l *Limiter) Allow(key string) bool {
+	if l.limit <= 0 {
+		return true
+	}
+
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.prune(now)
+
+	w, ok := l.windows[key]
+	if !ok || now.Sub(w.start) >= l.window {
+		w = &window{start: now}
+		l.windows[key] = w
+	}
+
+	w.hits++
+	return w.hits <= l.limit
+}
+
+// Reset forgets all hits of the key
+func (l *Limiter) Reset(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.windows, key)
+}
+
+// Window returns the duration after which hits are forgotten
+func (l *Limiter) Window() time.Duration {
+	return l.window
+}
+
+// prune removes elapsed windows at most once per window, so memory doesn't grow with every key ever seen
+func (l *Limiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < l.window {
+		return
+	}
+
+	for key, w := range l.windows {
+		if now.Sub(w.start) >= l.window {
+			delete(l.windows, key)
+		}
+	}
+	l.lastPrune = now
+}
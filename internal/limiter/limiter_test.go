@@ -0,0 +1,74 @@
+package limiter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	tests := map[string]struct {
+		limit   int
+		window  time.Duration
+		hits    int
+		wait    time.Duration
+		allowed bool
+	}{
+		"allow hits within limit": {
+			limit:   3,
+			window:  time.Minute,
+			hits:    3,
+			allowed: true,
+		},
+		"deny hits over limit": {
+			limit:   3,
+			window:  time.Minute,
+			hits:    4,
+			allowed: false,
+		},
+		"allow hits again after window elapsed": {
+			limit:   3,
+			window:  10 * time.Millisecond,
+			hits:    4,
+			wait:    20 * time.Millisecond,
+			allowed: true,
+		},
+		"allow all hits without limit": {
+			limit:   0,
+			window:  time.Minute,
+			hits:    100,
+			allowed: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		l := New(test.limit, test.window)
+
+		var allowed bool
+		for i := 0; i < test.hits; i++ {
+			allowed = l.Allow("key")
+		}
+
+		if test.wait > 0 {
+			time.Sleep(test.wait)
+			allowed = l.Allow("key")
+		}
+
+		assert.Equal(t, test.allowed, allowed)
+		// other keys are limited separately
+		assert.True(t, l.Allow("other"))
+	}
+}
+
+func TestLimiter_Reset(t *testing.T) {
+	l := New(1, time.Minute)
+
+	assert.True(t, l.Allow("key"))
+	assert.False(t, l.Allow("key"))
+
+	l.Reset("key")
+	assert.True(t, l.Allow("key"))
+}
@@ -1,47 +1,137 @@
 package logger
 
 import (
+	"io"
 	"os"
-	"path"
+	"time"
 
 	"github.com/pagient/pagient-server/internal/config"
 
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
-var logFile *os.File
+var (
+	closers []io.Closer
+	// stopRotation stops rotating the log file by interval
+	stopRotation chan struct{}
+)
 
 // Init sets up global logger using configuration
 func Init() error {
-	level, err := zerolog.ParseLevel(config.Log.Level)
-	if err != nil {
-		return errors.New("parse log level failed")
+	if err := SetLevel(config.LogLevel()); err != nil {
+		return err
 	}
-	zerolog.SetGlobalLevel(level)
 
-	logFile, err = os.OpenFile(path.Join(config.General.Root, "pagient.log"), os.O_CREATE|os.O_APPEND|os.O_RDWR, 0666)
-	if err != nil {
-		return errors.New("logfile could not be opened")
-	}
+	var writers []io.Writer
+	for _, output := range config.Log.Outputs {
+		writer, err := open(output)
+		if err != nil {
+			Close()
+			return errors.Wrapf(err, "open log output %s failed", output)
+		}
 
-	if config.Log.Pretty {
-		log.Logger = log.Output(
-			zerolog.ConsoleWriter{
-				Out:     logFile,
+		// syslog keeps the level of entries, so it isn't formatted for consoles
+		if config.Log.Pretty && output != "syslog" {
+			writer = zerolog.ConsoleWriter{
+				Out:     writer,
 				NoColor: !config.Log.Colored,
-			},
-		)
+			}
+		}
 
-		return nil
+		writers = append(writers, writer)
 	}
 
-	log.Logger = log.Output(logFile)
+	log.Logger = log.Output(zerolog.MultiLevelWriter(writers...))
 	return nil
 }
 
-// Close closes the log file
+func open(output string) (io.Writer, error) {
+	switch output {
+	case "stdout":
+		return os.Stdout, nil
+	case "stderr":
+		return os.Stderr, nil
+	case "file":
+		return openFile(), nil
+	case "syslog":
+		return openSyslog()
+	default:
+		return nil, errors.Errorf("unknown log output %s", output)
+	}
+}
+
+// openFile returns the log file rotated by size and optionally by interval
+func openFile() io.Writer {
+	file := &lumberjack.Logger{
+		Filename:   config.Log.File,
+		MaxSize:    config.Log.MaxSize,
+		MaxAge:     days(config.Log.MaxAge),
+		MaxBackups: config.Log.MaxBackups,
+		LocalTime:  true,
+		Compress:   config.Log.Compress,
+	}
+	closers = append(closers, file)
+
+	if config.Log.RotateInterval > 0 {
+		stopRotation = make(chan struct{})
+		go rotate(file, config.Log.RotateInterval, stopRotation)
+	}
+
+	return file
+}
+
+func rotate(file *lumberjack.Logger, every time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := file.Rotate(); err != nil {
+				log.Error().
+					Err(err).
+					Msg("rotate log file failed")
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// days rounds up a duration to whole days, as rotated files are kept by days
+func days(d time.Duration) int {
+	day := 24 * time.Hour
+	return int((d + day - 1) / day)
+}
+
+// SetLevel sets the minimum level of log entries of the global logger
+func SetLevel(value string) error {
+	level, err := zerolog.ParseLevel(value)
+	if err != nil {
+		return errors.New("parse log level failed")
+	}
+	zerolog.SetGlobalLevel(level)
+
+	return nil
+}
+
+// Close closes the log file and the connection to syslog
 func Close() error {
-	return logFile.Close()
+	if stopRotation != nil {
+		close(stopRotation)
+		stopRotation = nil
+	}
+
+	var err error
+	for _, closer := range closers {
+		if closeErr := closer.Close(); closeErr != nil {
+			err = closeErr
+		}
+	}
+	closers = nil
+
+	return err
 }
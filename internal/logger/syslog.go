@@ -0,0 +1,24 @@
+//go:build !windows
+// +build !windows
+
+package logger
+
+import (
+	"log/syslog"
+
+	"github.com/pagient/pagient-server/internal/config"
+
+	"github.com/rs/zerolog"
+)
+
+// openSyslog connects to the local syslog or the configured remote one,
+// entries are written with the syslog priority of their level
+func openSyslog() (zerolog.LevelWriter, error) {
+	writer, err := syslog.Dial(config.Log.SyslogNetwork, config.Log.SyslogAddress, syslog.LOG_INFO|syslog.LOG_DAEMON, config.Log.SyslogTag)
+	if err != nil {
+		return nil, err
+	}
+	closers = append(closers, writer)
+
+	return zerolog.SyslogLevelWriter(writer), nil
+}
@@ -0,0 +1,11 @@
+package logger
+
+import (
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+// openSyslog fails as there is no syslog on windows
+func openSyslog() (zerolog.LevelWriter, error) {
+	return nil, errors.New("syslog is not supported on windows")
+}
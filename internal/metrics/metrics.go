@@ -0,0 +1,113 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "pagient"
+
+var (
+	// HTTPRequestDuration observes the latency of http requests by method, route pattern and status code
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of http requests by method, route and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// WebsocketConnections counts the currently connected websocket clients
+	WebsocketConnections = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "websocket",
+		Name:      "connections",
+		Help:      "Currently connected websocket clients.",
+	})
+
+	// WebsocketDroppedMessages counts messages dropped because of a full transmit queue of the hub
+	WebsocketDroppedMessages = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "websocket",
+		Name:      "dropped_messages_total",
+		Help:      "Messages dropped because of a full transmit queue of the websocket hub.",
+	})
+
+	// CallerPollDuration observes the duration of a poll of the practitioner software
+	CallerPollDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "caller",
+		Name:      "poll_duration_seconds",
+		Help:      "Duration of polling the practitioner software and calling pagers.",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	// CallerPollErrors counts failed polls by the step they failed in
+	CallerPollErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "caller",
+		Name:      "poll_errors_total",
+		Help:      "Failed polls of the practitioner software by the step they failed in.",
+	}, []string{"step"})
+
+	// CallerLastSuccess holds the time of the last successful poll
+	CallerLastSuccess = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "caller",
+		Name:      "last_success_timestamp_seconds",
+		Help:      "Unix time of the last successful poll of the practitioner software.",
+	})
+
+	// BridgeQueryDuration observes the latency of queries to the database of the practitioner software
+	BridgeQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "bridge",
+		Name:      "query_duration_seconds",
+		Help:      "Latency of queries to the database of the practitioner software.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"query"})
+
+	// PagerCalls counts calls sent to the pager gateway by result
+	PagerCalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "pager",
+		Name:      "calls_total",
+		Help:      "Calls sent to the pager gateway by result, either success or failure.",
+	}, []string{"result"})
+
+	// DBTransactionDuration observes the duration of database transactions by how they ended
+	DBTransactionDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "transaction_duration_seconds",
+		Help:      "Duration of database transactions by result, either commit or rollback.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"result"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		HTTPRequestDuration,
+		WebsocketConnections,
+		WebsocketDroppedMessages,
+		CallerPollDuration,
+		CallerPollErrors,
+		CallerLastSuccess,
+		BridgeQueryDuration,
+		PagerCalls,
+		DBTransactionDuration,
+	)
+}
+
+// Since returns the seconds elapsed since start, the unit of all durations observed
+func Since(start time.Time) float64 {
+	return time.Since(start).Seconds()
+}
+
+// Handler returns the http handler exposing all registered metrics
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"github.com/pagient/pagient-server/internal/model"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog/log"
+)
+
+// VisitCounter counts the open visits by status
+type VisitCounter interface {
+	CountVisitsByStatus() (map[model.VisitStatus]int, error)
+}
+
+// visitCollector queries the count of open visits on every scrape,
+// so the counts can't drift from the database
+type visitCollector struct {
+	counter VisitCounter
+	desc    *prometheus.Desc
+}
+
+// NewVisitCollector returns a collector of the open visits of patients by status
+func NewVisitCollector(counter VisitCounter) prometheus.Collector {
+	return &visitCollector{
+		counter: counter,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "visits"),
+			"Open visits of patients by status.",
+			[]string{"status"},
+			nil,
+		),
+	}
+}
+
+// Describe sends the description of the visit metric
+func (c *visitCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect sends the count of open visits per status
+func (c *visitCollector) Collect(ch chan<- prometheus.Metric) {
+	counts, err := c.counter.CountVisitsByStatus()
+	if err != nil {
+		log.Error().
+			Err(err).
+			Msg("count visits by status failed")
+
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+
+	// every status is exposed, so missing statuses don't look like missing data
+	for _, status := range []model.VisitStatus{
+		model.VisitStatusPending,
+		model.VisitStatusCall,
+		model.VisitStatusCalled,
+		model.VisitStatusFinished,
+	} {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(counts[status]), string(status))
+	}
+}
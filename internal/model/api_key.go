@@ -0,0 +1,139 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-ozzo/ozzo-validation"
+	"github.com/pkg/errors"
+)
+
+// APIKeyScope holds a permission of an APIKey
+type APIKeyScope string
+
+// enumerates all scopes an api key can have
+const (
+	APIKeyScopeReadPatients   APIKeyScope = "patients:read"
+	APIKeyScopeCreatePatients APIKeyScope = "patients:create"
+	APIKeyScopeCallPagers     APIKeyScope = "pagers:call"
+	APIKeyScopeDisplay        APIKeyScope = "display"
+)
+
+// APIKey struct authenticates integrations on behalf of a user
+type APIKey struct {
+	ID   uint   `gorm:"primary_key"`
+	Name string `gorm:"not null"`
+	// Key is the plain api key, only the hash gets stored
+	Key string `gorm:"-"`
+	// Prefix of the plain key to recognize it
+	Prefix string
+	Hash   string `gorm:"not null;unique_index"`
+	// Scopes separated by commas
+	Scopes     string `gorm:"not null"`
+	User       User   `gorm:"save_associations:false"`
+	UserID     uint   `gorm:"index"`
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+	ExpiresAt  *time.Time
+}
+
+// ScopeList returns the scopes of the api key
+func (key *APIKey) ScopeList() []APIKeyScope {
+	var scopes []APIKeyScope
+	for _, scope := range strings.Split(key.Scopes, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, APIKeyScope(scope))
+		}
+	}
+
+	return scopes
+}
+
+// SetScopes replaces the scopes of the api key
+func (key *APIKey) SetScopes(scopes []APIKeyScope) {
+	list := make([]string, len(scopes))
+	for i, scope := range scopes {
+		list[i] = string(scope)
+	}
+
+	key.Scopes = strings.Join(list, ",")
+}
+
+// HasScope returns whether the api key has one of the given scopes
+func (key *APIKey) HasScope(scopes ...APIKeyScope) bool {
+	for _, have := range key.ScopeList() {
+		for _, want := range scopes {
+			if have == want {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// IsDisplayOnly returns whether the api key has no other scope than showing the waiting-room display
+func (key *APIKey) IsDisplayOnly() bool {
+	scopes := key.ScopeList()
+	for _, scope := range scopes {
+		if scope != APIKeyScopeDisplay {
+			return false
+		}
+	}
+
+	return len(scopes) > 0
+}
+
+// Session returns the identifier of websocket connections authenticated by the api key
+func (key *APIKey) Session() string {
+	return "key-" + strconv.FormatUint(uint64(key.ID), 10)
+}
+
+// IsExpired returns whether the api key can't be used anymore
+func (key *APIKey) IsExpired(now time.Time) bool {
+	return key.ExpiresAt != nil && key.ExpiresAt.Before(now)
+}
+
+// Validate validates the api key
+func (key *APIKey) Validate() error {
+	scopes := key.ScopeList()
+	if len(scopes) == 0 {
+		return &modelValidationErr{"scopes: cannot be blank."}
+	}
+
+	for _, scope := range scopes {
+		if err := validation.Validate(scope,
+			validation.In(APIKeyScopeReadPatients, APIKeyScopeCreatePatients, APIKeyScopeCallPagers, APIKeyScopeDisplay),
+		); err != nil {
+			if e, ok := err.(validation.InternalError); ok {
+				return errors.Wrap(e, "internal validation error occurred")
+			}
+
+			return &modelValidationErr{"scopes: " + string(scope) + " " + err.Error()}
+		}
+	}
+
+	if err := validation.ValidateStruct(key,
+		validation.Field(&key.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&key.UserID, validation.Required),
+	); err != nil {
+		if e, ok := err.(validation.InternalError); ok {
+			return errors.Wrap(e, "internal validation error occurred")
+		}
+
+		return &modelValidationErr{err.Error()}
+	}
+
+	return nil
+}
+
+// ValidateOwner validates the scopes of the api key against the role of it's owner,
+// displays may only show the waiting-room display as they aren't allowed to work with patients
+func (key *APIKey) ValidateOwner(owner *User) error {
+	if owner.Role == UserRoleDisplay && !key.IsDisplayOnly() {
+		return &modelValidationErr{"scopes: displays may only use the display scope."}
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKey_IsDisplayOnly(t *testing.T) {
+	tests := map[string]struct {
+		scopes  string
+		display bool
+	}{
+		"display key": {
+			scopes:  "display",
+			display: true,
+		},
+		"integration key": {
+			scopes:  "patients:read,pagers:call",
+			display: false,
+		},
+		"display key with further scopes": {
+			scopes:  "display,patients:read",
+			display: false,
+		},
+		"key without scopes": {
+			scopes:  "",
+			display: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		key := &APIKey{Scopes: test.scopes}
+		assert.Equal(t, test.display, key.IsDisplayOnly())
+	}
+}
+
+func TestAPIKey_ValidateOwner(t *testing.T) {
+	tests := map[string]struct {
+		scopes string
+		role   UserRole
+		valid  bool
+	}{
+		"display key of display": {
+			scopes: "display",
+			role:   UserRoleDisplay,
+			valid:  true,
+		},
+		"patient key of display": {
+			scopes: "patients:read",
+			role:   UserRoleDisplay,
+			valid:  false,
+		},
+		"display key with further scopes of display": {
+			scopes: "display,pagers:call",
+			role:   UserRoleDisplay,
+			valid:  false,
+		},
+		"patient key of user": {
+			scopes: "patients:read,patients:create,pagers:call",
+			role:   UserRoleUser,
+			valid:  true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		key := &APIKey{Scopes: test.scopes}
+		err := key.ValidateOwner(&User{Role: test.role})
+
+		assert.Equal(t, test.valid, err == nil)
+		if err != nil {
+			assert.True(t, IsValidationErr(err))
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// AuditAction holds the kind of an audited operation
+type AuditAction string
+
+// enumerates all audited operations
+const (
+	AuditActionPurgePatients AuditAction = "purge_patients"
+)
+
+// AuditEntry struct records an operation on personal data
+type AuditEntry struct {
+	ID uint `gorm:"primary_key"`
+	// Actor is the user or background job performing the operation
+	Actor     string
+	Action    AuditAction `gorm:"index"`
+	Details   string
+	CreatedAt time.Time `gorm:"index"`
+}
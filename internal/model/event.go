@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// Event struct
+type Event struct {
+	ID        uint   `gorm:"primary_key"`
+	Origin    string `gorm:"not null"`
+	Type      string `gorm:"not null"`
+	Payload   string `gorm:"not null"`
+	CreatedAt time.Time
+}
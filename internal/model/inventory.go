@@ -0,0 +1,33 @@
+package model
+
+import "fmt"
+
+// InventoryKind names a section of an inventory
+type InventoryKind string
+
+// enumerates all sections of an inventory
+const (
+	InventoryKindClients InventoryKind = "clients"
+	InventoryKindPagers  InventoryKind = "pagers"
+	InventoryKindUsers   InventoryKind = "users"
+)
+
+// Inventory holds the clients, pagers and users of a site, e.g. to import them at once.
+// Users refer to their client by it's name, as clients get their ids on import.
+type Inventory struct {
+	Clients []*Client
+	Pagers  []*Pager
+	Users   []*User
+}
+
+// ImportError reports why a row of an import is invalid, rows are counted from 1 per section
+type ImportError struct {
+	Kind    InventoryKind
+	Row     int
+	Message string
+}
+
+// Error implements the error interface
+func (err *ImportError) Error() string {
+	return fmt.Sprintf("%s row %d: %s", err.Kind, err.Row, err.Message)
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// LoginFailure holds the reason of a failed login
+type LoginFailure string
+
+// enumerates all reasons of failed logins
+const (
+	LoginFailureUnknownUser   LoginFailure = "unknown_user"
+	LoginFailureWrongPassword LoginFailure = "wrong_password"
+	LoginFailureLocked        LoginFailure = "locked"
+)
+
+// LoginAttempt struct records a failed login
+type LoginAttempt struct {
+	ID        uint   `gorm:"primary_key"`
+	Username  string `gorm:"index"`
+	IP        string
+	Reason    LoginFailure
+	CreatedAt time.Time `gorm:"index"`
+}
@@ -0,0 +1,9 @@
+package model
+
+// QueuePosition holds the position of a patient in the queue of a room of the practitioner software
+type QueuePosition struct {
+	PatientID uint
+	Room      string
+	// Position starts at 1 for the patient examined next
+	Position uint
+}
@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// ReportGroup defines by what visits are aggregated in reports
+type ReportGroup string
+
+// enumerates all report groups
+const (
+	// ReportGroupDay aggregates visits by the day of their check-in
+	ReportGroupDay ReportGroup = "day"
+	// ReportGroupHour aggregates visits by the hour of day of their check-in
+	ReportGroupHour ReportGroup = "hour"
+	// ReportGroupRoom aggregates visits by room
+	ReportGroupRoom ReportGroup = "room"
+	// ReportGroupClient aggregates visits by the client they've been checked in at
+	ReportGroupClient ReportGroup = "client"
+)
+
+// ReportRow holds the aggregated statistics of the visits of one group
+type ReportRow struct {
+	Group  string
+	Visits int
+	// Called is the count of visits whose patients have been called
+	Called int
+	// wait times from check-in until the first call
+	WaitAverage time.Duration
+	WaitMedian  time.Duration
+	WaitP90     time.Duration
+	// arrival times from the first call until the pager has been returned
+	ArrivalAverage time.Duration
+	ArrivalMedian  time.Duration
+	ArrivalP90     time.Duration
+	// NoShows is the count of called visits closed without the pager being returned
+	NoShows    int
+	NoShowRate float64
+	// PagerTime is the total time pagers have been assigned to the visits
+	PagerTime time.Duration
+	// PagerUtilization is the share of the available pager time, the pagers have been assigned
+	PagerUtilization float64
+}
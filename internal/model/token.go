@@ -1,9 +1,36 @@
 package model
 
+import (
+	"strconv"
+	"time"
+)
+
 // Token struct
 type Token struct {
 	ID     uint   `gorm:"primary_key"`
 	Raw    string `gorm:"not null;unique"`
 	User   User   `gorm:"save_associations:false"`
 	UserID uint
+	// Family groups all tokens of a session created by refreshing
+	Family string `gorm:"index"`
+	// Refresh is the plain refresh token, only the hash gets stored
+	Refresh          string `gorm:"-"`
+	RefreshHash      string `gorm:"unique_index"`
+	Rotated          bool   `sql:"default:false"`
+	ExpiresAt        time.Time
+	RefreshExpiresAt time.Time
+	CreatedAt        time.Time
+	LastSeenAt       time.Time
+	IP               string
+	UserAgent        string
+}
+
+// Session returns the identifier of the session the token belongs to
+func (token *Token) Session() string {
+	if token.Family != "" {
+		return token.Family
+	}
+
+	// tokens created before sessions could be refreshed
+	return strconv.FormatUint(uint64(token.ID), 10)
 }
@@ -2,22 +2,107 @@ package model
 
 import (
 	"regexp"
+	"time"
 
 	"github.com/go-ozzo/ozzo-validation"
 	"github.com/pkg/errors"
 )
 
+// UserRole holds the permissions of the User
+type UserRole string
+
+// enumerates all roles a user can have
+const (
+	// UserRoleAdmin is for users managing other users and their sessions
+	UserRoleAdmin UserRole = "admin"
+	// UserRoleUser is for users working with patients
+	UserRoleUser UserRole = "user"
+	// UserRoleDisplay is for waiting-room displays only showing patients
+	UserRoleDisplay UserRole = "display"
+)
+
+// UserOrigin holds where the User is managed
+type UserOrigin string
+
+// enumerates all origins a user can have
+const (
+	// UserOriginLocal is for users managed by pagient, logging in with their local password
+	UserOriginLocal UserOrigin = "local"
+	// UserOriginLDAP is for users provisioned from the ldap directory
+	UserOriginLDAP UserOrigin = "ldap"
+	// UserOriginOIDC is for users provisioned from the openid connect provider
+	UserOriginOIDC UserOrigin = "oidc"
+)
+
 // User struct
 type User struct {
-	ID       uint   `gorm:"primary_key"`
-	Username string `gorm:"not null;unique"`
-	Password string `gorm:"not null"`
-	Client   Client `gorm:"save_associations:false"`
-	ClientID uint   `gorm:"unique"`
+	ID       uint     `gorm:"primary_key"`
+	Username string   `gorm:"not null;unique"`
+	Password string   `gorm:"not null"`
+	Role     UserRole `sql:"default:'user'"`
+	Client   Client   `gorm:"save_associations:false"`
+	// ClientID is nil for users without client, so they don't collide on the unique constraint
+	ClientID *uint `gorm:"unique"`
+	// Origin tells whether role and client are managed locally or by a directory
+	Origin UserOrigin `sql:"default:'local'"`
+	// Subject identifies the user at the openid connect provider,
+	// it's nil for other users so they don't collide on the unique constraint
+	Subject *string `gorm:"unique"`
+	// FailedLogins counts the failed logins since the last successful one
+	FailedLogins int `sql:"default:0"`
+	LockedUntil  time.Time
+}
+
+// PasswordPolicy defines the requirements for user passwords
+type PasswordPolicy struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+}
+
+// rules returns the validation rules of the password policy
+func (policy *PasswordPolicy) rules() []validation.Rule {
+	minLength := 1
+	if policy != nil && policy.MinLength > minLength {
+		minLength = policy.MinLength
+	}
+
+	rules := []validation.Rule{validation.Required, validation.Length(minLength, 100)}
+	if policy == nil {
+		return rules
+	}
+
+	if policy.RequireUpper {
+		rules = append(rules, validation.Match(regexp.MustCompile("[[:upper:]]")).Error("must contain an upper case letter"))
+	}
+	if policy.RequireLower {
+		rules = append(rules, validation.Match(regexp.MustCompile("[[:lower:]]")).Error("must contain a lower case letter"))
+	}
+	if policy.RequireDigit {
+		rules = append(rules, validation.Match(regexp.MustCompile("[[:digit:]]")).Error("must contain a digit"))
+	}
+	if policy.RequireSymbol {
+		rules = append(rules, validation.Match(regexp.MustCompile("[^[:alnum:]]")).Error("must contain a symbol"))
+	}
+
+	return rules
+}
+
+// IsProvisionedBy returns whether the user is managed by the directory of given origin,
+// only those users may be provisioned by it so local accounts can't be taken over
+func (user *User) IsProvisionedBy(origin UserOrigin) bool {
+	return user.Origin == origin && origin != UserOriginLocal
+}
+
+// IsLocked returns whether the user is locked out because of too many failed logins
+func (user *User) IsLocked(now time.Time) bool {
+	return user.LockedUntil.After(now)
 }
 
-// Validate validates the user
-func (user *User) Validate(clients []*Client) error {
+// Validate validates the user, the password has to follow the given policy
+func (user *User) Validate(clients []*Client, policy *PasswordPolicy) error {
 	// convert pager slice to generic interface slice
 	clientIDs := make([]interface{}, len(clients))
 	for i, client := range clients {
@@ -26,7 +111,73 @@ func (user *User) Validate(clients []*Client) error {
 
 	if err := validation.ValidateStruct(user,
 		validation.Field(&user.Username, validation.Required, validation.Match(regexp.MustCompile("[[:word:]]+$"))),
-		validation.Field(&user.Password, validation.Required, validation.Length(1, 100)),
+		validation.Field(&user.Password, policy.rules()...),
+		validation.Field(&user.Role, validation.In(UserRoleAdmin, UserRoleUser, UserRoleDisplay)),
+		validation.Field(&user.ClientID, validation.In(clientIDs...)),
+	); err != nil {
+		if e, ok := err.(validation.InternalError); ok {
+			return errors.Wrap(e, "internal validation error occured")
+		}
+
+		return &modelValidationErr{err.Error()}
+	}
+
+	return nil
+}
+
+// ValidateImport validates imported users, the client is checked by name by the importer.
+// Users may be imported without password, as passwords are left out on export.
+func (user *User) ValidateImport(policy *PasswordPolicy) error {
+	var passwordRules []validation.Rule
+	if user.Password != "" {
+		passwordRules = policy.rules()
+	}
+
+	if err := validation.ValidateStruct(user,
+		validation.Field(&user.Username, validation.Required, validation.Match(regexp.MustCompile("[[:word:]]+$"))),
+		validation.Field(&user.Password, passwordRules...),
+		validation.Field(&user.Role, validation.In(UserRoleAdmin, UserRoleUser, UserRoleDisplay)),
+		validation.Field(&user.Origin, validation.In(UserOriginLocal, UserOriginLDAP, UserOriginOIDC)),
+	); err != nil {
+		if e, ok := err.(validation.InternalError); ok {
+			return errors.Wrap(e, "internal validation error occured")
+		}
+
+		return &modelValidationErr{err.Error()}
+	}
+
+	return nil
+}
+
+// ValidatePasswordChange validates the user requirements when changing password,
+// the new password has to follow the given policy
+func (user *User) ValidatePasswordChange(policy *PasswordPolicy) error {
+	if err := validation.ValidateStruct(user,
+		validation.Field(&user.Username, validation.Required),
+		validation.Field(&user.Password, policy.rules()...),
+	); err != nil {
+		if e, ok := err.(validation.InternalError); ok {
+			return errors.Wrap(e, "internal validation error occured")
+		}
+
+		return &modelValidationErr{err.Error()}
+	}
+
+	return nil
+}
+
+// ValidateUpdate validates the user requirements when changing role and client,
+// the password is left as is
+func (user *User) ValidateUpdate(clients []*Client) error {
+	// convert client slice to generic interface slice
+	clientIDs := make([]interface{}, len(clients))
+	for i, client := range clients {
+		clientIDs[i] = client.ID
+	}
+
+	if err := validation.ValidateStruct(user,
+		validation.Field(&user.Username, validation.Required),
+		validation.Field(&user.Role, validation.Required, validation.In(UserRoleAdmin, UserRoleUser, UserRoleDisplay)),
 		validation.Field(&user.ClientID, validation.In(clientIDs...)),
 	); err != nil {
 		if e, ok := err.(validation.InternalError); ok {
@@ -39,11 +190,27 @@ func (user *User) Validate(clients []*Client) error {
 	return nil
 }
 
-// ValidatePasswordChange validates the user requirements when changing password
-func (user *User) ValidatePasswordChange() error {
+// ValidateRoleChange validates the user requirements when changing role
+func (user *User) ValidateRoleChange() error {
+	if err := validation.ValidateStruct(user,
+		validation.Field(&user.Username, validation.Required),
+		validation.Field(&user.Role, validation.Required, validation.In(UserRoleAdmin, UserRoleUser, UserRoleDisplay)),
+	); err != nil {
+		if e, ok := err.(validation.InternalError); ok {
+			return errors.Wrap(e, "internal validation error occured")
+		}
+
+		return &modelValidationErr{err.Error()}
+	}
+
+	return nil
+}
+
+// ValidateLink validates the user requirements when linking it to the openid connect provider
+func (user *User) ValidateLink() error {
 	if err := validation.ValidateStruct(user,
-		validation.Field(&user.ID, validation.Required),
-		validation.Field(&user.Password, validation.Required, validation.Length(1, 100)),
+		validation.Field(&user.Username, validation.Required),
+		validation.Field(&user.Subject, validation.Required),
 	); err != nil {
 		if e, ok := err.(validation.InternalError); ok {
 			return errors.Wrap(e, "internal validation error occured")
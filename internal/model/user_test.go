@@ -0,0 +1,149 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUser_Validate(t *testing.T) {
+	policy := &PasswordPolicy{
+		MinLength:     8,
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+	}
+
+	tests := map[string]struct {
+		password string
+		policy   *PasswordPolicy
+		valid    bool
+	}{
+		"password without policy": {
+			password: "a",
+			policy:   nil,
+			valid:    true,
+		},
+		"password too short": {
+			password: "Ab1!",
+			policy:   policy,
+			valid:    false,
+		},
+		"password without upper case letter": {
+			password: "abcdef1!",
+			policy:   policy,
+			valid:    false,
+		},
+		"password without lower case letter": {
+			password: "ABCDEF1!",
+			policy:   policy,
+			valid:    false,
+		},
+		"password without digit": {
+			password: "Abcdefg!",
+			policy:   policy,
+			valid:    false,
+		},
+		"password without symbol": {
+			password: "Abcdefg1",
+			policy:   policy,
+			valid:    false,
+		},
+		"password following policy": {
+			password: "Abcdef1!",
+			policy:   policy,
+			valid:    true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		user := &User{
+			Username: "user",
+			Password: test.password,
+		}
+
+		err := user.Validate(nil, test.policy)
+		assert.Equal(t, !test.valid, IsValidationErr(err))
+		if test.valid {
+			assert.NoError(t, err)
+		}
+	}
+}
+
+func TestUser_ValidateImport(t *testing.T) {
+	tests := map[string]struct {
+		user  *User
+		valid bool
+	}{
+		"user with password": {
+			user:  &User{Username: "user", Password: "secret", Role: UserRoleUser, Origin: UserOriginLocal},
+			valid: true,
+		},
+		"user without password": {
+			user:  &User{Username: "user", Role: UserRoleUser, Origin: UserOriginLocal},
+			valid: true,
+		},
+		"user with too short password": {
+			user:  &User{Username: "user", Password: "short", Role: UserRoleUser, Origin: UserOriginLocal},
+			valid: false,
+		},
+		"unknown origin": {
+			user:  &User{Username: "user", Role: UserRoleUser, Origin: "kerberos"},
+			valid: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		err := test.user.ValidateImport(&PasswordPolicy{MinLength: 6})
+		assert.Equal(t, !test.valid, IsValidationErr(err))
+		if test.valid {
+			assert.NoError(t, err)
+		}
+	}
+}
+
+func TestUser_ValidateUpdate(t *testing.T) {
+	clientID := uint(1)
+	unknownClientID := uint(2)
+
+	tests := map[string]struct {
+		user  *User
+		valid bool
+	}{
+		"assign existing client": {
+			user:  &User{Username: "user", Role: UserRoleUser, ClientID: &clientID},
+			valid: true,
+		},
+		"assign no client": {
+			user:  &User{Username: "user", Role: UserRoleDisplay},
+			valid: true,
+		},
+		"assign unknown client": {
+			user:  &User{Username: "user", Role: UserRoleUser, ClientID: &unknownClientID},
+			valid: false,
+		},
+		"unknown role": {
+			user:  &User{Username: "user", Role: "guest"},
+			valid: false,
+		},
+		"missing username": {
+			user:  &User{Role: UserRoleUser},
+			valid: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		err := test.user.ValidateUpdate([]*Client{{ID: clientID, Name: "client"}})
+		assert.Equal(t, !test.valid, IsValidationErr(err))
+		if test.valid {
+			assert.NoError(t, err)
+		}
+	}
+}
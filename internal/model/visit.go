@@ -0,0 +1,129 @@
+package model
+
+import (
+	"time"
+
+	"github.com/go-ozzo/ozzo-validation"
+	"github.com/pkg/errors"
+)
+
+// VisitStatus hold the state of the Visit
+type VisitStatus string
+
+// enumerates all states a visit can be in
+const (
+	// VisitStatusPending is for when the patient is pending
+	VisitStatusPending VisitStatus = "pending"
+	// VisitStatusCall is for when the patient's pager gets called
+	VisitStatusCall VisitStatus = "call"
+	// VisitStatusCalled is for when the patient's pager has been called
+	VisitStatusCalled VisitStatus = "called"
+	// VisitStatusFinished is for when the patient is finished with his medical examination
+	VisitStatusFinished VisitStatus = "finished"
+)
+
+// Visit struct holds the state of a single visit of a patient,
+// a patient may visit multiple times, but only has one open visit at a time
+type Visit struct {
+	ID        uint    `gorm:"primary_key"`
+	Patient   Patient `gorm:"save_associations:false"`
+	PatientID uint    `gorm:"not null;index"`
+	Pager     Pager   `gorm:"save_associations:false"`
+	PagerID   uint
+	Client    Client `gorm:"save_associations:false"`
+	ClientID  uint
+	// Ticket numbers the visits of a day, waiting-room displays show it instead of personal data
+	Ticket uint `gorm:"not null" sql:"default:0"`
+	// Room the patient waits in or is examined in
+	Room   string
+	Status VisitStatus `gorm:"not null" sql:"default:\"pending\""`
+	Active bool        `gorm:"not null" sql:"default:false"`
+	// CreatedAt is the time of the check-in
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	// PagerAssignedAt is set when a pager gets assigned to the visit
+	PagerAssignedAt *time.Time
+	// PagerReturnedAt is set when the pager gets removed from the visit
+	PagerReturnedAt *time.Time
+	// ArrivedAt is set when the pager gets returned after a call, the patient has arrived then
+	ArrivedAt *time.Time
+	// FirstCalledAt is set when the patient's pager gets called the first time, the wait time ends then
+	FirstCalledAt *time.Time
+	// CalledAt is set whenever the patient's pager gets called
+	CalledAt *time.Time
+	// RecalledAt is set when the patient's pager gets called again
+	RecalledAt *time.Time
+	// QueuePosition of the patient in the queue of the room, 0 if not queued
+	QueuePosition uint
+	// EstimatedCallAt is predicted from the queue position and the service times of the room
+	EstimatedCallAt *time.Time
+	// FinishedAt is set when the visit's status changes to finished, the retention period starts then
+	FinishedAt *time.Time
+	// ClosedAt is set when the visit is removed from the queue
+	ClosedAt *time.Time `gorm:"index"`
+}
+
+// IsDisplayed returns whether the visit is shown on waiting-room displays, that is while it's pager is called
+func (visit *Visit) IsDisplayed() bool {
+	if visit.ClosedAt != nil || visit.PagerID == 0 {
+		return false
+	}
+
+	return visit.Status == VisitStatusCall || visit.Status == VisitStatusCalled
+}
+
+// Validate validates the visit
+func (visit *Visit) Validate(pagers []*Pager) error {
+	// convert pager slice to generic interface slice
+	pagerIDs := make([]interface{}, len(pagers))
+	for i, pager := range pagers {
+		pagerIDs[i] = pager.ID
+	}
+
+	if err := validation.ValidateStruct(visit,
+		validation.Field(&visit.PatientID, validation.Required),
+		validation.Field(&visit.PagerID, validation.In(pagerIDs...)),
+		validation.Field(&visit.Room, validation.Length(0, 50)),
+		validation.Field(&visit.Status, validation.In(VisitStatusPending, VisitStatusCall, VisitStatusCalled, VisitStatusFinished)),
+	); err != nil {
+		if e, ok := err.(validation.InternalError); ok {
+			return errors.Wrap(e, "internal validation error occured")
+		}
+
+		return &modelValidationErr{err.Error()}
+	}
+
+	return nil
+}
+
+// WaitTime returns how long the patient has waited until the first call or until now if not yet called
+func (visit *Visit) WaitTime(now time.Time) time.Duration {
+	if visit.FirstCalledAt != nil {
+		now = *visit.FirstCalledAt
+	}
+
+	if now.Before(visit.CreatedAt) {
+		return 0
+	}
+
+	return now.Sub(visit.CreatedAt)
+}
+
+// EstimatedWaitTime returns how much longer the patient likely waits until the first call,
+// predicted by the queue position or given the average wait time otherwise.
+// False is returned if the patient has already been called
+func (visit *Visit) EstimatedWaitTime(average time.Duration, now time.Time) (time.Duration, bool) {
+	if visit.FirstCalledAt != nil || visit.Status == VisitStatusFinished {
+		return 0, false
+	}
+
+	remaining := average - visit.WaitTime(now)
+	if visit.EstimatedCallAt != nil {
+		remaining = visit.EstimatedCallAt.Sub(now)
+	}
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return remaining, true
+}
@@ -0,0 +1,97 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVisit_EstimatedWaitTime(t *testing.T) {
+	now := time.Now()
+	firstCalledAt := now.Add(-5 * time.Minute)
+	estimatedCallAt := now.Add(8 * time.Minute)
+
+	tests := map[string]struct {
+		visit     *Visit
+		average   time.Duration
+		waitTime  time.Duration
+		estimated time.Duration
+		ok        bool
+	}{
+		"waiting patient": {
+			visit:     &Visit{CreatedAt: now.Add(-10 * time.Minute), Status: VisitStatusPending},
+			average:   15 * time.Minute,
+			waitTime:  10 * time.Minute,
+			estimated: 5 * time.Minute,
+			ok:        true,
+		},
+		"patient waiting longer than average": {
+			visit:     &Visit{CreatedAt: now.Add(-20 * time.Minute), Status: VisitStatusPending},
+			average:   15 * time.Minute,
+			waitTime:  20 * time.Minute,
+			estimated: 0,
+			ok:        true,
+		},
+		"queued patient": {
+			visit:     &Visit{CreatedAt: now.Add(-10 * time.Minute), EstimatedCallAt: &estimatedCallAt, Status: VisitStatusPending},
+			average:   15 * time.Minute,
+			waitTime:  10 * time.Minute,
+			estimated: 8 * time.Minute,
+			ok:        true,
+		},
+		"called patient": {
+			visit:     &Visit{CreatedAt: now.Add(-20 * time.Minute), FirstCalledAt: &firstCalledAt, Status: VisitStatusCalled},
+			average:   15 * time.Minute,
+			waitTime:  15 * time.Minute,
+			estimated: 0,
+			ok:        false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		assert.Equal(t, test.waitTime, test.visit.WaitTime(now))
+
+		estimated, ok := test.visit.EstimatedWaitTime(test.average, now)
+		assert.Equal(t, test.ok, ok)
+		assert.Equal(t, test.estimated, estimated)
+	}
+}
+
+func TestVisit_IsDisplayed(t *testing.T) {
+	now := time.Now()
+
+	tests := map[string]struct {
+		visit     *Visit
+		displayed bool
+	}{
+		"pending patient": {
+			visit:     &Visit{PagerID: 1, Status: VisitStatusPending},
+			displayed: false,
+		},
+		"pager getting called": {
+			visit:     &Visit{PagerID: 1, Status: VisitStatusCall},
+			displayed: true,
+		},
+		"pager called": {
+			visit:     &Visit{PagerID: 1, Status: VisitStatusCalled},
+			displayed: true,
+		},
+		"pager returned": {
+			visit:     &Visit{Status: VisitStatusCalled},
+			displayed: false,
+		},
+		"closed visit": {
+			visit:     &Visit{PagerID: 1, Status: VisitStatusCalled, ClosedAt: &now},
+			displayed: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		assert.Equal(t, test.displayed, test.visit.IsDisplayed())
+	}
+}
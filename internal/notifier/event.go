@@ -0,0 +1,42 @@
+package notifier
+
+import (
+	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/service"
+)
+
+// EventType is the type of a visit notification
+type EventType string
+
+const (
+	// EventTypeVisitAdd marks a notification about a new visit
+	EventTypeVisitAdd EventType = "visit_add"
+	// EventTypeVisitUpdate marks a notification about an updated visit
+	EventTypeVisitUpdate EventType = "visit_update"
+	// EventTypeVisitDelete marks a notification about a deleted visit
+	EventTypeVisitDelete EventType = "visit_delete"
+)
+
+type event struct {
+	kind  EventType
+	visit *model.Visit
+}
+
+// newEvent copies the visit, so later changes by the caller don't leak into queued notifications
+func newEvent(kind EventType, visit *model.Visit) *event {
+	p := *visit
+
+	return &event{kind, &p}
+}
+
+// deliver passes the event to the matching method of given notifier
+func (e *event) deliver(n service.UINotifier) {
+	switch e.kind {
+	case EventTypeVisitAdd:
+		n.NotifyNewVisit(e.visit)
+	case EventTypeVisitUpdate:
+		n.NotifyUpdatedVisit(e.visit)
+	case EventTypeVisitDelete:
+		n.NotifyDeletedVisit(e.visit)
+	}
+}
@@ -0,0 +1,93 @@
+package notifier
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/service"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Fanout dispatches notifications to multiple notifiers.
+// Every notifier gets its own queue and goroutine, so a slow notifier can't block the others or the caller.
+type Fanout struct {
+	sinks []*sink
+}
+
+type sink struct {
+	notifier service.UINotifier
+	queue    chan *event
+	dropped  uint64
+}
+
+// NewFanout creates a fanout notifier with a queue of given size per notifier
+func NewFanout(size int, notifiers ...service.UINotifier) *Fanout {
+	sinks := make([]*sink, len(notifiers))
+	for i, n := range notifiers {
+		sinks[i] = &sink{
+			notifier: n,
+			queue:    make(chan *event, size),
+		}
+	}
+
+	return &Fanout{sinks}
+}
+
+// Run delivers queued notifications to the notifiers in separate goroutines
+func (f *Fanout) Run(stop <-chan struct{}) {
+	for _, s := range f.sinks {
+		go func(s *sink) {
+			for {
+				select {
+				case e := <-s.queue:
+					e.deliver(s.notifier)
+				case <-stop:
+					return
+				}
+			}
+		}(s)
+	}
+}
+
+// Dropped returns the count of notifications dropped because of full queues
+func (f *Fanout) Dropped() uint64 {
+	var dropped uint64
+	for _, s := range f.sinks {
+		dropped += atomic.LoadUint64(&s.dropped)
+	}
+
+	return dropped
+}
+
+// NotifyNewVisit queues a notification about a new visit
+func (f *Fanout) NotifyNewVisit(visit *model.Visit) {
+	f.dispatch(newEvent(EventTypeVisitAdd, visit))
+}
+
+// NotifyUpdatedVisit queues a notification about an updated visit
+func (f *Fanout) NotifyUpdatedVisit(visit *model.Visit) {
+	f.dispatch(newEvent(EventTypeVisitUpdate, visit))
+}
+
+// NotifyDeletedVisit queues a notification about a deleted visit
+func (f *Fanout) NotifyDeletedVisit(visit *model.Visit) {
+	f.dispatch(newEvent(EventTypeVisitDelete, visit))
+}
+
+func (f *Fanout) dispatch(e *event) {
+	for _, s := range f.sinks {
+		select {
+		case s.queue <- e:
+		default:
+			atomic.AddUint64(&s.dropped, 1)
+
+			log.Warn().
+				Str("notifier", fmt.Sprintf("%T", s.notifier)).
+				Str("type", string(e.kind)).
+				Uint("visit", e.visit.ID).
+				Msg("notification queue full, notification dropped")
+		}
+	}
+}
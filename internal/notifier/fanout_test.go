@@ -0,0 +1,95 @@
+package notifier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestFanout_Dispatch(t *testing.T) {
+	tests := map[string]struct {
+		size     int
+		run      bool
+		visits   []*model.Visit
+		received int
+		dropped  uint64
+	}{
+		"deliver to all notifiers": {
+			size: 10,
+			run:  true,
+			visits: []*model.Visit{
+				{ID: 1},
+				{ID: 2},
+				{ID: 3},
+			},
+			received: 3,
+			dropped:  0,
+		},
+		"drop notifications if queue is full": {
+			size: 2,
+			run:  false,
+			visits: []*model.Visit{
+				{ID: 1},
+				{ID: 2},
+				{ID: 3},
+			},
+			received: 0,
+			dropped:  2,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		notifierA := &service.MockUINotifier{}
+		notifierB := &service.MockUINotifier{}
+		if test.received > 0 {
+			notifierA.On("NotifyUpdatedVisit", mock.AnythingOfType("*model.Visit")).Return().Times(test.received)
+			notifierB.On("NotifyUpdatedVisit", mock.AnythingOfType("*model.Visit")).Return().Times(test.received)
+		}
+
+		fanout := NewFanout(test.size, notifierA, notifierB)
+
+		stop := make(chan struct{})
+		if test.run {
+			fanout.Run(stop)
+		}
+
+		for _, visit := range test.visits {
+			fanout.NotifyUpdatedVisit(visit)
+		}
+
+		// give the sink goroutines time to deliver
+		<-time.After(50 * time.Millisecond)
+		close(stop)
+
+		assert.Equal(t, test.dropped, fanout.Dropped())
+		notifierA.AssertExpectations(t)
+		notifierB.AssertExpectations(t)
+	}
+}
+
+func TestFanout_CopiesVisit(t *testing.T) {
+	n := &service.MockUINotifier{}
+	n.On("NotifyNewVisit", mock.AnythingOfType("*model.Visit")).Return().Once()
+
+	fanout := NewFanout(1, n)
+
+	visit := &model.Visit{ID: 1, Status: model.VisitStatusPending}
+	fanout.NotifyNewVisit(visit)
+	visit.Status = model.VisitStatusCall
+
+	stop := make(chan struct{})
+	fanout.Run(stop)
+	<-time.After(50 * time.Millisecond)
+	close(stop)
+
+	n.AssertExpectations(t)
+	delivered := n.Calls[0].Arguments.Get(0).(*model.Visit)
+	assert.Equal(t, model.VisitStatusPending, delivered.Status)
+}
@@ -0,0 +1,41 @@
+package notifier
+
+import (
+	"github.com/pagient/pagient-server/internal/model"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Logger writes notifications to the log
+type Logger struct{}
+
+// NewLogger creates a logging notifier
+func NewLogger() *Logger {
+	return &Logger{}
+}
+
+// NotifyNewVisit logs a new visit
+func (l *Logger) NotifyNewVisit(visit *model.Visit) {
+	l.log(EventTypeVisitAdd, visit)
+}
+
+// NotifyUpdatedVisit logs an updated visit
+func (l *Logger) NotifyUpdatedVisit(visit *model.Visit) {
+	l.log(EventTypeVisitUpdate, visit)
+}
+
+// NotifyDeletedVisit logs a deleted visit
+func (l *Logger) NotifyDeletedVisit(visit *model.Visit) {
+	l.log(EventTypeVisitDelete, visit)
+}
+
+func (l *Logger) log(kind EventType, visit *model.Visit) {
+	log.Info().
+		Str("type", string(kind)).
+		Uint("visit", visit.ID).
+		Uint("pager", visit.PagerID).
+		Uint("client", visit.ClientID).
+		Str("status", string(visit.Status)).
+		Bool("active", visit.Active).
+		Msg("visit changed")
+}
@@ -0,0 +1,150 @@
+package notifier
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/service"
+
+	"github.com/pkg/errors"
+	"github.com/rs/xid"
+	"github.com/rs/zerolog/log"
+)
+
+// relayed events are kept this many polling intervals before they get removed
+const relayRetention = 60
+
+// Relay shares notifications between processes using the same database, e.g. the web server and admin commands.
+// Notifications are published as events to the database,
+// events of other processes are polled and passed to the target notifier.
+type Relay struct {
+	events service.EventService
+	target service.UINotifier
+	origin string
+	lastID uint
+}
+
+// NewRelay creates a relay notifier, target may be nil if events of other processes are of no interest
+func NewRelay(events service.EventService, target service.UINotifier) *Relay {
+	return &Relay{
+		events: events,
+		target: target,
+		origin: xid.New().String(),
+	}
+}
+
+// NotifyNewVisit publishes a notification about a new visit
+func (r *Relay) NotifyNewVisit(visit *model.Visit) {
+	r.publish(EventTypeVisitAdd, visit)
+}
+
+// NotifyUpdatedVisit publishes a notification about an updated visit
+func (r *Relay) NotifyUpdatedVisit(visit *model.Visit) {
+	r.publish(EventTypeVisitUpdate, visit)
+}
+
+// NotifyDeletedVisit publishes a notification about a deleted visit
+func (r *Relay) NotifyDeletedVisit(visit *model.Visit) {
+	r.publish(EventTypeVisitDelete, visit)
+}
+
+// Run polls events of other processes repeated by given every
+func (r *Relay) Run(every time.Duration, stop <-chan struct{}) error {
+	// skip events that happened before this process started
+	if err := r.skip(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	ticker := time.NewTicker(every)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if err := r.poll(); err != nil {
+					log.Error().
+						Err(err).
+						Msg("poll relayed events failed")
+
+					continue
+				}
+
+				if err := r.events.DeleteEventsBefore(time.Now().Add(-relayRetention * every)); err != nil {
+					log.Error().
+						Err(err).
+						Msg("remove outdated relayed events failed")
+				}
+			case <-stop:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	<-stop
+
+	return nil
+}
+
+func (r *Relay) publish(kind EventType, visit *model.Visit) {
+	payload, err := json.Marshal(visit)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Msg("marshal relayed event failed")
+
+		return
+	}
+
+	if err := r.events.CreateEvent(&model.Event{
+		Origin:  r.origin,
+		Type:    string(kind),
+		Payload: string(payload),
+	}); err != nil {
+		log.Error().
+			Err(err).
+			Str("type", string(kind)).
+			Msg("publish relayed event failed")
+	}
+}
+
+func (r *Relay) skip() error {
+	events, err := r.events.ListEventsAfter(r.lastID)
+	if err != nil {
+		return errors.Wrap(err, "list events failed")
+	}
+
+	if len(events) > 0 {
+		r.lastID = events[len(events)-1].ID
+	}
+
+	return nil
+}
+
+func (r *Relay) poll() error {
+	events, err := r.events.ListEventsAfter(r.lastID)
+	if err != nil {
+		return errors.Wrap(err, "list events failed")
+	}
+
+	for _, e := range events {
+		r.lastID = e.ID
+
+		if e.Origin == r.origin || r.target == nil {
+			continue
+		}
+
+		visit := &model.Visit{}
+		if err := json.Unmarshal([]byte(e.Payload), visit); err != nil {
+			log.Error().
+				Err(err).
+				Uint("event", e.ID).
+				Msg("unmarshal relayed event failed")
+
+			continue
+		}
+
+		(&event{EventType(e.Type), visit}).deliver(r.target)
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package notifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/ui/renderer"
+
+	"github.com/rs/zerolog/log"
+)
+
+// SSE broadcasts notifications as server-sent events to its subscribers
+type SSE struct {
+	mu          sync.Mutex
+	size        int
+	subscribers map[*Subscriber]bool
+}
+
+// Subscriber receives the server-sent events of a single stream
+type Subscriber struct {
+	// masked subscribers don't receive full social security numbers
+	masked bool
+
+	events chan []byte
+}
+
+// NewSSE creates a server-sent event notifier with a queue of given size per subscriber
+func NewSSE(size int) *SSE {
+	return &SSE{
+		size:        size,
+		subscribers: make(map[*Subscriber]bool),
+	}
+}
+
+// Subscribe registers a new subscriber
+func (s *SSE) Subscribe(masked bool) *Subscriber {
+	sub := &Subscriber{
+		masked: masked,
+		events: make(chan []byte, s.size),
+	}
+
+	s.mu.Lock()
+	s.subscribers[sub] = true
+	s.mu.Unlock()
+
+	return sub
+}
+
+// Unsubscribe removes the subscriber and closes it's event channel
+func (s *SSE) Unsubscribe(sub *Subscriber) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.subscribers[sub]; ok {
+		s.remove(sub)
+	}
+}
+
+// Events returns the channel of encoded events, it gets closed once the subscriber is removed
+func (sub *Subscriber) Events() <-chan []byte {
+	return sub.events
+}
+
+// NotifyNewVisit broadcasts a new visit
+func (s *SSE) NotifyNewVisit(visit *model.Visit) {
+	s.broadcast(EventTypeVisitAdd, visit)
+}
+
+// NotifyUpdatedVisit broadcasts an updated visit
+func (s *SSE) NotifyUpdatedVisit(visit *model.Visit) {
+	s.broadcast(EventTypeVisitUpdate, visit)
+}
+
+// NotifyDeletedVisit broadcasts a deleted visit
+func (s *SSE) NotifyDeletedVisit(visit *model.Visit) {
+	s.broadcast(EventTypeVisitDelete, visit)
+}
+
+// broadcast sends the event to all subscribers,
+// subscribers with a full queue are removed, so a stalled stream never blocks the others
+func (s *SSE) broadcast(kind EventType, visit *model.Visit) {
+	data := renderer.NewVisitResponse(visit)
+
+	full, err := encodeEvent(kind, data)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("type", string(kind)).
+			Uint("visit", visit.ID).
+			Msg("server-sent event could not be encoded")
+		return
+	}
+
+	masked, err := encodeEvent(kind, data.Masked())
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("type", string(kind)).
+			Uint("visit", visit.ID).
+			Msg("server-sent event could not be encoded")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for sub := range s.subscribers {
+		event := full
+		if sub.masked {
+			event = masked
+		}
+
+		select {
+		case sub.events <- event:
+		default:
+			s.remove(sub)
+		}
+	}
+}
+
+// remove unregisters the subscriber, the caller must hold the lock
+func (s *SSE) remove(sub *Subscriber) {
+	delete(s.subscribers, sub)
+	close(sub.events)
+}
+
+// encodeEvent formats the data as server-sent event of given type
+func encodeEvent(kind EventType, data *renderer.VisitResponse) ([]byte, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(fmt.Sprintf("event: %s\ndata: %s\n\n", kind, payload)), nil
+}
@@ -0,0 +1,63 @@
+package notifier
+
+import (
+	"testing"
+
+	"github.com/pagient/pagient-server/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSSE_NotifyNewVisit(t *testing.T) {
+	tests := map[string]struct {
+		masked bool
+		ssn    string
+	}{
+		"masked subscriber gets masked social security number": {
+			masked: true,
+			ssn:    `"ssn":"1234******"`,
+		},
+		"unmasked subscriber gets full social security number": {
+			masked: false,
+			ssn:    `"ssn":"1234010180"`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		stream := NewSSE(1)
+		sub := stream.Subscribe(test.masked)
+
+		stream.NotifyNewVisit(&model.Visit{
+			ID:      1,
+			Patient: model.Patient{ID: 1, SocialSecurityNo: "1234010180", Name: "Jane Doe"},
+		})
+
+		event := string(<-sub.Events())
+		assert.Contains(t, event, "event: visit_add\ndata: ")
+		assert.Contains(t, event, test.ssn)
+		assert.True(t, len(event) > 2 && event[len(event)-2:] == "\n\n")
+
+		stream.Unsubscribe(sub)
+		_, open := <-sub.Events()
+		assert.False(t, open)
+	}
+}
+
+func TestSSE_FullQueue(t *testing.T) {
+	stream := NewSSE(1)
+	sub := stream.Subscribe(true)
+
+	stream.NotifyNewVisit(&model.Visit{ID: 1})
+	stream.NotifyUpdatedVisit(&model.Visit{ID: 1})
+
+	// the queued event is still delivered, then the stream ends
+	_, open := <-sub.Events()
+	assert.True(t, open)
+	_, open = <-sub.Events()
+	assert.False(t, open)
+
+	// unsubscribing a removed subscriber doesn't close it's channel twice
+	stream.Unsubscribe(sub)
+}
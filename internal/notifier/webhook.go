@@ -0,0 +1,85 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/ui/renderer"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// Webhook posts notifications as json to an http endpoint,
+// social security numbers are masked unless revealing them has been configured
+type Webhook struct {
+	url    string
+	reveal bool
+	client *http.Client
+}
+
+type webhookPayload struct {
+	Type EventType               `json:"type"`
+	Data *renderer.VisitResponse `json:"data"`
+}
+
+// NewWebhook creates a webhook notifier posting to given url
+func NewWebhook(url string, timeout time.Duration, reveal bool) *Webhook {
+	return &Webhook{
+		url:    url,
+		reveal: reveal,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// NotifyNewVisit posts a notification about a new visit
+func (wh *Webhook) NotifyNewVisit(visit *model.Visit) {
+	wh.post(EventTypeVisitAdd, visit)
+}
+
+// NotifyUpdatedVisit posts a notification about an updated visit
+func (wh *Webhook) NotifyUpdatedVisit(visit *model.Visit) {
+	wh.post(EventTypeVisitUpdate, visit)
+}
+
+// NotifyDeletedVisit posts a notification about a deleted visit
+func (wh *Webhook) NotifyDeletedVisit(visit *model.Visit) {
+	wh.post(EventTypeVisitDelete, visit)
+}
+
+func (wh *Webhook) post(kind EventType, visit *model.Visit) {
+	data := renderer.NewVisitResponse(visit)
+	if !wh.reveal {
+		data = data.Masked()
+	}
+
+	if err := wh.send(&webhookPayload{kind, data}); err != nil {
+		log.Error().
+			Err(err).
+			Str("url", wh.url).
+			Str("type", string(kind)).
+			Msg("webhook notification failed")
+	}
+}
+
+func (wh *Webhook) send(payload *webhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(err, "marshal webhook payload failed")
+	}
+
+	resp, err := wh.client.Post(wh.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "post webhook request failed")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package notifier
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pagient/pagient-server/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhook_NotifyNewVisit(t *testing.T) {
+	tests := map[string]struct {
+		reveal bool
+		ssn    string
+	}{
+		"social security number is masked by default": {
+			reveal: false,
+			ssn:    "1234******",
+		},
+		"social security number is revealed if configured": {
+			reveal: true,
+			ssn:    "1234010180",
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		var payload struct {
+			Type EventType `json:"type"`
+			Data struct {
+				Patient struct {
+					SocialSecurityNo string `json:"ssn"`
+				} `json:"patient"`
+			} `json:"data"`
+		}
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			assert.NoError(t, json.NewDecoder(req.Body).Decode(&payload))
+		}))
+
+		wh := NewWebhook(server.URL, time.Second, test.reveal)
+		wh.NotifyNewVisit(&model.Visit{
+			ID:      1,
+			Patient: model.Patient{ID: 1, SocialSecurityNo: "1234010180", Name: "Jane Doe"},
+		})
+		server.Close()
+
+		assert.Equal(t, EventTypeVisitAdd, payload.Type)
+		assert.Equal(t, test.ssn, payload.Data.Patient.SocialSecurityNo)
+	}
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+
+	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/tracing"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// apiKeyPrefix makes api keys recognizable, e.g. by secret scanners
+	apiKeyPrefix = "pgk_"
+	// apiKeyPrefixLength is the count of characters of a key stored in plain
+	apiKeyPrefixLength = 12
+)
+
+// ListAPIKeys returns all api keys of a user or of all users if username is empty
+func (service *defaultService) ListAPIKeys(username string) (keys []*model.APIKey, err error) {
+	service, span := service.trace("ListAPIKeys")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := service.db.Begin()
+	if err != nil {
+		return nil, errors.Wrap(err, "create transaction failed")
+	}
+
+	keys, err = tx.GetAPIKeys(username)
+	if err != nil {
+		service.logger().Error().
+			Err(err).
+			Str("user", username).
+			Msg("get api keys failed")
+
+		tx.Rollback()
+		return nil, errors.Wrap(err, "get api keys failed")
+	}
+
+	tx.Commit()
+	return keys, nil
+}
+
+// ShowAPIKey returns an api key by it's id
+func (service *defaultService) ShowAPIKey(id uint) (key *model.APIKey, err error) {
+	service, span := service.trace("ShowAPIKey")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := service.db.Begin()
+	if err != nil {
+		return nil, errors.Wrap(err, "create transaction failed")
+	}
+
+	key, err = tx.GetAPIKey(id)
+	if err != nil {
+		service.logger().Error().
+			Err(err).
+			Uint("key", id).
+			Msg("get api key failed")
+
+		tx.Rollback()
+		return nil, errors.Wrap(err, "get api key failed")
+	}
+
+	tx.Commit()
+	return key, nil
+}
+
+// ShowAPIKeyByKey returns an api key by the plain key
+func (service *defaultService) ShowAPIKeyByKey(plainKey string) (key *model.APIKey, err error) {
+	service, span := service.trace("ShowAPIKeyByKey")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := service.db.Begin()
+	if err != nil {
+		return nil, errors.Wrap(err, "create transaction failed")
+	}
+
+	key, err = tx.GetAPIKeyByHash(hashToken(plainKey))
+	if err != nil {
+		service.logger().Error().
+			Err(err).
+			Msg("get api key by hash failed")
+
+		tx.Rollback()
+		return nil, errors.Wrap(err, "get api key by hash failed")
+	}
+
+	tx.Commit()
+	return key, nil
+}
+
+// CreateAPIKey generates a new api key, the plain key is only available on the created model
+func (service *defaultService) CreateAPIKey(key *model.APIKey) (err error) {
+	service, span := service.trace("CreateAPIKey")
+	defer func() { tracing.End(span, err) }()
+
+	if err := key.Validate(); err != nil {
+		if model.IsValidationErr(err) {
+			return &modelValidationErr{err.Error()}
+		}
+
+		return errors.Wrap(err, "validate api key failed")
+	}
+
+	tx, err := service.db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "create transaction failed")
+	}
+
+	// the scopes are checked against the stored role, api keys pass the role checks of the routes
+	owner, err := tx.GetUser(key.User.Username)
+	if err != nil {
+		service.logger().Error().
+			Err(err).
+			Msg("get user failed")
+
+		tx.Rollback()
+		return errors.Wrap(err, "get user failed")
+	}
+	if owner == nil || owner.ID != key.UserID {
+		tx.Rollback()
+		return &modelValidationErr{"user: doesn't exist."}
+	}
+
+	if err := key.ValidateOwner(owner); err != nil {
+		tx.Rollback()
+
+		if model.IsValidationErr(err) {
+			return &modelValidationErr{err.Error()}
+		}
+
+		return errors.Wrap(err, "validate api key failed")
+	}
+
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "generate api key failed")
+	}
+
+	key.Key = apiKeyPrefix + base64.RawURLEncoding.EncodeToString(random)
+	key.Prefix = key.Key[:apiKeyPrefixLength]
+	key.Hash = hashToken(key.Key)
+
+	if err := tx.AddAPIKey(key); err != nil {
+		service.logger().Error().
+			Err(err).
+			Msg("add api key failed")
+
+		tx.Rollback()
+		return errors.Wrap(err, "add api key failed")
+	}
+
+	tx.Commit()
+	return nil
+}
+
+// TouchAPIKey stores when the api key has been used last
+func (service *defaultService) TouchAPIKey(key *model.APIKey) (err error) {
+	service, span := service.trace("TouchAPIKey")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := service.db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "create transaction failed")
+	}
+
+	if err := tx.UpdateAPIKeyLastUsed(key); err != nil {
+		service.logger().Error().
+			Err(err).
+			Uint("key", key.ID).
+			Msg("update api key last used failed")
+
+		tx.Rollback()
+		return errors.Wrap(err, "update api key last used failed")
+	}
+
+	tx.Commit()
+	return nil
+}
+
+// DeleteAPIKey revokes an api key
+func (service *defaultService) DeleteAPIKey(key *model.APIKey) (err error) {
+	service, span := service.trace("DeleteAPIKey")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := service.db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "create transaction failed")
+	}
+
+	if err := tx.RemoveAPIKey(key); err != nil {
+		tx.Rollback()
+
+		if isEntryNotExistErr(err) {
+			return &modelNotExistErr{"api key doesn't exist"}
+		}
+
+		service.logger().Error().
+			Err(err).
+			Msg("remove api key failed")
+
+		return errors.Wrap(err, "remove api key failed")
+	}
+
+	tx.Commit()
+	return nil
+}
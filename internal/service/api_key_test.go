@@ -0,0 +1,104 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pagient/pagient-server/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDefaultService_CreateAPIKey(t *testing.T) {
+	user := &model.User{ID: 1, Username: "user", Role: model.UserRoleUser}
+	display := &model.User{ID: 2, Username: "display", Role: model.UserRoleDisplay}
+
+	tests := map[string]struct {
+		key           *model.APIKey
+		owner         *model.User
+		validationErr bool
+	}{
+		"api key without name": {
+			key: &model.APIKey{
+				User:   *user,
+				UserID: 1,
+				Scopes: string(model.APIKeyScopeReadPatients),
+			},
+			validationErr: true,
+		},
+		"api key with unknown scope": {
+			key: &model.APIKey{
+				Name:   "kiosk",
+				User:   *user,
+				UserID: 1,
+				Scopes: "patients:delete",
+			},
+			validationErr: true,
+		},
+		"display can't create api key with patient scopes": {
+			key: &model.APIKey{
+				Name:   "kiosk",
+				User:   *display,
+				UserID: 2,
+				Scopes: string(model.APIKeyScopeReadPatients) + "," + string(model.APIKeyScopeCallPagers),
+			},
+			owner:         display,
+			validationErr: true,
+		},
+		"successfully create display api key for display": {
+			key: &model.APIKey{
+				Name:   "waiting room",
+				User:   *display,
+				UserID: 2,
+				Scopes: string(model.APIKeyScopeDisplay),
+			},
+			owner:         display,
+			validationErr: false,
+		},
+		"successfully create api key": {
+			key: &model.APIKey{
+				Name:   "kiosk",
+				User:   *user,
+				UserID: 1,
+				Scopes: string(model.APIKeyScopeReadPatients) + "," + string(model.APIKeyScopeCreatePatients),
+			},
+			owner:         user,
+			validationErr: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		tx := &MockTx{}
+		db := &MockDB{}
+
+		if test.owner != nil {
+			db.On("Begin").Return(tx, nil).Once()
+			tx.On("GetUser", test.owner.Username).Return(test.owner, nil).Once()
+		}
+		if test.owner != nil && test.validationErr {
+			tx.On("Rollback").Return(nil).Once()
+		}
+		if !test.validationErr {
+			tx.On("AddAPIKey", mock.MatchedBy(func(key *model.APIKey) bool {
+				return key.Hash == hashToken(key.Key)
+			})).Return(nil).Once()
+			tx.On("Commit").Return(nil).Once()
+		}
+
+		s := NewService(db, nil)
+		err := s.CreateAPIKey(test.key)
+
+		assert.Equal(t, test.validationErr, IsModelValidationErr(err))
+		if !test.validationErr {
+			assert.NoError(t, err)
+			assert.True(t, strings.HasPrefix(test.key.Key, apiKeyPrefix))
+			assert.Equal(t, test.key.Key[:apiKeyPrefixLength], test.key.Prefix)
+		}
+
+		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
+	}
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"time"
+
+	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/tracing"
+
+	"github.com/pkg/errors"
+)
+
+// ListAuditEntries returns the audit entries since the given time
+func (service *defaultService) ListAuditEntries(since time.Time) (entries []*model.AuditEntry, err error) {
+	service, span := service.trace("ListAuditEntries")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := service.db.Begin()
+	if err != nil {
+		return nil, errors.Wrap(err, "create transaction failed")
+	}
+
+	entries, err = tx.GetAuditEntries(since)
+	if err != nil {
+		service.logger().Error().
+			Err(err).
+			Msg("get audit entries failed")
+
+		tx.Rollback()
+		return nil, errors.Wrap(err, "get audit entries failed")
+	}
+
+	tx.Commit()
+	return entries, nil
+}
@@ -0,0 +1,10 @@
+package service
+
+import "github.com/pagient/pagient-server/internal/model"
+
+// Authenticator interface for user directories verifying credentials
+type Authenticator interface {
+	// Authenticate returns the user as described by the directory with username,
+	// role and client name, or nil if the credentials are invalid
+	Authenticate(string, string) (*model.User, error)
+}
@@ -1,22 +1,27 @@
 package service
 
 import (
+	"fmt"
+
 	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/tracing"
 
 	"github.com/pkg/errors"
-	"github.com/rs/zerolog/log"
 )
 
 // ListClients returns all clients
-func (service *defaultService) ListClients() ([]*model.Client, error) {
+func (service *defaultService) ListClients() (clients []*model.Client, err error) {
+	service, span := service.trace("ListClients")
+	defer func() { tracing.End(span, err) }()
+
 	tx, err := service.db.Begin()
 	if err != nil {
 		return nil, errors.Wrap(err, "create transaction failed")
 	}
 
-	clients, err := tx.GetClients()
+	clients, err = tx.GetClients()
 	if err != nil {
-		log.Error().
+		service.logger().Error().
 			Err(err).
 			Msg("get all clients failed")
 
@@ -29,15 +34,18 @@ func (service *defaultService) ListClients() ([]*model.Client, error) {
 }
 
 // ShowClient returns a client by it's id
-func (service *defaultService) ShowClient(id uint) (*model.Client, error) {
+func (service *defaultService) ShowClient(id uint) (client *model.Client, err error) {
+	service, span := service.trace("ShowClient")
+	defer func() { tracing.End(span, err) }()
+
 	tx, err := service.db.Begin()
 	if err != nil {
 		return nil, errors.Wrap(err, "create transaction failed")
 	}
 
-	client, err := tx.GetClient(id)
+	client, err = tx.GetClient(id)
 	if err != nil {
-		log.Error().
+		service.logger().Error().
 			Err(err).
 			Uint("client id", id).
 			Msg("get client failed")
@@ -51,15 +59,18 @@ func (service *defaultService) ShowClient(id uint) (*model.Client, error) {
 }
 
 // ShowClientByUser returns a client belonging to the given user
-func (service *defaultService) ShowClientByUser(username string) (*model.Client, error) {
+func (service *defaultService) ShowClientByUser(username string) (client *model.Client, err error) {
+	service, span := service.trace("ShowClientByUser")
+	defer func() { tracing.End(span, err) }()
+
 	tx, err := service.db.Begin()
 	if err != nil {
 		return nil, errors.Wrap(err, "create transaction failed")
 	}
 
-	client, err := tx.GetClientByUser(username)
+	client, err = tx.GetClientByUser(username)
 	if err != nil {
-		log.Error().
+		service.logger().Error().
 			Err(err).
 			Str("username", username).
 			Msg("get client by user failed")
@@ -73,7 +84,10 @@ func (service *defaultService) ShowClientByUser(username string) (*model.Client,
 }
 
 // CreateClient creates a new client
-func (service *defaultService) CreateClient(client *model.Client) error {
+func (service *defaultService) CreateClient(client *model.Client) (err error) {
+	service, span := service.trace("CreateClient")
+	defer func() { tracing.End(span, err) }()
+
 	if err := service.validateClient(client); err != nil {
 		return errors.WithStack(err)
 	}
@@ -85,7 +99,7 @@ func (service *defaultService) CreateClient(client *model.Client) error {
 
 	err = tx.AddClient(client)
 	if err != nil {
-		log.Error().
+		service.logger().Error().
 			Err(err).
 			Msg("add client failed")
 
@@ -97,6 +111,123 @@ func (service *defaultService) CreateClient(client *model.Client) error {
 	return nil
 }
 
+// UpdateClient renames an existing client if given model is valid
+func (service *defaultService) UpdateClient(client *model.Client) (err error) {
+	service, span := service.trace("UpdateClient")
+	defer func() { tracing.End(span, err) }()
+
+	if err := service.validateClient(client); err != nil {
+		return errors.WithStack(err)
+	}
+
+	tx, err := service.db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "create transaction failed")
+	}
+
+	existing, err := tx.GetClient(client.ID)
+	if err != nil {
+		service.logger().Error().
+			Err(err).
+			Uint("client id", client.ID).
+			Msg("get client failed")
+
+		tx.Rollback()
+		return errors.Wrap(err, "get client failed")
+	}
+	if existing == nil {
+		tx.Rollback()
+		return &modelNotExistErr{"client doesn't exist"}
+	}
+
+	if err := tx.UpdateClient(client); err != nil {
+		service.logger().Error().
+			Err(err).
+			Msg("update client failed")
+
+		tx.Rollback()
+		return errors.Wrap(err, "update client failed")
+	}
+
+	tx.Commit()
+	return nil
+}
+
+// DeleteClient deletes a client neither assigned to a user nor having open visits
+func (service *defaultService) DeleteClient(client *model.Client) (err error) {
+	service, span := service.trace("DeleteClient")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := service.db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "create transaction failed")
+	}
+
+	existing, err := tx.GetClient(client.ID)
+	if err != nil {
+		service.logger().Error().
+			Err(err).
+			Uint("client id", client.ID).
+			Msg("get client failed")
+
+		tx.Rollback()
+		return errors.Wrap(err, "get client failed")
+	}
+	if existing == nil {
+		tx.Rollback()
+		return &modelNotExistErr{"client doesn't exist"}
+	}
+
+	users, err := tx.GetUsers()
+	if err != nil {
+		service.logger().Error().
+			Err(err).
+			Msg("get all users failed")
+
+		tx.Rollback()
+		return errors.Wrap(err, "get all users failed")
+	}
+
+	for _, user := range users {
+		if user.ClientID != nil && *user.ClientID == client.ID {
+			tx.Rollback()
+			return &invalidArgumentErr{fmt.Sprintf("client is assigned to user %s", user.Username)}
+		}
+	}
+
+	visits, err := tx.GetVisitsByClient(client.ID)
+	if err != nil {
+		service.logger().Error().
+			Err(err).
+			Msg("get visits by client failed")
+
+		tx.Rollback()
+		return errors.Wrap(err, "get visits by client failed")
+	}
+
+	if len(visits) > 0 {
+		tx.Rollback()
+		return &invalidArgumentErr{"client has open visits"}
+	}
+
+	if err := tx.RemoveClient(client); err != nil {
+		tx.Rollback()
+
+		if isEntryNotExistErr(err) {
+			return &modelNotExistErr{"client doesn't exist"}
+		}
+
+		service.logger().Error().
+			Err(err).
+			Msg("remove client failed")
+
+		return errors.Wrap(err, "remove client failed")
+	}
+
+	tx.Commit()
+	return nil
+}
+
 func (service *defaultService) validateClient(client *model.Client) error {
 	if err := client.Validate(); err != nil {
 		if model.IsValidationErr(err) {
@@ -76,3 +76,73 @@ func TestDefaultService_CreateClient(t *testing.T) {
 		tx.AssertExpectations(t)
 	}
 }
+
+func TestDefaultService_DeleteClient(t *testing.T) {
+	clientID := uint(1)
+
+	tests := map[string]struct {
+		client     *model.Client
+		users      []*model.User
+		visits     []*model.Visit
+		invalidErr bool
+		notExist   bool
+	}{
+		"successfully delete client": {
+			client: &model.Client{ID: clientID, Name: "test"},
+			users:  []*model.User{{Username: "user"}},
+		},
+		"client assigned to user": {
+			client:     &model.Client{ID: clientID, Name: "test"},
+			users:      []*model.User{{Username: "user", ClientID: &clientID}},
+			invalidErr: true,
+		},
+		"client with open visits": {
+			client:     &model.Client{ID: clientID, Name: "test"},
+			visits:     []*model.Visit{{ID: 1, ClientID: clientID}},
+			invalidErr: true,
+		},
+		"unknown client": {
+			notExist: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		tx := &MockTx{}
+		tx.On("GetClient", clientID).Return(test.client, nil).Once()
+
+		if test.client != nil {
+			tx.On("GetUsers").Return(test.users, nil).Once()
+		}
+		// visits are only checked of clients not assigned to users
+		if test.client != nil && (!test.invalidErr || test.visits != nil) {
+			tx.On("GetVisitsByClient", clientID).Return(test.visits, nil).Once()
+		}
+
+		if test.invalidErr || test.notExist {
+			tx.On("Rollback").Return(nil).Once()
+		} else {
+			tx.On("RemoveClient", mock.AnythingOfType("*model.Client")).Return(nil).Once()
+			tx.On("Commit").Return(nil).Once()
+		}
+
+		db := &MockDB{}
+		db.On("Begin").Return(tx, nil).Once()
+
+		s := NewService(db, nil)
+		err := s.DeleteClient(&model.Client{ID: clientID})
+
+		switch {
+		case test.invalidErr:
+			assert.True(t, IsInvalidArgumentErr(err))
+		case test.notExist:
+			assert.True(t, IsModelNotExistErr(err))
+		default:
+			assert.NoError(t, err)
+		}
+
+		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
+	}
+}
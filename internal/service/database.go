@@ -1,6 +1,8 @@
 package service
 
 import (
+	"time"
+
 	"github.com/pagient/pagient-server/internal/model"
 
 	"github.com/pkg/errors"
@@ -16,11 +18,32 @@ type Tx interface {
 	Commit() error
 	Rollback() error
 
+	APIKeyTx
+	AuditTx
 	ClientTx
+	EventTx
+	LoginAttemptTx
 	PagerTx
 	PatientTx
 	TokenTx
 	UserTx
+	VisitTx
+}
+
+// APIKeyTx interface
+type APIKeyTx interface {
+	GetAPIKeys(string) ([]*model.APIKey, error)
+	GetAPIKey(uint) (*model.APIKey, error)
+	GetAPIKeyByHash(string) (*model.APIKey, error)
+	AddAPIKey(*model.APIKey) error
+	UpdateAPIKeyLastUsed(*model.APIKey) error
+	RemoveAPIKey(*model.APIKey) error
+}
+
+// AuditTx interface
+type AuditTx interface {
+	GetAuditEntries(time.Time) ([]*model.AuditEntry, error)
+	AddAuditEntry(*model.AuditEntry) error
 }
 
 // ClientTx interface
@@ -29,6 +52,21 @@ type ClientTx interface {
 	GetClient(uint) (*model.Client, error)
 	GetClientByUser(string) (*model.Client, error)
 	AddClient(*model.Client) error
+	UpdateClient(*model.Client) error
+	RemoveClient(*model.Client) error
+}
+
+// EventTx interface
+type EventTx interface {
+	GetEventsAfter(uint) ([]*model.Event, error)
+	AddEvent(*model.Event) error
+	RemoveEventsBefore(time.Time) error
+}
+
+// LoginAttemptTx interface
+type LoginAttemptTx interface {
+	GetLoginAttempts(string, time.Time) ([]*model.LoginAttempt, error)
+	AddLoginAttempt(*model.LoginAttempt) error
 }
 
 // PagerTx interface
@@ -37,38 +75,67 @@ type PagerTx interface {
 	GetUnassignedPagers() ([]*model.Pager, error)
 	GetPager(uint) (*model.Pager, error)
 	AddPager(*model.Pager) error
+	UpdatePager(*model.Pager) error
+	RemovePager(*model.Pager) error
 }
 
 // PatientTx interface
 type PatientTx interface {
 	GetPatients() ([]*model.Patient, error)
-	GetPatientsWithPagerByStatus(...model.PatientStatus) ([]*model.Patient, error)
-	// Get Patients by Client, Activity (first in slice) and Assignment of a Pager (second in slice)
-	GetPatientsByClient(uint, ...bool) ([]*model.Patient, error)
 	GetPatient(uint) (*model.Patient, error)
+	GetFinishedPatientsBefore(time.Time) ([]*model.Patient, error)
 	AddPatient(*model.Patient) error
 	UpdatePatient(*model.Patient) error
-	MarkPatientsInactiveByClient(uint) error
+	AnonymizePatient(*model.Patient) error
 	RemovePatient(*model.Patient) error
-	// Remove Patients by Client, Activity (first in slice) and Assignment of a Pager (second in slice)
-	RemovePatientsByClient(uint, ...bool) error
 }
 
 // TokenTx interface
 type TokenTx interface {
 	GetToken(string) (*model.Token, error)
+	GetTokenByID(uint) (*model.Token, error)
+	GetTokenByRefreshHash(string) (*model.Token, error)
 	GetTokensByUser(string) ([]*model.Token, error)
 	AddToken(*model.Token) error
+	UpdateToken(*model.Token) error
+	UpdateTokenLastSeen(*model.Token) error
 	RemoveToken(*model.Token) error
+	RemoveTokensByFamily(string) error
+	RemoveExpiredTokens(time.Time) error
 }
 
 // UserTx interface
 type UserTx interface {
 	GetUsers() ([]*model.User, error)
 	GetUser(string) (*model.User, error)
+	GetUserBySubject(string) (*model.User, error)
 	GetUserByToken(string) (*model.User, error)
 	AddUser(*model.User) error
+	UpdateUser(*model.User) error
 	UpdateUserPassword(*model.User) error
+	UpdateUserRole(*model.User) error
+	UpdateUserLogin(*model.User) error
+	IncrementUserFailedLogins(*model.User) error
+	RemoveUser(*model.User) error
+}
+
+// VisitTx interface
+type VisitTx interface {
+	GetVisits() ([]*model.Visit, error)
+	GetVisitsWithPagerByStatus(...model.VisitStatus) ([]*model.Visit, error)
+	CountVisitsByStatus() (map[model.VisitStatus]int, error)
+	// Get open Visits by Client, Activity (first in slice) and Assignment of a Pager (second in slice)
+	GetVisitsByClient(uint, ...bool) ([]*model.Visit, error)
+	GetVisitsByPatient(uint) ([]*model.Visit, error)
+	GetVisitsFirstCalledAfter(time.Time) ([]*model.Visit, error)
+	GetVisitsCheckedInBetween(time.Time, time.Time) ([]*model.Visit, error)
+	GetVisit(uint) (*model.Visit, error)
+	NextVisitTicket(time.Time) (uint, error)
+	AddVisit(*model.Visit) error
+	UpdateVisit(*model.Visit) error
+	MarkVisitsInactiveByClient(uint) error
+	// Close open Visits by Client, Activity (first in slice) and Assignment of a Pager (second in slice)
+	CloseVisitsByClient(uint, ...bool) error
 }
 
 type entryExistErr interface {
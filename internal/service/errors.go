@@ -111,3 +111,47 @@ func IsInvalidArgumentErr(err error) bool {
 	ia, ok := errors.Cause(err).(invalidArgErr)
 	return ok && ia.InvalidArgument()
 }
+
+type lockErr interface {
+	Locked() bool
+}
+
+type userLockedErr struct {
+	msg string
+}
+
+func (err *userLockedErr) Error() string {
+	return err.msg
+}
+
+func (err *userLockedErr) Locked() bool {
+	return true
+}
+
+// IsUserLockedErr returns true if the user is locked out
+func IsUserLockedErr(err error) bool {
+	le, ok := errors.Cause(err).(lockErr)
+	return ok && le.Locked()
+}
+
+type linkErr interface {
+	NotLinked() bool
+}
+
+type userNotLinkedErr struct {
+	msg string
+}
+
+func (err *userNotLinkedErr) Error() string {
+	return err.msg
+}
+
+func (err *userNotLinkedErr) NotLinked() bool {
+	return true
+}
+
+// IsUserNotLinkedErr returns true if the account has to be linked to the single sign-on provider by an admin
+func IsUserNotLinkedErr(err error) bool {
+	ln, ok := errors.Cause(err).(linkErr)
+	return ok && ln.NotLinked()
+}
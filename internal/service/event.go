@@ -0,0 +1,83 @@
+package service
+
+import (
+	"time"
+
+	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/tracing"
+
+	"github.com/pkg/errors"
+)
+
+// ListEventsAfter returns all events newer than the event with given id
+func (service *defaultService) ListEventsAfter(id uint) (events []*model.Event, err error) {
+	service, span := service.trace("ListEventsAfter")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := service.db.Begin()
+	if err != nil {
+		return nil, errors.Wrap(err, "create transaction failed")
+	}
+
+	events, err = tx.GetEventsAfter(id)
+	if err != nil {
+		service.logger().Error().
+			Err(err).
+			Uint("event id", id).
+			Msg("get events after id failed")
+
+		tx.Rollback()
+		return nil, errors.Wrap(err, "get events after id failed")
+	}
+
+	tx.Commit()
+	return events, nil
+}
+
+// CreateEvent stores a new event
+func (service *defaultService) CreateEvent(event *model.Event) (err error) {
+	service, span := service.trace("CreateEvent")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := service.db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "create transaction failed")
+	}
+
+	err = tx.AddEvent(event)
+	if err != nil {
+		service.logger().Error().
+			Err(err).
+			Msg("add event failed")
+
+		tx.Rollback()
+		return errors.Wrap(err, "add event failed")
+	}
+
+	tx.Commit()
+	return nil
+}
+
+// DeleteEventsBefore removes all events created before the given time
+func (service *defaultService) DeleteEventsBefore(before time.Time) (err error) {
+	service, span := service.trace("DeleteEventsBefore")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := service.db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "create transaction failed")
+	}
+
+	err = tx.RemoveEventsBefore(before)
+	if err != nil {
+		service.logger().Error().
+			Err(err).
+			Msg("remove events failed")
+
+		tx.Rollback()
+		return errors.Wrap(err, "remove events failed")
+	}
+
+	tx.Commit()
+	return nil
+}
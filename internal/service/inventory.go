@@ -0,0 +1,322 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/tracing"
+
+	"github.com/pkg/errors"
+)
+
+// ImportInventory adds the clients, pagers and users of the inventory in one transaction.
+// All rows are validated first, nothing is added if any row is invalid and the invalid rows are returned.
+// On dry runs the rows are added, but the transaction is rolled back.
+func (service *defaultService) ImportInventory(inventory *model.Inventory, dryRun bool) (importErrs []*model.ImportError, err error) {
+	service, span := service.trace("ImportInventory")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := service.db.Begin()
+	if err != nil {
+		return nil, errors.Wrap(err, "create transaction failed")
+	}
+
+	importErrs, err = service.validateInventory(tx, inventory)
+	if err != nil {
+		service.logger().Error().
+			Err(err).
+			Msg("validate inventory failed")
+
+		tx.Rollback()
+		return nil, errors.WithStack(err)
+	}
+
+	if len(importErrs) > 0 {
+		tx.Rollback()
+		return importErrs, nil
+	}
+
+	if err := service.addInventory(tx, inventory); err != nil {
+		service.logger().Error().
+			Err(err).
+			Msg("add inventory failed")
+
+		tx.Rollback()
+		return nil, errors.WithStack(err)
+	}
+
+	if dryRun {
+		tx.Rollback()
+		return nil, nil
+	}
+
+	tx.Commit()
+
+	service.logger().Info().
+		Int("clients", len(inventory.Clients)).
+		Int("pagers", len(inventory.Pagers)).
+		Int("users", len(inventory.Users)).
+		Msg("inventory imported")
+
+	return nil, nil
+}
+
+// ExportInventory returns all clients, pagers and users, the passwords of users are left out.
+// Imported users without password can't login locally until an admin sets their password.
+func (service *defaultService) ExportInventory() (inventory *model.Inventory, err error) {
+	service, span := service.trace("ExportInventory")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := service.db.Begin()
+	if err != nil {
+		return nil, errors.Wrap(err, "create transaction failed")
+	}
+
+	clients, err := tx.GetClients()
+	if err != nil {
+		service.logger().Error().
+			Err(err).
+			Msg("get all clients failed")
+
+		tx.Rollback()
+		return nil, errors.Wrap(err, "get all clients failed")
+	}
+
+	pagers, err := tx.GetPagers()
+	if err != nil {
+		service.logger().Error().
+			Err(err).
+			Msg("get all pagers failed")
+
+		tx.Rollback()
+		return nil, errors.Wrap(err, "get all pagers failed")
+	}
+
+	users, err := tx.GetUsers()
+	if err != nil {
+		service.logger().Error().
+			Err(err).
+			Msg("get all users failed")
+
+		tx.Rollback()
+		return nil, errors.Wrap(err, "get all users failed")
+	}
+
+	tx.Commit()
+
+	clientNames := make(map[uint]string, len(clients))
+	for _, client := range clients {
+		clientNames[client.ID] = client.Name
+	}
+
+	for _, user := range users {
+		user.Password = ""
+		if user.ClientID != nil {
+			user.Client = model.Client{ID: *user.ClientID, Name: clientNames[*user.ClientID]}
+		}
+	}
+
+	return &model.Inventory{
+		Clients: clients,
+		Pagers:  pagers,
+		Users:   users,
+	}, nil
+}
+
+// validateInventory validates every row of the inventory against the rows before it and the existing entries
+func (service *defaultService) validateInventory(tx Tx, inventory *model.Inventory) ([]*model.ImportError, error) {
+	clients, err := tx.GetClients()
+	if err != nil {
+		return nil, errors.Wrap(err, "get all clients failed")
+	}
+
+	pagers, err := tx.GetPagers()
+	if err != nil {
+		return nil, errors.Wrap(err, "get all pagers failed")
+	}
+
+	users, err := tx.GetUsers()
+	if err != nil {
+		return nil, errors.Wrap(err, "get all users failed")
+	}
+
+	var importErrs []*model.ImportError
+	report := func(kind model.InventoryKind, i int, msg string) {
+		importErrs = append(importErrs, &model.ImportError{Kind: kind, Row: i + 1, Message: msg})
+	}
+
+	clientNames := make(map[string]bool)
+	for _, client := range clients {
+		clientNames[client.Name] = true
+	}
+
+	for i, client := range inventory.Clients {
+		if err := client.Validate(); err != nil {
+			if !model.IsValidationErr(err) {
+				return nil, errors.Wrap(err, "validate client failed")
+			}
+
+			report(model.InventoryKindClients, i, err.Error())
+			continue
+		}
+
+		if clientNames[client.Name] {
+			report(model.InventoryKindClients, i, fmt.Sprintf("client %s already exists", client.Name))
+			continue
+		}
+		clientNames[client.Name] = true
+	}
+
+	pagerNames := make(map[string]bool)
+	easyCallIDs := make(map[uint]bool)
+	for _, pager := range pagers {
+		pagerNames[pager.Name] = true
+		easyCallIDs[pager.EasyCallID] = true
+	}
+
+	for i, pager := range inventory.Pagers {
+		if err := pager.Validate(); err != nil {
+			if !model.IsValidationErr(err) {
+				return nil, errors.Wrap(err, "validate pager failed")
+			}
+
+			report(model.InventoryKindPagers, i, err.Error())
+			continue
+		}
+
+		if pagerNames[pager.Name] {
+			report(model.InventoryKindPagers, i, fmt.Sprintf("pager %s already exists", pager.Name))
+			continue
+		}
+		if easyCallIDs[pager.EasyCallID] {
+			report(model.InventoryKindPagers, i, fmt.Sprintf("pager with easy call id %d already exists", pager.EasyCallID))
+			continue
+		}
+		pagerNames[pager.Name] = true
+		easyCallIDs[pager.EasyCallID] = true
+	}
+
+	usernames := make(map[string]bool)
+	// assignedClients maps client names to the user they are assigned to, every client belongs to one user
+	assignedClients := make(map[string]string)
+	for _, user := range users {
+		usernames[user.Username] = true
+	}
+	for _, client := range clients {
+		for _, user := range users {
+			if user.ClientID != nil && *user.ClientID == client.ID {
+				assignedClients[client.Name] = user.Username
+			}
+		}
+	}
+
+	for i, user := range inventory.Users {
+		if user.Role == "" {
+			user.Role = model.UserRoleUser
+		}
+		if user.Origin == "" {
+			user.Origin = model.UserOriginLocal
+		}
+
+		// the client is checked by name below, as imported clients have no id yet
+		if err := user.ValidateImport(passwordPolicy()); err != nil {
+			if !model.IsValidationErr(err) {
+				return nil, errors.Wrap(err, "validate user failed")
+			}
+
+			report(model.InventoryKindUsers, i, err.Error())
+			continue
+		}
+
+		if usernames[user.Username] {
+			report(model.InventoryKindUsers, i, fmt.Sprintf("user %s already exists", user.Username))
+			continue
+		}
+
+		if name := user.Client.Name; name != "" {
+			if !clientNames[name] {
+				report(model.InventoryKindUsers, i, fmt.Sprintf("client %s doesn't exist", name))
+				continue
+			}
+			if username, ok := assignedClients[name]; ok {
+				report(model.InventoryKindUsers, i, fmt.Sprintf("client %s is already assigned to user %s", name, username))
+				continue
+			}
+			assignedClients[name] = user.Username
+		}
+		usernames[user.Username] = true
+	}
+
+	return importErrs, nil
+}
+
+// addInventory adds the validated inventory, clients first so users can be assigned to them
+func (service *defaultService) addInventory(tx Tx, inventory *model.Inventory) error {
+	clients, err := tx.GetClients()
+	if err != nil {
+		return errors.Wrap(err, "get all clients failed")
+	}
+
+	clientIDs := make(map[string]uint, len(clients))
+	for _, client := range clients {
+		clientIDs[client.Name] = client.ID
+	}
+
+	for _, client := range inventory.Clients {
+		if err := tx.AddClient(client); err != nil {
+			return errors.Wrapf(err, "add client %s failed", client.Name)
+		}
+		clientIDs[client.Name] = client.ID
+	}
+
+	for _, pager := range inventory.Pagers {
+		if err := tx.AddPager(pager); err != nil {
+			return errors.Wrapf(err, "add pager %s failed", pager.Name)
+		}
+	}
+
+	for _, user := range inventory.Users {
+		if name := user.Client.Name; name != "" {
+			clientID := clientIDs[name]
+			user.ClientID = &clientID
+		}
+
+		if err := setImportedPassword(user); err != nil {
+			return errors.WithStack(err)
+		}
+
+		if err := tx.AddUser(user); err != nil {
+			return errors.Wrapf(err, "add user %s failed", user.Username)
+		}
+	}
+
+	return nil
+}
+
+// setImportedPassword hashes the password of an imported user. Local users without password
+// get a random one nobody knows, so they can't login until an admin sets theirs.
+// Directory users are kept without password, so they login through their directory only.
+func setImportedPassword(user *model.User) error {
+	password := user.Password
+	if password == "" {
+		if user.Origin != model.UserOriginLocal {
+			return nil
+		}
+
+		random := make([]byte, 32)
+		if _, err := rand.Read(random); err != nil {
+			return errors.Wrap(err, "generate password failed")
+		}
+		password = base64.RawURLEncoding.EncodeToString(random)
+	}
+
+	hash, err := hashPassword(password)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	user.Password = hash
+
+	return nil
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/ui/renderer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDefaultService_ImportInventory(t *testing.T) {
+	clientID := uint(1)
+
+	tests := map[string]struct {
+		inventory *model.Inventory
+		dryRun    bool
+		errs      []*model.ImportError
+	}{
+		"successfully import inventory": {
+			inventory: &model.Inventory{
+				Clients: []*model.Client{{Name: "room 2"}},
+				Pagers:  []*model.Pager{{Name: "pager 2", EasyCallID: 2}},
+				Users:   []*model.User{{Username: "user2", Password: "Secret12!", Client: model.Client{Name: "room 2"}}},
+			},
+		},
+		"rollback on dry run": {
+			inventory: &model.Inventory{
+				Pagers: []*model.Pager{{Name: "pager 2", EasyCallID: 2}},
+			},
+			dryRun: true,
+		},
+		"report invalid rows": {
+			inventory: &model.Inventory{
+				Clients: []*model.Client{{Name: "room 1"}},
+				Pagers: []*model.Pager{
+					{Name: "pager 2", EasyCallID: 2},
+					{Name: "pager 3", EasyCallID: 2},
+					{Name: "pager 4"},
+				},
+				Users: []*model.User{
+					{Username: "user2", Password: "Secret12!", Client: model.Client{Name: "room 1"}},
+					{Username: "user3", Password: "Secret12!", Client: model.Client{Name: "room 3"}},
+				},
+			},
+			errs: []*model.ImportError{
+				{Kind: model.InventoryKindClients, Row: 1, Message: "client room 1 already exists"},
+				{Kind: model.InventoryKindPagers, Row: 2, Message: "pager with easy call id 2 already exists"},
+				{Kind: model.InventoryKindPagers, Row: 3, Message: "EasyCallID: cannot be blank."},
+				{Kind: model.InventoryKindUsers, Row: 1, Message: "client room 1 is already assigned to user user"},
+				{Kind: model.InventoryKindUsers, Row: 2, Message: "client room 3 doesn't exist"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		tx := &MockTx{}
+		tx.On("GetClients").Return([]*model.Client{{ID: clientID, Name: "room 1"}}, nil)
+		tx.On("GetPagers").Return([]*model.Pager{{ID: 1, Name: "pager 1", EasyCallID: 1}}, nil).Once()
+		tx.On("GetUsers").Return([]*model.User{{ID: 1, Username: "user", ClientID: &clientID}}, nil).Once()
+
+		if test.errs == nil {
+			if len(test.inventory.Clients) > 0 {
+				tx.On("AddClient", mock.AnythingOfType("*model.Client")).Return(func(c *model.Client) error {
+					c.ID = 2
+					return nil
+				})
+			}
+			if len(test.inventory.Pagers) > 0 {
+				tx.On("AddPager", mock.AnythingOfType("*model.Pager")).Return(nil)
+			}
+			if len(test.inventory.Users) > 0 {
+				tx.On("AddUser", mock.AnythingOfType("*model.User")).Return(nil)
+			}
+		}
+
+		if test.errs != nil || test.dryRun {
+			tx.On("Rollback").Return(nil).Once()
+		} else {
+			tx.On("Commit").Return(nil).Once()
+		}
+
+		db := &MockDB{}
+		db.On("Begin").Return(tx, nil).Once()
+
+		s := NewService(db, nil)
+		errs, err := s.ImportInventory(test.inventory, test.dryRun)
+
+		assert.NoError(t, err)
+		assert.Equal(t, test.errs, errs)
+
+		if test.errs == nil {
+			for _, user := range test.inventory.Users {
+				// users get the id of the client imported along
+				if assert.NotNil(t, user.ClientID) {
+					assert.Equal(t, uint(2), *user.ClientID)
+				}
+				assert.True(t, comparePasswords(user.Password, "Secret12!"))
+			}
+		}
+
+		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
+	}
+}
+
+func TestDefaultService_InventoryRoundTrip(t *testing.T) {
+	hash, err := hashPassword("Secret12!")
+	assert.NoError(t, err)
+
+	clientID := uint(1)
+
+	for _, format := range []string{renderer.InventoryFormatJSON, renderer.InventoryFormatYAML, renderer.InventoryFormatCSV} {
+		t.Logf("Running test case: %s", format)
+
+		// csv holds one kind only
+		kind := model.InventoryKind("")
+		if format == renderer.InventoryFormatCSV {
+			kind = model.InventoryKindUsers
+		}
+
+		source := &MockTx{}
+		source.On("GetClients").Return([]*model.Client{{ID: clientID, Name: "room 1"}}, nil).Once()
+		source.On("GetPagers").Return([]*model.Pager{{ID: 1, Name: "pager 1", EasyCallID: 1}}, nil).Once()
+		source.On("GetUsers").Return([]*model.User{
+			{ID: 1, Username: "local", Password: hash, Role: model.UserRoleUser, Origin: model.UserOriginLocal, ClientID: &clientID},
+			{ID: 2, Username: "directory", Role: model.UserRoleAdmin, Origin: model.UserOriginLDAP},
+		}, nil).Once()
+		source.On("Commit").Return(nil).Once()
+
+		sourceDB := &MockDB{}
+		sourceDB.On("Begin").Return(source, nil).Once()
+
+		exported, err := NewService(sourceDB, nil).ExportInventory()
+		assert.NoError(t, err)
+
+		var buf bytes.Buffer
+		assert.NoError(t, renderer.WriteInventory(&buf, format, kind, exported))
+
+		inventory, importErrs, err := renderer.ReadInventory(&buf, format, kind)
+		assert.NoError(t, err)
+		assert.Empty(t, importErrs)
+
+		// the clients of users are imported first, unless only users are imported
+		var clients []*model.Client
+		if kind == model.InventoryKindUsers {
+			clients = []*model.Client{{ID: clientID, Name: "room 1"}}
+		}
+
+		target := &MockTx{}
+		target.On("GetClients").Return(clients, nil)
+		target.On("GetPagers").Return([]*model.Pager{}, nil).Once()
+		target.On("GetUsers").Return([]*model.User{}, nil).Once()
+		if len(inventory.Clients) > 0 {
+			target.On("AddClient", mock.AnythingOfType("*model.Client")).Return(nil)
+		}
+		if len(inventory.Pagers) > 0 {
+			target.On("AddPager", mock.AnythingOfType("*model.Pager")).Return(nil)
+		}
+		target.On("AddUser", mock.AnythingOfType("*model.User")).Return(nil).Twice()
+		target.On("Commit").Return(nil).Once()
+
+		targetDB := &MockDB{}
+		targetDB.On("Begin").Return(target, nil).Once()
+
+		importErrs, err = NewService(targetDB, nil).ImportInventory(inventory, false)
+		assert.NoError(t, err)
+		assert.Empty(t, importErrs)
+
+		if assert.Len(t, inventory.Users, 2) {
+			local, directory := inventory.Users[0], inventory.Users[1]
+
+			// local users get a password nobody knows, until an admin sets theirs
+			assert.Equal(t, model.UserOriginLocal, local.Origin)
+			assert.NotEmpty(t, local.Password)
+			assert.False(t, comparePasswords(local.Password, "Secret12!"))
+			assert.False(t, comparePasswords(local.Password, ""))
+			assert.Equal(t, "room 1", local.Client.Name)
+
+			// directory users login through their directory only
+			assert.Equal(t, model.UserOriginLDAP, directory.Origin)
+			assert.Empty(t, directory.Password)
+			assert.Equal(t, model.UserRoleAdmin, directory.Role)
+		}
+
+		sourceDB.AssertExpectations(t)
+		source.AssertExpectations(t)
+		targetDB.AssertExpectations(t)
+		target.AssertExpectations(t)
+	}
+}
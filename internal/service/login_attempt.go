@@ -0,0 +1,34 @@
+package service
+
+import (
+	"time"
+
+	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/tracing"
+
+	"github.com/pkg/errors"
+)
+
+// ListLoginAttempts returns the failed logins since the given time, optionally of a single user
+func (service *defaultService) ListLoginAttempts(username string, since time.Time) (attempts []*model.LoginAttempt, err error) {
+	service, span := service.trace("ListLoginAttempts")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := service.db.Begin()
+	if err != nil {
+		return nil, errors.Wrap(err, "create transaction failed")
+	}
+
+	attempts, err = tx.GetLoginAttempts(username, since)
+	if err != nil {
+		service.logger().Error().
+			Err(err).
+			Msg("get login attempts failed")
+
+		tx.Rollback()
+		return nil, errors.Wrap(err, "get login attempts failed")
+	}
+
+	tx.Commit()
+	return attempts, nil
+}
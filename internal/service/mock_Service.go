@@ -3,19 +3,22 @@
 package service
 
 import mock "github.com/stretchr/testify/mock"
+import context "context"
 import model "github.com/pagient/pagient-server/internal/model"
+import time "time"
+import zerolog "github.com/rs/zerolog"
 
 // MockService is an autogenerated mock type for the Service type
 type MockService struct {
 	mock.Mock
 }
 
-// CallPatient provides a mock function with given fields: _a0
-func (_m *MockService) CallPatient(_a0 *model.Patient) error {
+// CallVisit provides a mock function with given fields: _a0
+func (_m *MockService) CallVisit(_a0 *model.Visit) error {
 	ret := _m.Called(_a0)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*model.Patient) error); ok {
+	if rf, ok := ret.Get(0).(func(*model.Visit) error); ok {
 		r0 = rf(_a0)
 	} else {
 		r0 = ret.Error(0)
@@ -38,6 +41,85 @@ func (_m *MockService) ChangeUserPassword(_a0 *model.User) error {
 	return r0
 }
 
+// ChangeUserRole provides a mock function with given fields: _a0
+func (_m *MockService) ChangeUserRole(_a0 *model.User) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.User) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CheckIn provides a mock function with given fields: _a0
+func (_m *MockService) CheckIn(_a0 *model.Visit) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Visit) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CloseVisit provides a mock function with given fields: _a0
+func (_m *MockService) CloseVisit(_a0 *model.Visit) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Visit) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CountVisitsByStatus provides a mock function with given fields:
+func (_m *MockService) CountVisitsByStatus() (map[model.VisitStatus]int, error) {
+	ret := _m.Called()
+
+	var r0 map[model.VisitStatus]int
+	if rf, ok := ret.Get(0).(func() map[model.VisitStatus]int); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[model.VisitStatus]int)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateAPIKey provides a mock function with given fields: _a0
+func (_m *MockService) CreateAPIKey(_a0 *model.APIKey) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.APIKey) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateClient provides a mock function with given fields: _a0
 func (_m *MockService) CreateClient(_a0 *model.Client) error {
 	ret := _m.Called(_a0)
@@ -52,12 +134,26 @@ func (_m *MockService) CreateClient(_a0 *model.Client) error {
 	return r0
 }
 
-// CreatePatient provides a mock function with given fields: _a0
-func (_m *MockService) CreatePatient(_a0 *model.Patient) error {
+// CreateEvent provides a mock function with given fields: _a0
+func (_m *MockService) CreateEvent(_a0 *model.Event) error {
 	ret := _m.Called(_a0)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*model.Patient) error); ok {
+	if rf, ok := ret.Get(0).(func(*model.Event) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreatePager provides a mock function with given fields: _a0
+func (_m *MockService) CreatePager(_a0 *model.Pager) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Pager) error); ok {
 		r0 = rf(_a0)
 	} else {
 		r0 = ret.Error(0)
@@ -94,6 +190,76 @@ func (_m *MockService) CreateUser(_a0 *model.User) error {
 	return r0
 }
 
+// DeleteAPIKey provides a mock function with given fields: _a0
+func (_m *MockService) DeleteAPIKey(_a0 *model.APIKey) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.APIKey) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteClient provides a mock function with given fields: _a0
+func (_m *MockService) DeleteClient(_a0 *model.Client) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Client) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteEventsBefore provides a mock function with given fields: _a0
+func (_m *MockService) DeleteEventsBefore(_a0 time.Time) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(time.Time) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteExpiredTokens provides a mock function with given fields:
+func (_m *MockService) DeleteExpiredTokens() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeletePager provides a mock function with given fields: _a0
+func (_m *MockService) DeletePager(_a0 *model.Pager) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Pager) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeletePatient provides a mock function with given fields: _a0
 func (_m *MockService) DeletePatient(_a0 *model.Patient) error {
 	ret := _m.Called(_a0)
@@ -119,19 +285,352 @@ func (_m *MockService) DeleteToken(_a0 *model.Token) error {
 		r0 = ret.Error(0)
 	}
 
-	return r0
+	return r0
+}
+
+// DeleteTokensByUser provides a mock function with given fields: _a0, _a1
+func (_m *MockService) DeleteTokensByUser(_a0 string, _a1 string) ([]*model.Token, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 []*model.Token
+	if rf, ok := ret.Get(0).(func(string, string) []*model.Token); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Token)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteUser provides a mock function with given fields: _a0
+func (_m *MockService) DeleteUser(_a0 *model.User) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.User) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExportInventory provides a mock function with given fields:
+func (_m *MockService) ExportInventory() (*model.Inventory, error) {
+	ret := _m.Called()
+
+	var r0 *model.Inventory
+	if rf, ok := ret.Get(0).(func() *model.Inventory); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Inventory)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ImportInventory provides a mock function with given fields: _a0, _a1
+func (_m *MockService) ImportInventory(_a0 *model.Inventory, _a1 bool) ([]*model.ImportError, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 []*model.ImportError
+	if rf, ok := ret.Get(0).(func(*model.Inventory, bool) []*model.ImportError); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.ImportError)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.Inventory, bool) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LinkUser provides a mock function with given fields: _a0
+func (_m *MockService) LinkUser(_a0 *model.User) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.User) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListAPIKeys provides a mock function with given fields: _a0
+func (_m *MockService) ListAPIKeys(_a0 string) ([]*model.APIKey, error) {
+	ret := _m.Called(_a0)
+
+	var r0 []*model.APIKey
+	if rf, ok := ret.Get(0).(func(string) []*model.APIKey); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.APIKey)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAuditEntries provides a mock function with given fields: _a0
+func (_m *MockService) ListAuditEntries(_a0 time.Time) ([]*model.AuditEntry, error) {
+	ret := _m.Called(_a0)
+
+	var r0 []*model.AuditEntry
+	if rf, ok := ret.Get(0).(func(time.Time) []*model.AuditEntry); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.AuditEntry)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListClients provides a mock function with given fields:
+func (_m *MockService) ListClients() ([]*model.Client, error) {
+	ret := _m.Called()
+
+	var r0 []*model.Client
+	if rf, ok := ret.Get(0).(func() []*model.Client); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Client)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListEventsAfter provides a mock function with given fields: _a0
+func (_m *MockService) ListEventsAfter(_a0 uint) ([]*model.Event, error) {
+	ret := _m.Called(_a0)
+
+	var r0 []*model.Event
+	if rf, ok := ret.Get(0).(func(uint) []*model.Event); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Event)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListLoginAttempts provides a mock function with given fields: _a0, _a1
+func (_m *MockService) ListLoginAttempts(_a0 string, _a1 time.Time) ([]*model.LoginAttempt, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 []*model.LoginAttempt
+	if rf, ok := ret.Get(0).(func(string, time.Time) []*model.LoginAttempt); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.LoginAttempt)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, time.Time) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPagerVisitsByStatus provides a mock function with given fields: _a0
+func (_m *MockService) ListPagerVisitsByStatus(_a0 ...model.VisitStatus) ([]*model.Visit, error) {
+	_va := make([]interface{}, len(_a0))
+	for _i := range _a0 {
+		_va[_i] = _a0[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 []*model.Visit
+	if rf, ok := ret.Get(0).(func(...model.VisitStatus) []*model.Visit); ok {
+		r0 = rf(_a0...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Visit)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(...model.VisitStatus) error); ok {
+		r1 = rf(_a0...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPagers provides a mock function with given fields:
+func (_m *MockService) ListPagers() ([]*model.Pager, error) {
+	ret := _m.Called()
+
+	var r0 []*model.Pager
+	if rf, ok := ret.Get(0).(func() []*model.Pager); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Pager)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPatients provides a mock function with given fields:
+func (_m *MockService) ListPatients() ([]*model.Patient, error) {
+	ret := _m.Called()
+
+	var r0 []*model.Patient
+	if rf, ok := ret.Get(0).(func() []*model.Patient); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Patient)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTokensByUser provides a mock function with given fields: _a0
+func (_m *MockService) ListTokensByUser(_a0 string) ([]*model.Token, error) {
+	ret := _m.Called(_a0)
+
+	var r0 []*model.Token
+	if rf, ok := ret.Get(0).(func(string) []*model.Token); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Token)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListUsers provides a mock function with given fields:
+func (_m *MockService) ListUsers() ([]*model.User, error) {
+	ret := _m.Called()
+
+	var r0 []*model.User
+	if rf, ok := ret.Get(0).(func() []*model.User); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// ListClients provides a mock function with given fields:
-func (_m *MockService) ListClients() ([]*model.Client, error) {
+// ListVisits provides a mock function with given fields:
+func (_m *MockService) ListVisits() ([]*model.Visit, error) {
 	ret := _m.Called()
 
-	var r0 []*model.Client
-	if rf, ok := ret.Get(0).(func() []*model.Client); ok {
+	var r0 []*model.Visit
+	if rf, ok := ret.Get(0).(func() []*model.Visit); ok {
 		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*model.Client)
+			r0 = ret.Get(0).([]*model.Visit)
 		}
 	}
 
@@ -145,51 +644,52 @@ func (_m *MockService) ListClients() ([]*model.Client, error) {
 	return r0, r1
 }
 
-// ListPagerPatientsByStatus provides a mock function with given fields: _a0
-func (_m *MockService) ListPagerPatientsByStatus(_a0 ...model.PatientStatus) ([]*model.Patient, error) {
-	_va := make([]interface{}, len(_a0))
-	for _i := range _a0 {
-		_va[_i] = _a0[_i]
-	}
-	var _ca []interface{}
-	_ca = append(_ca, _va...)
-	ret := _m.Called(_ca...)
+// Login provides a mock function with given fields: _a0, _a1, _a2
+func (_m *MockService) Login(_a0 string, _a1 string, _a2 string) (*model.User, bool, error) {
+	ret := _m.Called(_a0, _a1, _a2)
 
-	var r0 []*model.Patient
-	if rf, ok := ret.Get(0).(func(...model.PatientStatus) []*model.Patient); ok {
-		r0 = rf(_a0...)
+	var r0 *model.User
+	if rf, ok := ret.Get(0).(func(string, string, string) *model.User); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*model.Patient)
+			r0 = ret.Get(0).(*model.User)
 		}
 	}
 
-	var r1 error
-	if rf, ok := ret.Get(1).(func(...model.PatientStatus) error); ok {
-		r1 = rf(_a0...)
+	var r1 bool
+	if rf, ok := ret.Get(1).(func(string, string, string) bool); ok {
+		r1 = rf(_a0, _a1, _a2)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(bool)
 	}
 
-	return r0, r1
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string, string, string) error); ok {
+		r2 = rf(_a0, _a1, _a2)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
-// ListPagers provides a mock function with given fields:
-func (_m *MockService) ListPagers() ([]*model.Pager, error) {
-	ret := _m.Called()
+// LoginExternal provides a mock function with given fields: _a0, _a1
+func (_m *MockService) LoginExternal(_a0 *model.User, _a1 string) (*model.User, error) {
+	ret := _m.Called(_a0, _a1)
 
-	var r0 []*model.Pager
-	if rf, ok := ret.Get(0).(func() []*model.Pager); ok {
-		r0 = rf()
+	var r0 *model.User
+	if rf, ok := ret.Get(0).(func(*model.User, string) *model.User); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*model.Pager)
+			r0 = ret.Get(0).(*model.User)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(*model.User, string) error); ok {
+		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -197,13 +697,13 @@ func (_m *MockService) ListPagers() ([]*model.Pager, error) {
 	return r0, r1
 }
 
-// ListPatients provides a mock function with given fields:
-func (_m *MockService) ListPatients() ([]*model.Patient, error) {
-	ret := _m.Called()
+// PurgeFinishedPatients provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *MockService) PurgeFinishedPatients(_a0 time.Time, _a1 model.PurgeMode, _a2 string, _a3 bool) ([]*model.Patient, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
 
 	var r0 []*model.Patient
-	if rf, ok := ret.Get(0).(func() []*model.Patient); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(time.Time, model.PurgeMode, string, bool) []*model.Patient); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*model.Patient)
@@ -211,8 +711,8 @@ func (_m *MockService) ListPatients() ([]*model.Patient, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(time.Time, model.PurgeMode, string, bool) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -220,22 +720,36 @@ func (_m *MockService) ListPatients() ([]*model.Patient, error) {
 	return r0, r1
 }
 
-// ListTokensByUser provides a mock function with given fields: _a0
-func (_m *MockService) ListTokensByUser(_a0 string) ([]*model.Token, error) {
-	ret := _m.Called(_a0)
+// RefreshToken provides a mock function with given fields: _a0, _a1
+func (_m *MockService) RefreshToken(_a0 string, _a1 *model.Token) error {
+	ret := _m.Called(_a0, _a1)
 
-	var r0 []*model.Token
-	if rf, ok := ret.Get(0).(func(string) []*model.Token); ok {
-		r0 = rf(_a0)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, *model.Token) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReportVisits provides a mock function with given fields: _a0, _a1, _a2
+func (_m *MockService) ReportVisits(_a0 time.Time, _a1 time.Time, _a2 model.ReportGroup) ([]*model.ReportRow, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 []*model.ReportRow
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time, model.ReportGroup) []*model.ReportRow); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*model.Token)
+			r0 = ret.Get(0).([]*model.ReportRow)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(_a0)
+	if rf, ok := ret.Get(1).(func(time.Time, time.Time, model.ReportGroup) error); ok {
+		r1 = rf(_a0, _a1, _a2)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -243,22 +757,22 @@ func (_m *MockService) ListTokensByUser(_a0 string) ([]*model.Token, error) {
 	return r0, r1
 }
 
-// ListUsers provides a mock function with given fields:
-func (_m *MockService) ListUsers() ([]*model.User, error) {
-	ret := _m.Called()
+// ShowAPIKey provides a mock function with given fields: _a0
+func (_m *MockService) ShowAPIKey(_a0 uint) (*model.APIKey, error) {
+	ret := _m.Called(_a0)
 
-	var r0 []*model.User
-	if rf, ok := ret.Get(0).(func() []*model.User); ok {
-		r0 = rf()
+	var r0 *model.APIKey
+	if rf, ok := ret.Get(0).(func(uint) *model.APIKey); ok {
+		r0 = rf(_a0)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*model.User)
+			r0 = ret.Get(0).(*model.APIKey)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(_a0)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -266,34 +780,48 @@ func (_m *MockService) ListUsers() ([]*model.User, error) {
 	return r0, r1
 }
 
-// Login provides a mock function with given fields: _a0, _a1
-func (_m *MockService) Login(_a0 string, _a1 string) (*model.User, bool, error) {
-	ret := _m.Called(_a0, _a1)
+// ShowAPIKeyByKey provides a mock function with given fields: _a0
+func (_m *MockService) ShowAPIKeyByKey(_a0 string) (*model.APIKey, error) {
+	ret := _m.Called(_a0)
 
-	var r0 *model.User
-	if rf, ok := ret.Get(0).(func(string, string) *model.User); ok {
-		r0 = rf(_a0, _a1)
+	var r0 *model.APIKey
+	if rf, ok := ret.Get(0).(func(string) *model.APIKey); ok {
+		r0 = rf(_a0)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*model.User)
+			r0 = ret.Get(0).(*model.APIKey)
 		}
 	}
 
-	var r1 bool
-	if rf, ok := ret.Get(1).(func(string, string) bool); ok {
-		r1 = rf(_a0, _a1)
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
 	} else {
-		r1 = ret.Get(1).(bool)
+		r1 = ret.Error(1)
 	}
 
-	var r2 error
-	if rf, ok := ret.Get(2).(func(string, string) error); ok {
-		r2 = rf(_a0, _a1)
+	return r0, r1
+}
+
+// ShowAverageWaitTime provides a mock function with given fields:
+func (_m *MockService) ShowAverageWaitTime() (time.Duration, error) {
+	ret := _m.Called()
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
 	} else {
-		r2 = ret.Error(2)
+		r0 = ret.Get(0).(time.Duration)
 	}
 
-	return r0, r1, r2
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // ShowClient provides a mock function with given fields: _a0
@@ -411,6 +939,29 @@ func (_m *MockService) ShowToken(_a0 string) (*model.Token, error) {
 	return r0, r1
 }
 
+// ShowTokenByID provides a mock function with given fields: _a0
+func (_m *MockService) ShowTokenByID(_a0 uint) (*model.Token, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *model.Token
+	if rf, ok := ret.Get(0).(func(uint) *model.Token); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Token)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ShowUser provides a mock function with given fields: _a0
 func (_m *MockService) ShowUser(_a0 string) (*model.User, error) {
 	ret := _m.Called(_a0)
@@ -457,6 +1008,85 @@ func (_m *MockService) ShowUserByToken(_a0 string) (*model.User, error) {
 	return r0, r1
 }
 
+// ShowVisit provides a mock function with given fields: _a0
+func (_m *MockService) ShowVisit(_a0 uint) (*model.Visit, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *model.Visit
+	if rf, ok := ret.Get(0).(func(uint) *model.Visit); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Visit)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TouchAPIKey provides a mock function with given fields: _a0
+func (_m *MockService) TouchAPIKey(_a0 *model.APIKey) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.APIKey) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TouchToken provides a mock function with given fields: _a0
+func (_m *MockService) TouchToken(_a0 *model.Token) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Token) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateClient provides a mock function with given fields: _a0
+func (_m *MockService) UpdateClient(_a0 *model.Client) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Client) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePager provides a mock function with given fields: _a0
+func (_m *MockService) UpdatePager(_a0 *model.Pager) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Pager) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdatePatient provides a mock function with given fields: _a0
 func (_m *MockService) UpdatePatient(_a0 *model.Patient) error {
 	ret := _m.Called(_a0)
@@ -470,3 +1100,77 @@ func (_m *MockService) UpdatePatient(_a0 *model.Patient) error {
 
 	return r0
 }
+
+// UpdateQueuePositions provides a mock function with given fields: _a0
+func (_m *MockService) UpdateQueuePositions(_a0 []*model.QueuePosition) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]*model.QueuePosition) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateUser provides a mock function with given fields: _a0
+func (_m *MockService) UpdateUser(_a0 *model.User) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.User) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateVisit provides a mock function with given fields: _a0
+func (_m *MockService) UpdateVisit(_a0 *model.Visit) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Visit) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WithContext provides a mock function with given fields: _a0
+func (_m *MockService) WithContext(_a0 context.Context) Service {
+	ret := _m.Called(_a0)
+
+	var r0 Service
+	if rf, ok := ret.Get(0).(func(context.Context) Service); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(Service)
+		}
+	}
+
+	return r0
+}
+
+// WithLogger provides a mock function with given fields: _a0
+func (_m *MockService) WithLogger(_a0 zerolog.Logger) Service {
+	ret := _m.Called(_a0)
+
+	var r0 Service
+	if rf, ok := ret.Get(0).(func(zerolog.Logger) Service); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(Service)
+		}
+	}
+
+	return r0
+}
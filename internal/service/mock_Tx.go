@@ -3,6 +3,7 @@
 package service
 
 import mock "github.com/stretchr/testify/mock"
+import time "time"
 import model "github.com/pagient/pagient-server/internal/model"
 
 // MockTx is an autogenerated mock type for the Tx type
@@ -10,6 +11,34 @@ type MockTx struct {
 	mock.Mock
 }
 
+// AddAPIKey provides a mock function with given fields: _a0
+func (_m *MockTx) AddAPIKey(_a0 *model.APIKey) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.APIKey) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddAuditEntry provides a mock function with given fields: _a0
+func (_m *MockTx) AddAuditEntry(_a0 *model.AuditEntry) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.AuditEntry) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // AddClient provides a mock function with given fields: _a0
 func (_m *MockTx) AddClient(_a0 *model.Client) error {
 	ret := _m.Called(_a0)
@@ -24,6 +53,48 @@ func (_m *MockTx) AddClient(_a0 *model.Client) error {
 	return r0
 }
 
+// AddEvent provides a mock function with given fields: _a0
+func (_m *MockTx) AddEvent(_a0 *model.Event) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Event) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddLoginAttempt provides a mock function with given fields: _a0
+func (_m *MockTx) AddLoginAttempt(_a0 *model.LoginAttempt) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.LoginAttempt) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddPager provides a mock function with given fields: _a0
+func (_m *MockTx) AddPager(_a0 *model.Pager) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Pager) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // AddPatient provides a mock function with given fields: _a0
 func (_m *MockTx) AddPatient(_a0 *model.Patient) error {
 	ret := _m.Called(_a0)
@@ -66,6 +137,55 @@ func (_m *MockTx) AddUser(_a0 *model.User) error {
 	return r0
 }
 
+// AddVisit provides a mock function with given fields: _a0
+func (_m *MockTx) AddVisit(_a0 *model.Visit) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Visit) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AnonymizePatient provides a mock function with given fields: _a0
+func (_m *MockTx) AnonymizePatient(_a0 *model.Patient) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Patient) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CloseVisitsByClient provides a mock function with given fields: _a0, _a1
+func (_m *MockTx) CloseVisitsByClient(_a0 uint, _a1 ...bool) error {
+	_va := make([]interface{}, len(_a1))
+	for _i := range _a1 {
+		_va[_i] = _a1[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _a0)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, ...bool) error); ok {
+		r0 = rf(_a0, _a1...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Commit provides a mock function with given fields:
 func (_m *MockTx) Commit() error {
 	ret := _m.Called()
@@ -80,16 +200,39 @@ func (_m *MockTx) Commit() error {
 	return r0
 }
 
-// GetClient provides a mock function with given fields: _a0
-func (_m *MockTx) GetClient(_a0 uint) (*model.Client, error) {
+// CountVisitsByStatus provides a mock function with given fields:
+func (_m *MockTx) CountVisitsByStatus() (map[model.VisitStatus]int, error) {
+	ret := _m.Called()
+
+	var r0 map[model.VisitStatus]int
+	if rf, ok := ret.Get(0).(func() map[model.VisitStatus]int); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[model.VisitStatus]int)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAPIKey provides a mock function with given fields: _a0
+func (_m *MockTx) GetAPIKey(_a0 uint) (*model.APIKey, error) {
 	ret := _m.Called(_a0)
 
-	var r0 *model.Client
-	if rf, ok := ret.Get(0).(func(uint) *model.Client); ok {
+	var r0 *model.APIKey
+	if rf, ok := ret.Get(0).(func(uint) *model.APIKey); ok {
 		r0 = rf(_a0)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*model.Client)
+			r0 = ret.Get(0).(*model.APIKey)
 		}
 	}
 
@@ -103,16 +246,16 @@ func (_m *MockTx) GetClient(_a0 uint) (*model.Client, error) {
 	return r0, r1
 }
 
-// GetClientByUser provides a mock function with given fields: _a0
-func (_m *MockTx) GetClientByUser(_a0 string) (*model.Client, error) {
+// GetAPIKeyByHash provides a mock function with given fields: _a0
+func (_m *MockTx) GetAPIKeyByHash(_a0 string) (*model.APIKey, error) {
 	ret := _m.Called(_a0)
 
-	var r0 *model.Client
-	if rf, ok := ret.Get(0).(func(string) *model.Client); ok {
+	var r0 *model.APIKey
+	if rf, ok := ret.Get(0).(func(string) *model.APIKey); ok {
 		r0 = rf(_a0)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*model.Client)
+			r0 = ret.Get(0).(*model.APIKey)
 		}
 	}
 
@@ -126,22 +269,22 @@ func (_m *MockTx) GetClientByUser(_a0 string) (*model.Client, error) {
 	return r0, r1
 }
 
-// GetClients provides a mock function with given fields:
-func (_m *MockTx) GetClients() ([]*model.Client, error) {
-	ret := _m.Called()
+// GetAPIKeys provides a mock function with given fields: _a0
+func (_m *MockTx) GetAPIKeys(_a0 string) ([]*model.APIKey, error) {
+	ret := _m.Called(_a0)
 
-	var r0 []*model.Client
-	if rf, ok := ret.Get(0).(func() []*model.Client); ok {
-		r0 = rf()
+	var r0 []*model.APIKey
+	if rf, ok := ret.Get(0).(func(string) []*model.APIKey); ok {
+		r0 = rf(_a0)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*model.Client)
+			r0 = ret.Get(0).([]*model.APIKey)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -149,21 +292,21 @@ func (_m *MockTx) GetClients() ([]*model.Client, error) {
 	return r0, r1
 }
 
-// GetPager provides a mock function with given fields: _a0
-func (_m *MockTx) GetPager(_a0 uint) (*model.Pager, error) {
+// GetAuditEntries provides a mock function with given fields: _a0
+func (_m *MockTx) GetAuditEntries(_a0 time.Time) ([]*model.AuditEntry, error) {
 	ret := _m.Called(_a0)
 
-	var r0 *model.Pager
-	if rf, ok := ret.Get(0).(func(uint) *model.Pager); ok {
+	var r0 []*model.AuditEntry
+	if rf, ok := ret.Get(0).(func(time.Time) []*model.AuditEntry); ok {
 		r0 = rf(_a0)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*model.Pager)
+			r0 = ret.Get(0).([]*model.AuditEntry)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(uint) error); ok {
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
 		r1 = rf(_a0)
 	} else {
 		r1 = ret.Error(1)
@@ -172,22 +315,22 @@ func (_m *MockTx) GetPager(_a0 uint) (*model.Pager, error) {
 	return r0, r1
 }
 
-// GetPagers provides a mock function with given fields:
-func (_m *MockTx) GetPagers() ([]*model.Pager, error) {
-	ret := _m.Called()
+// GetClient provides a mock function with given fields: _a0
+func (_m *MockTx) GetClient(_a0 uint) (*model.Client, error) {
+	ret := _m.Called(_a0)
 
-	var r0 []*model.Pager
-	if rf, ok := ret.Get(0).(func() []*model.Pager); ok {
-		r0 = rf()
+	var r0 *model.Client
+	if rf, ok := ret.Get(0).(func(uint) *model.Client); ok {
+		r0 = rf(_a0)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*model.Pager)
+			r0 = ret.Get(0).(*model.Client)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(_a0)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -195,21 +338,21 @@ func (_m *MockTx) GetPagers() ([]*model.Pager, error) {
 	return r0, r1
 }
 
-// GetPatient provides a mock function with given fields: _a0
-func (_m *MockTx) GetPatient(_a0 uint) (*model.Patient, error) {
+// GetClientByUser provides a mock function with given fields: _a0
+func (_m *MockTx) GetClientByUser(_a0 string) (*model.Client, error) {
 	ret := _m.Called(_a0)
 
-	var r0 *model.Patient
-	if rf, ok := ret.Get(0).(func(uint) *model.Patient); ok {
+	var r0 *model.Client
+	if rf, ok := ret.Get(0).(func(string) *model.Client); ok {
 		r0 = rf(_a0)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*model.Patient)
+			r0 = ret.Get(0).(*model.Client)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(uint) error); ok {
+	if rf, ok := ret.Get(1).(func(string) error); ok {
 		r1 = rf(_a0)
 	} else {
 		r1 = ret.Error(1)
@@ -218,16 +361,16 @@ func (_m *MockTx) GetPatient(_a0 uint) (*model.Patient, error) {
 	return r0, r1
 }
 
-// GetPatients provides a mock function with given fields:
-func (_m *MockTx) GetPatients() ([]*model.Patient, error) {
+// GetClients provides a mock function with given fields:
+func (_m *MockTx) GetClients() ([]*model.Client, error) {
 	ret := _m.Called()
 
-	var r0 []*model.Patient
-	if rf, ok := ret.Get(0).(func() []*model.Patient); ok {
+	var r0 []*model.Client
+	if rf, ok := ret.Get(0).(func() []*model.Client); ok {
 		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*model.Patient)
+			r0 = ret.Get(0).([]*model.Client)
 		}
 	}
 
@@ -241,29 +384,22 @@ func (_m *MockTx) GetPatients() ([]*model.Patient, error) {
 	return r0, r1
 }
 
-// GetPatientsByClient provides a mock function with given fields: _a0, _a1
-func (_m *MockTx) GetPatientsByClient(_a0 uint, _a1 ...bool) ([]*model.Patient, error) {
-	_va := make([]interface{}, len(_a1))
-	for _i := range _a1 {
-		_va[_i] = _a1[_i]
-	}
-	var _ca []interface{}
-	_ca = append(_ca, _a0)
-	_ca = append(_ca, _va...)
-	ret := _m.Called(_ca...)
+// GetEventsAfter provides a mock function with given fields: _a0
+func (_m *MockTx) GetEventsAfter(_a0 uint) ([]*model.Event, error) {
+	ret := _m.Called(_a0)
 
-	var r0 []*model.Patient
-	if rf, ok := ret.Get(0).(func(uint, ...bool) []*model.Patient); ok {
-		r0 = rf(_a0, _a1...)
+	var r0 []*model.Event
+	if rf, ok := ret.Get(0).(func(uint) []*model.Event); ok {
+		r0 = rf(_a0)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*model.Patient)
+			r0 = ret.Get(0).([]*model.Event)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(uint, ...bool) error); ok {
-		r1 = rf(_a0, _a1...)
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(_a0)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -271,19 +407,13 @@ func (_m *MockTx) GetPatientsByClient(_a0 uint, _a1 ...bool) ([]*model.Patient,
 	return r0, r1
 }
 
-// GetPatientsWithPagerByStatus provides a mock function with given fields: _a0
-func (_m *MockTx) GetPatientsWithPagerByStatus(_a0 ...model.PatientStatus) ([]*model.Patient, error) {
-	_va := make([]interface{}, len(_a0))
-	for _i := range _a0 {
-		_va[_i] = _a0[_i]
-	}
-	var _ca []interface{}
-	_ca = append(_ca, _va...)
-	ret := _m.Called(_ca...)
+// GetFinishedPatientsBefore provides a mock function with given fields: _a0
+func (_m *MockTx) GetFinishedPatientsBefore(_a0 time.Time) ([]*model.Patient, error) {
+	ret := _m.Called(_a0)
 
 	var r0 []*model.Patient
-	if rf, ok := ret.Get(0).(func(...model.PatientStatus) []*model.Patient); ok {
-		r0 = rf(_a0...)
+	if rf, ok := ret.Get(0).(func(time.Time) []*model.Patient); ok {
+		r0 = rf(_a0)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*model.Patient)
@@ -291,8 +421,8 @@ func (_m *MockTx) GetPatientsWithPagerByStatus(_a0 ...model.PatientStatus) ([]*m
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(...model.PatientStatus) error); ok {
-		r1 = rf(_a0...)
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(_a0)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -300,22 +430,22 @@ func (_m *MockTx) GetPatientsWithPagerByStatus(_a0 ...model.PatientStatus) ([]*m
 	return r0, r1
 }
 
-// GetToken provides a mock function with given fields: _a0
-func (_m *MockTx) GetToken(_a0 string) (*model.Token, error) {
-	ret := _m.Called(_a0)
+// GetLoginAttempts provides a mock function with given fields: _a0, _a1
+func (_m *MockTx) GetLoginAttempts(_a0 string, _a1 time.Time) ([]*model.LoginAttempt, error) {
+	ret := _m.Called(_a0, _a1)
 
-	var r0 *model.Token
-	if rf, ok := ret.Get(0).(func(string) *model.Token); ok {
-		r0 = rf(_a0)
+	var r0 []*model.LoginAttempt
+	if rf, ok := ret.Get(0).(func(string, time.Time) []*model.LoginAttempt); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*model.Token)
+			r0 = ret.Get(0).([]*model.LoginAttempt)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(_a0)
+	if rf, ok := ret.Get(1).(func(string, time.Time) error); ok {
+		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -323,21 +453,21 @@ func (_m *MockTx) GetToken(_a0 string) (*model.Token, error) {
 	return r0, r1
 }
 
-// GetTokensByUser provides a mock function with given fields: _a0
-func (_m *MockTx) GetTokensByUser(_a0 string) ([]*model.Token, error) {
+// GetPager provides a mock function with given fields: _a0
+func (_m *MockTx) GetPager(_a0 uint) (*model.Pager, error) {
 	ret := _m.Called(_a0)
 
-	var r0 []*model.Token
-	if rf, ok := ret.Get(0).(func(string) []*model.Token); ok {
+	var r0 *model.Pager
+	if rf, ok := ret.Get(0).(func(uint) *model.Pager); ok {
 		r0 = rf(_a0)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*model.Token)
+			r0 = ret.Get(0).(*model.Pager)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
 		r1 = rf(_a0)
 	} else {
 		r1 = ret.Error(1)
@@ -346,8 +476,8 @@ func (_m *MockTx) GetTokensByUser(_a0 string) ([]*model.Token, error) {
 	return r0, r1
 }
 
-// GetUnassignedPagers provides a mock function with given fields:
-func (_m *MockTx) GetUnassignedPagers() ([]*model.Pager, error) {
+// GetPagers provides a mock function with given fields:
+func (_m *MockTx) GetPagers() ([]*model.Pager, error) {
 	ret := _m.Called()
 
 	var r0 []*model.Pager
@@ -369,21 +499,21 @@ func (_m *MockTx) GetUnassignedPagers() ([]*model.Pager, error) {
 	return r0, r1
 }
 
-// GetUser provides a mock function with given fields: _a0
-func (_m *MockTx) GetUser(_a0 string) (*model.User, error) {
+// GetPatient provides a mock function with given fields: _a0
+func (_m *MockTx) GetPatient(_a0 uint) (*model.Patient, error) {
 	ret := _m.Called(_a0)
 
-	var r0 *model.User
-	if rf, ok := ret.Get(0).(func(string) *model.User); ok {
+	var r0 *model.Patient
+	if rf, ok := ret.Get(0).(func(uint) *model.Patient); ok {
 		r0 = rf(_a0)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*model.User)
+			r0 = ret.Get(0).(*model.Patient)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
 		r1 = rf(_a0)
 	} else {
 		r1 = ret.Error(1)
@@ -392,21 +522,348 @@ func (_m *MockTx) GetUser(_a0 string) (*model.User, error) {
 	return r0, r1
 }
 
-// GetUserByToken provides a mock function with given fields: _a0
-func (_m *MockTx) GetUserByToken(_a0 string) (*model.User, error) {
-	ret := _m.Called(_a0)
+// GetPatients provides a mock function with given fields:
+func (_m *MockTx) GetPatients() ([]*model.Patient, error) {
+	ret := _m.Called()
+
+	var r0 []*model.Patient
+	if rf, ok := ret.Get(0).(func() []*model.Patient); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Patient)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetToken provides a mock function with given fields: _a0
+func (_m *MockTx) GetToken(_a0 string) (*model.Token, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *model.Token
+	if rf, ok := ret.Get(0).(func(string) *model.Token); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Token)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTokenByID provides a mock function with given fields: _a0
+func (_m *MockTx) GetTokenByID(_a0 uint) (*model.Token, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *model.Token
+	if rf, ok := ret.Get(0).(func(uint) *model.Token); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Token)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTokenByRefreshHash provides a mock function with given fields: _a0
+func (_m *MockTx) GetTokenByRefreshHash(_a0 string) (*model.Token, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *model.Token
+	if rf, ok := ret.Get(0).(func(string) *model.Token); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Token)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTokensByUser provides a mock function with given fields: _a0
+func (_m *MockTx) GetTokensByUser(_a0 string) ([]*model.Token, error) {
+	ret := _m.Called(_a0)
+
+	var r0 []*model.Token
+	if rf, ok := ret.Get(0).(func(string) []*model.Token); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Token)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUnassignedPagers provides a mock function with given fields:
+func (_m *MockTx) GetUnassignedPagers() ([]*model.Pager, error) {
+	ret := _m.Called()
+
+	var r0 []*model.Pager
+	if rf, ok := ret.Get(0).(func() []*model.Pager); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Pager)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUser provides a mock function with given fields: _a0
+func (_m *MockTx) GetUser(_a0 string) (*model.User, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *model.User
+	if rf, ok := ret.Get(0).(func(string) *model.User); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserBySubject provides a mock function with given fields: _a0
+func (_m *MockTx) GetUserBySubject(_a0 string) (*model.User, error) {
+	ret := _m.Called(_a0)
 
 	var r0 *model.User
 	if rf, ok := ret.Get(0).(func(string) *model.User); ok {
 		r0 = rf(_a0)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*model.User)
+			r0 = ret.Get(0).(*model.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserByToken provides a mock function with given fields: _a0
+func (_m *MockTx) GetUserByToken(_a0 string) (*model.User, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *model.User
+	if rf, ok := ret.Get(0).(func(string) *model.User); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUsers provides a mock function with given fields:
+func (_m *MockTx) GetUsers() ([]*model.User, error) {
+	ret := _m.Called()
+
+	var r0 []*model.User
+	if rf, ok := ret.Get(0).(func() []*model.User); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetVisit provides a mock function with given fields: _a0
+func (_m *MockTx) GetVisit(_a0 uint) (*model.Visit, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *model.Visit
+	if rf, ok := ret.Get(0).(func(uint) *model.Visit); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Visit)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetVisits provides a mock function with given fields:
+func (_m *MockTx) GetVisits() ([]*model.Visit, error) {
+	ret := _m.Called()
+
+	var r0 []*model.Visit
+	if rf, ok := ret.Get(0).(func() []*model.Visit); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Visit)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NextVisitTicket provides a mock function with given fields: _a0
+func (_m *MockTx) NextVisitTicket(_a0 time.Time) (uint, error) {
+	ret := _m.Called(_a0)
+
+	var r0 uint
+	if rf, ok := ret.Get(0).(func(time.Time) uint); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(uint)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetVisitsByClient provides a mock function with given fields: _a0, _a1
+func (_m *MockTx) GetVisitsByClient(_a0 uint, _a1 ...bool) ([]*model.Visit, error) {
+	_va := make([]interface{}, len(_a1))
+	for _i := range _a1 {
+		_va[_i] = _a1[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _a0)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 []*model.Visit
+	if rf, ok := ret.Get(0).(func(uint, ...bool) []*model.Visit); ok {
+		r0 = rf(_a0, _a1...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Visit)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint, ...bool) error); ok {
+		r1 = rf(_a0, _a1...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetVisitsByPatient provides a mock function with given fields: _a0
+func (_m *MockTx) GetVisitsByPatient(_a0 uint) ([]*model.Visit, error) {
+	ret := _m.Called(_a0)
+
+	var r0 []*model.Visit
+	if rf, ok := ret.Get(0).(func(uint) []*model.Visit); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Visit)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
 		r1 = rf(_a0)
 	} else {
 		r1 = ret.Error(1)
@@ -415,22 +872,45 @@ func (_m *MockTx) GetUserByToken(_a0 string) (*model.User, error) {
 	return r0, r1
 }
 
-// GetUsers provides a mock function with given fields:
-func (_m *MockTx) GetUsers() ([]*model.User, error) {
-	ret := _m.Called()
+// GetVisitsCheckedInBetween provides a mock function with given fields: _a0, _a1
+func (_m *MockTx) GetVisitsCheckedInBetween(_a0 time.Time, _a1 time.Time) ([]*model.Visit, error) {
+	ret := _m.Called(_a0, _a1)
 
-	var r0 []*model.User
-	if rf, ok := ret.Get(0).(func() []*model.User); ok {
-		r0 = rf()
+	var r0 []*model.Visit
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time) []*model.Visit); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*model.User)
+			r0 = ret.Get(0).([]*model.Visit)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(time.Time, time.Time) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetVisitsFirstCalledAfter provides a mock function with given fields: _a0
+func (_m *MockTx) GetVisitsFirstCalledAfter(_a0 time.Time) ([]*model.Visit, error) {
+	ret := _m.Called(_a0)
+
+	var r0 []*model.Visit
+	if rf, ok := ret.Get(0).(func(time.Time) []*model.Visit); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Visit)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(_a0)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -438,8 +918,37 @@ func (_m *MockTx) GetUsers() ([]*model.User, error) {
 	return r0, r1
 }
 
-// MarkPatientsInactiveByClient provides a mock function with given fields: _a0
-func (_m *MockTx) MarkPatientsInactiveByClient(_a0 uint) error {
+// GetVisitsWithPagerByStatus provides a mock function with given fields: _a0
+func (_m *MockTx) GetVisitsWithPagerByStatus(_a0 ...model.VisitStatus) ([]*model.Visit, error) {
+	_va := make([]interface{}, len(_a0))
+	for _i := range _a0 {
+		_va[_i] = _a0[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 []*model.Visit
+	if rf, ok := ret.Get(0).(func(...model.VisitStatus) []*model.Visit); ok {
+		r0 = rf(_a0...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Visit)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(...model.VisitStatus) error); ok {
+		r1 = rf(_a0...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkVisitsInactiveByClient provides a mock function with given fields: _a0
+func (_m *MockTx) MarkVisitsInactiveByClient(_a0 uint) error {
 	ret := _m.Called(_a0)
 
 	var r0 error
@@ -452,12 +961,12 @@ func (_m *MockTx) MarkPatientsInactiveByClient(_a0 uint) error {
 	return r0
 }
 
-// RemovePatient provides a mock function with given fields: _a0
-func (_m *MockTx) RemovePatient(_a0 *model.Patient) error {
+// RemoveAPIKey provides a mock function with given fields: _a0
+func (_m *MockTx) RemoveAPIKey(_a0 *model.APIKey) error {
 	ret := _m.Called(_a0)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*model.Patient) error); ok {
+	if rf, ok := ret.Get(0).(func(*model.APIKey) error); ok {
 		r0 = rf(_a0)
 	} else {
 		r0 = ret.Error(0)
@@ -466,20 +975,69 @@ func (_m *MockTx) RemovePatient(_a0 *model.Patient) error {
 	return r0
 }
 
-// RemovePatientsByClient provides a mock function with given fields: _a0, _a1
-func (_m *MockTx) RemovePatientsByClient(_a0 uint, _a1 ...bool) error {
-	_va := make([]interface{}, len(_a1))
-	for _i := range _a1 {
-		_va[_i] = _a1[_i]
+// RemoveClient provides a mock function with given fields: _a0
+func (_m *MockTx) RemoveClient(_a0 *model.Client) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Client) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
 	}
-	var _ca []interface{}
-	_ca = append(_ca, _a0)
-	_ca = append(_ca, _va...)
-	ret := _m.Called(_ca...)
+
+	return r0
+}
+
+// RemoveEventsBefore provides a mock function with given fields: _a0
+func (_m *MockTx) RemoveEventsBefore(_a0 time.Time) error {
+	ret := _m.Called(_a0)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(uint, ...bool) error); ok {
-		r0 = rf(_a0, _a1...)
+	if rf, ok := ret.Get(0).(func(time.Time) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveExpiredTokens provides a mock function with given fields: _a0
+func (_m *MockTx) RemoveExpiredTokens(_a0 time.Time) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(time.Time) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemovePager provides a mock function with given fields: _a0
+func (_m *MockTx) RemovePager(_a0 *model.Pager) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Pager) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemovePatient provides a mock function with given fields: _a0
+func (_m *MockTx) RemovePatient(_a0 *model.Patient) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Patient) error); ok {
+		r0 = rf(_a0)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -501,6 +1059,34 @@ func (_m *MockTx) RemoveToken(_a0 *model.Token) error {
 	return r0
 }
 
+// RemoveTokensByFamily provides a mock function with given fields: _a0
+func (_m *MockTx) RemoveTokensByFamily(_a0 string) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveUser provides a mock function with given fields: _a0
+func (_m *MockTx) RemoveUser(_a0 *model.User) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.User) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Rollback provides a mock function with given fields:
 func (_m *MockTx) Rollback() error {
 	ret := _m.Called()
@@ -515,6 +1101,48 @@ func (_m *MockTx) Rollback() error {
 	return r0
 }
 
+// UpdateAPIKeyLastUsed provides a mock function with given fields: _a0
+func (_m *MockTx) UpdateAPIKeyLastUsed(_a0 *model.APIKey) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.APIKey) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateClient provides a mock function with given fields: _a0
+func (_m *MockTx) UpdateClient(_a0 *model.Client) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Client) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePager provides a mock function with given fields: _a0
+func (_m *MockTx) UpdatePager(_a0 *model.Pager) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Pager) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdatePatient provides a mock function with given fields: _a0
 func (_m *MockTx) UpdatePatient(_a0 *model.Patient) error {
 	ret := _m.Called(_a0)
@@ -529,6 +1157,76 @@ func (_m *MockTx) UpdatePatient(_a0 *model.Patient) error {
 	return r0
 }
 
+// UpdateToken provides a mock function with given fields: _a0
+func (_m *MockTx) UpdateToken(_a0 *model.Token) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Token) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateTokenLastSeen provides a mock function with given fields: _a0
+func (_m *MockTx) UpdateTokenLastSeen(_a0 *model.Token) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Token) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateUser provides a mock function with given fields: _a0
+func (_m *MockTx) UpdateUser(_a0 *model.User) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.User) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// IncrementUserFailedLogins provides a mock function with given fields: _a0
+func (_m *MockTx) IncrementUserFailedLogins(_a0 *model.User) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.User) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateUserLogin provides a mock function with given fields: _a0
+func (_m *MockTx) UpdateUserLogin(_a0 *model.User) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.User) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateUserPassword provides a mock function with given fields: _a0
 func (_m *MockTx) UpdateUserPassword(_a0 *model.User) error {
 	ret := _m.Called(_a0)
@@ -542,3 +1240,31 @@ func (_m *MockTx) UpdateUserPassword(_a0 *model.User) error {
 
 	return r0
 }
+
+// UpdateUserRole provides a mock function with given fields: _a0
+func (_m *MockTx) UpdateUserRole(_a0 *model.User) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.User) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateVisit provides a mock function with given fields: _a0
+func (_m *MockTx) UpdateVisit(_a0 *model.Visit) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Visit) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
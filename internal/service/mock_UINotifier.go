@@ -0,0 +1,26 @@
+// Code generated by mockery v1.0.0. DO NOT EDIT.
+
+package service
+
+import mock "github.com/stretchr/testify/mock"
+import model "github.com/pagient/pagient-server/internal/model"
+
+// MockUINotifier is an autogenerated mock type for the UINotifier type
+type MockUINotifier struct {
+	mock.Mock
+}
+
+// NotifyDeletedVisit provides a mock function with given fields: _a0
+func (_m *MockUINotifier) NotifyDeletedVisit(_a0 *model.Visit) {
+	_m.Called(_a0)
+}
+
+// NotifyNewVisit provides a mock function with given fields: _a0
+func (_m *MockUINotifier) NotifyNewVisit(_a0 *model.Visit) {
+	_m.Called(_a0)
+}
+
+// NotifyUpdatedVisit provides a mock function with given fields: _a0
+func (_m *MockUINotifier) NotifyUpdatedVisit(_a0 *model.Visit) {
+	_m.Called(_a0)
+}
@@ -2,21 +2,24 @@ package service
 
 import (
 	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/tracing"
 
 	"github.com/pkg/errors"
-	"github.com/rs/zerolog/log"
 )
 
 // ListPagers returns all pagers
-func (service *defaultService) ListPagers() ([]*model.Pager, error) {
+func (service *defaultService) ListPagers() (pagers []*model.Pager, err error) {
+	service, span := service.trace("ListPagers")
+	defer func() { tracing.End(span, err) }()
+
 	tx, err := service.db.Begin()
 	if err != nil {
 		return nil, errors.Wrap(err, "create transaction failed")
 	}
 
-	pagers, err := tx.GetPagers()
+	pagers, err = tx.GetPagers()
 	if err != nil {
-		log.Error().
+		service.logger().Error().
 			Err(err).
 			Msg("get all pagers failed")
 
@@ -29,15 +32,18 @@ func (service *defaultService) ListPagers() ([]*model.Pager, error) {
 }
 
 // ShowPager returns a pager by it's id
-func (service *defaultService) ShowPager(id uint) (*model.Pager, error) {
+func (service *defaultService) ShowPager(id uint) (pager *model.Pager, err error) {
+	service, span := service.trace("ShowPager")
+	defer func() { tracing.End(span, err) }()
+
 	tx, err := service.db.Begin()
 	if err != nil {
 		return nil, errors.Wrap(err, "create transaction failed")
 	}
 
-	pager, err := tx.GetPager(id)
+	pager, err = tx.GetPager(id)
 	if err != nil {
-		log.Error().
+		service.logger().Error().
 			Err(err).
 			Uint("pager ID", id).
 			Msg("get pager failed")
@@ -51,7 +57,10 @@ func (service *defaultService) ShowPager(id uint) (*model.Pager, error) {
 }
 
 // CreatePager creates a new pager
-func (service *defaultService) CreatePager(pager *model.Pager) error {
+func (service *defaultService) CreatePager(pager *model.Pager) (err error) {
+	service, span := service.trace("CreatePager")
+	defer func() { tracing.End(span, err) }()
+
 	if err := service.validatePager(pager); err != nil {
 		return errors.WithStack(err)
 	}
@@ -63,7 +72,7 @@ func (service *defaultService) CreatePager(pager *model.Pager) error {
 
 	err = tx.AddPager(pager)
 	if err != nil {
-		log.Error().
+		service.logger().Error().
 			Err(err).
 			Msg("add pager failed")
 
@@ -75,6 +84,114 @@ func (service *defaultService) CreatePager(pager *model.Pager) error {
 	return nil
 }
 
+// UpdatePager changes name and easy call id of an existing pager if given model is valid
+func (service *defaultService) UpdatePager(pager *model.Pager) (err error) {
+	service, span := service.trace("UpdatePager")
+	defer func() { tracing.End(span, err) }()
+
+	if err := service.validatePager(pager); err != nil {
+		return errors.WithStack(err)
+	}
+
+	tx, err := service.db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "create transaction failed")
+	}
+
+	existing, err := tx.GetPager(pager.ID)
+	if err != nil {
+		service.logger().Error().
+			Err(err).
+			Uint("pager ID", pager.ID).
+			Msg("get pager failed")
+
+		tx.Rollback()
+		return errors.Wrapf(err, "get pager %d failed", pager.ID)
+	}
+	if existing == nil {
+		tx.Rollback()
+		return &modelNotExistErr{"pager doesn't exist"}
+	}
+
+	if err := tx.UpdatePager(pager); err != nil {
+		service.logger().Error().
+			Err(err).
+			Msg("update pager failed")
+
+		tx.Rollback()
+		return errors.Wrap(err, "update pager failed")
+	}
+
+	tx.Commit()
+	return nil
+}
+
+// DeletePager deletes a pager not assigned to an open visit
+func (service *defaultService) DeletePager(pager *model.Pager) (err error) {
+	service, span := service.trace("DeletePager")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := service.db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "create transaction failed")
+	}
+
+	existing, err := tx.GetPager(pager.ID)
+	if err != nil {
+		service.logger().Error().
+			Err(err).
+			Uint("pager ID", pager.ID).
+			Msg("get pager failed")
+
+		tx.Rollback()
+		return errors.Wrapf(err, "get pager %d failed", pager.ID)
+	}
+	if existing == nil {
+		tx.Rollback()
+		return &modelNotExistErr{"pager doesn't exist"}
+	}
+
+	unassigned, err := tx.GetUnassignedPagers()
+	if err != nil {
+		service.logger().Error().
+			Err(err).
+			Msg("get unassigned pagers failed")
+
+		tx.Rollback()
+		return errors.Wrap(err, "get unassigned pagers failed")
+	}
+
+	assigned := true
+	for _, p := range unassigned {
+		if p.ID == pager.ID {
+			assigned = false
+			break
+		}
+	}
+
+	if assigned {
+		tx.Rollback()
+		return &invalidArgumentErr{"pager is assigned to an open visit"}
+	}
+
+	if err := tx.RemovePager(pager); err != nil {
+		tx.Rollback()
+
+		if isEntryNotExistErr(err) {
+			return &modelNotExistErr{"pager doesn't exist"}
+		}
+
+		service.logger().Error().
+			Err(err).
+			Msg("remove pager failed")
+
+		return errors.Wrap(err, "remove pager failed")
+	}
+
+	tx.Commit()
+	return nil
+}
+
 func (service *defaultService) validatePager(pager *model.Pager) error {
 	if err := pager.Validate(); err != nil {
 		if model.IsValidationErr(err) {
@@ -1,42 +1,31 @@
 package service
 
 import (
-	"github.com/pagient/pagient-easy-call-go/easycall"
-	"github.com/pagient/pagient-server/internal/config"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/tracing"
 
 	"github.com/pkg/errors"
-	"github.com/rs/zerolog/log"
 )
 
 // ListPatients returns all patients
-func (service *defaultService) ListPatients() ([]*model.Patient, error) {
-	tx, err := service.db.Begin()
-	if err != nil {
-		return nil, errors.Wrap(err, "create transaction failed")
-	}
-
-	patients, err := tx.GetPatients()
-	if err != nil {
-		tx.Rollback()
-		return nil, errors.Wrap(err, "get all patients failed")
-	}
-
-	tx.Commit()
-	return patients, nil
-}
+func (service *defaultService) ListPatients() (patients []*model.Patient, err error) {
+	service, span := service.trace("ListPatients")
+	defer func() { tracing.End(span, err) }()
 
-// ListPagerPatientsByStatus returns all patients with a pager by status
-func (service *defaultService) ListPagerPatientsByStatus(statuses ...model.PatientStatus) ([]*model.Patient, error) {
 	tx, err := service.db.Begin()
 	if err != nil {
 		return nil, errors.Wrap(err, "create transaction failed")
 	}
 
-	patients, err := tx.GetPatientsWithPagerByStatus(statuses...)
+	patients, err = tx.GetPatients()
 	if err != nil {
 		tx.Rollback()
-		return nil, errors.Wrap(err, "get patients having pagers by status failed")
+		return nil, errors.Wrap(err, "get all patients failed")
 	}
 
 	tx.Commit()
@@ -44,13 +33,16 @@ func (service *defaultService) ListPagerPatientsByStatus(statuses ...model.Patie
 }
 
 // ShowPatient returns a patient by it's id
-func (service *defaultService) ShowPatient(id uint) (*model.Patient, error) {
+func (service *defaultService) ShowPatient(id uint) (patient *model.Patient, err error) {
+	service, span := service.trace("ShowPatient")
+	defer func() { tracing.End(span, err) }()
+
 	tx, err := service.db.Begin()
 	if err != nil {
 		return nil, errors.Wrap(err, "create transaction failed")
 	}
 
-	patient, err := tx.GetPatient(id)
+	patient, err = tx.GetPatient(id)
 	if err != nil {
 		tx.Rollback()
 		return nil, errors.Wrap(err, "get patient failed")
@@ -60,279 +52,205 @@ func (service *defaultService) ShowPatient(id uint) (*model.Patient, error) {
 	return patient, nil
 }
 
-// CreatePatient adds a new patient if given model is valid and not already existing
-func (service *defaultService) CreatePatient(patient *model.Patient) error {
-	patient.Status = model.PatientStatusPending
+// UpdatePatient updates the identity of an existing patient if given model is valid
+func (service *defaultService) UpdatePatient(patient *model.Patient) (err error) {
+	service, span := service.trace("UpdatePatient")
+	defer func() { tracing.End(span, err) }()
+
+	if err := patient.Validate(); err != nil {
+		if model.IsValidationErr(err) {
+			return &modelValidationErr{err.Error()}
+		}
 
-	if patient.ClientID == 0 {
-		return &invalidArgumentErr{"clientId: cannot be blank"}
+		return errors.Wrap(err, "validate patient failed")
 	}
 
-	tx, err := service.db.Begin()
+	tx, err := service.begin()
 	if err != nil {
 		return errors.Wrap(err, "create transaction failed")
 	}
 
-	if err := service.validatePatient(tx, patient); err != nil {
+	patientBeforeUpdate, err := tx.GetPatient(patient.ID)
+	if err != nil {
 		tx.Rollback()
-		return errors.WithStack(err)
+		return errors.Wrap(err, "get patient failed")
 	}
 
-	if patient.Active {
-		if err := service.markPatientsInactiveFromClient(tx, patient.ClientID); err != nil {
-			tx.Rollback()
-			return errors.WithStack(err)
-		}
+	if patientBeforeUpdate == nil {
+		tx.Rollback()
+		return &modelNotExistErr{"patient doesn't exist"}
 	}
 
-	if err := service.removeInactivePatientsWithoutPagerFromClient(tx, patient.ClientID); err != nil {
+	patient.AnonymizedAt = patientBeforeUpdate.AnonymizedAt
+
+	if err := service.upsertPatient(tx, patient, patientBeforeUpdate); err != nil {
 		tx.Rollback()
 		return errors.WithStack(err)
 	}
 
-	err = tx.AddPatient(patient)
+	// open visits show the patient's identity, so their views need an update
+	visits, err := tx.GetVisitsByPatient(patient.ID)
 	if err != nil {
 		tx.Rollback()
-
-		if isEntryNotValidErr(err) {
-			return &modelValidationErr{err.Error()}
-		}
-
-		if isEntryExistErr(err) {
-			return &modelExistErr{"patient already exists"}
-		}
-
-		return errors.Wrap(err, "add patient failed")
+		return errors.Wrap(err, "get visits by patient failed")
 	}
 
-	tx.Commit()
-	service.notifyNewPatient(patient)
+	for _, visit := range visits {
+		tx.notifyUpdatedVisit(visit)
+	}
 
-	return errors.Wrap(err, "add patient failed")
+	return tx.Commit()
 }
 
-// UpdatePatient updates an existing patient if given model is valid
-func (service *defaultService) UpdatePatient(patient *model.Patient) error {
-	tx, err := service.db.Begin()
+// DeletePatient deletes an existing patient and all of it's visits
+func (service *defaultService) DeletePatient(patient *model.Patient) (err error) {
+	service, span := service.trace("DeletePatient")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := service.begin()
 	if err != nil {
 		return errors.Wrap(err, "create transaction failed")
 	}
 
-	if err := service.validatePatient(tx, patient); err != nil {
-		tx.Rollback()
-		return errors.WithStack(err)
-	}
-
-	// load patient's old state to compare changed properties
-	patientBeforeUpdate, err := tx.GetPatient(patient.ID)
+	visits, err := tx.GetVisitsByPatient(patient.ID)
 	if err != nil {
 		tx.Rollback()
-		return errors.Wrap(err, "get patient failed")
+		return errors.Wrap(err, "get visits by patient failed")
 	}
 
-	if patient.Active {
-		if err := service.markPatientsInactiveFromClient(tx, patient.ClientID); err != nil {
+	for _, visit := range visits {
+		if visit.PagerID != 0 {
 			tx.Rollback()
-			return errors.WithStack(err)
+			return &invalidArgumentErr{"pagerId: cannot be set"}
 		}
 	}
 
-	err = tx.UpdatePatient(patient)
+	err = tx.RemovePatient(patient)
 	if err != nil {
 		tx.Rollback()
 
-		if isEntryNotValidErr(err) {
-			return &modelValidationErr{err.Error()}
-		}
-
 		if isEntryNotExistErr(err) {
 			return &modelNotExistErr{"patient doesn't exist"}
 		}
 
-		return errors.Wrap(err, "update patient failed")
-	}
-
-	if err := service.removeInactivePatientsWithoutPagerFromClient(tx, patient.ClientID); err != nil {
-		tx.Rollback()
-		return errors.WithStack(err)
+		return errors.Wrap(err, "remove patient failed")
 	}
 
-	// RoomAssignment status changed from another state to PatientStatusCall
-	if patient.Status == model.PatientStatusCall && patient.Status != patientBeforeUpdate.Status {
-		log.Debug().
-			Uint("pager", patient.PagerID).
-			Msg("pager gets called")
-
-		if err := service.callPatient(tx, patient); err != nil {
-			tx.Rollback()
-			return errors.Wrap(err, "call patient failed")
-		}
+	for _, visit := range visits {
+		tx.notifyDeletedVisit(visit)
 	}
 
-	tx.Commit()
-	service.notifyUpdatedPatient(patient)
-
-	return nil
+	return tx.Commit()
 }
 
-// DeletePatient deletes an existing patient
-func (service *defaultService) DeletePatient(patient *model.Patient) error {
-	if patient.PagerID != 0 {
-		return &invalidArgumentErr{"pagerId: cannot be set"}
-	}
+// PurgeFinishedPatients deletes or anonymizes the patients finished before the given time and records the purge in the audit log,
+// the patients to be purged are returned without changes on dry runs
+func (service *defaultService) PurgeFinishedPatients(before time.Time, mode model.PurgeMode, actor string, dryRun bool) (patients []*model.Patient, err error) {
+	service, span := service.trace("PurgeFinishedPatients")
+	defer func() { tracing.End(span, err) }()
 
-	tx, err := service.db.Begin()
-	if err != nil {
-		return errors.Wrap(err, "create transaction failed")
+	if mode != model.PurgeModeDelete && mode != model.PurgeModeAnonymize {
+		return nil, &invalidArgumentErr{fmt.Sprintf("purge mode %q is unknown", mode)}
 	}
 
-	err = tx.RemovePatient(patient)
+	tx, err := service.begin()
 	if err != nil {
-		tx.Rollback()
-
-		if isEntryNotExistErr(err) {
-			return &modelNotExistErr{"patient doesn't exist"}
-		}
-
-		return errors.Wrap(err, "remove patient failed")
+		return nil, errors.Wrap(err, "create transaction failed")
 	}
 
-	tx.Commit()
-	service.notifyDeletedPatient(patient)
-
-	return nil
-}
-
-// CallPatient calls a patient
-func (service *defaultService) CallPatient(patient *model.Patient) error {
-	tx, err := service.db.Begin()
+	patients, err = tx.GetFinishedPatientsBefore(before)
 	if err != nil {
-		return errors.Wrap(err, "create transaction failed")
-	}
+		service.logger().Error().
+			Err(err).
+			Msg("get finished patients failed")
 
-	if err := service.callPatient(tx, patient); err != nil {
 		tx.Rollback()
-		return errors.Wrap(err, "call patient failed")
-	}
-
-	tx.Commit()
-	service.notifyUpdatedPatient(patient)
-
-	return nil
-}
-
-func (service *defaultService) callPatient(tx Tx, patient *model.Patient) error {
-	pager, err := tx.GetPager(patient.PagerID)
-	if err != nil {
-		return errors.Wrap(err, "get pager failed")
+		return nil, errors.Wrap(err, "get finished patients failed")
 	}
 
-	client := easycall.NewClient(config.EasyCall.URL, config.EasyCall.User, config.EasyCall.Password)
-
-	if err := client.Send(&easycall.SendOptions{
-		Receiver: int(pager.EasyCallID),
-		Message:  "",
-		Port:     config.EasyCall.Port,
-	}); err != nil {
-		return &externalServiceErr{"pager call failed"}
-	}
-
-	patient.Status = model.PatientStatusCalled
-
-	err = tx.UpdatePatient(patient)
-	if err != nil {
-		return errors.Wrap(err, "update patient failed")
+	if dryRun || len(patients) == 0 {
+		tx.Rollback()
+		return patients, nil
 	}
 
-	return nil
-}
+	ids := make([]string, len(patients))
+	for i, patient := range patients {
+		ids[i] = strconv.FormatUint(uint64(patient.ID), 10)
 
-func (service *defaultService) validatePatient(tx Tx, patient *model.Patient) error {
-	var pagers []*model.Pager
-
-	if patient.PagerID != 0 {
-		// load pagers to validate if pager sent with request is valid
-		var err error
-		pagers, err = tx.GetUnassignedPagers()
+		// finished visits stay open until they are closed, their views need an update
+		visits, err := tx.GetVisitsByPatient(patient.ID)
 		if err != nil {
-			return errors.Wrap(err, "get all pagers failed")
+			service.logger().Error().
+				Err(err).
+				Uint("patient", patient.ID).
+				Msg("get visits by patient failed")
+
+			tx.Rollback()
+			return nil, errors.Wrap(err, "get visits by patient failed")
 		}
-	} else if patient.Status == model.PatientStatusCall {
-		return &modelValidationErr{"Status: \"call\" can only be set if PagerID is set."}
-	}
 
-	pat, err := tx.GetPatient(patient.ID)
-	if err != nil {
-		return errors.Wrap(err, "get patient failed")
-	}
+		if mode == model.PurgeModeAnonymize {
+			err = tx.AnonymizePatient(patient)
+		} else {
+			err = tx.RemovePatient(patient)
+		}
 
-	// patient exists so it is an update
-	// pager hasn't changed so it is also valid
-	if pat != nil && pat.PagerID == patient.PagerID {
-		pagers = append(pagers, &model.Pager{ID: patient.PagerID})
-	}
+		if err != nil {
+			service.logger().Error().
+				Err(err).
+				Uint("patient", patient.ID).
+				Msg("purge patient failed")
 
-	// validate patient
-	if err := patient.Validate(pagers); err != nil {
-		if model.IsValidationErr(err) {
-			return &modelValidationErr{err.Error()}
+			tx.Rollback()
+			return nil, errors.Wrap(err, "purge patient failed")
 		}
 
-		return errors.Wrap(err, "validate patient failed")
+		for _, visit := range visits {
+			if mode == model.PurgeModeAnonymize {
+				visit.Patient = *patient
+				tx.notifyUpdatedVisit(visit)
+			} else {
+				tx.notifyDeletedVisit(visit)
+			}
+		}
 	}
 
-	return nil
-}
+	if err := tx.AddAuditEntry(&model.AuditEntry{
+		Actor:   actor,
+		Action:  model.AuditActionPurgePatients,
+		Details: fmt.Sprintf("%s %d patients finished before %s: %s", mode, len(patients), before.Format(time.RFC3339), strings.Join(ids, ",")),
+	}); err != nil {
+		service.logger().Error().
+			Err(err).
+			Msg("add audit entry failed")
 
-func (service *defaultService) markPatientsInactiveFromClient(tx PatientTx, clientID uint) error {
-	patients, err := tx.GetPatientsByClient(clientID, true)
-	if err != nil {
-		return errors.Wrap(err, "get all patients by client failed")
+		tx.Rollback()
+		return nil, errors.Wrap(err, "add audit entry failed")
 	}
 
-	if err := tx.MarkPatientsInactiveByClient(clientID); err != nil {
-		return errors.Wrap(err, "mark all patients as inactive failed")
-	}
+	return patients, tx.Commit()
+}
 
-	for _, patient := range patients {
-		patient.Active = false
-		service.notifyUpdatedPatient(patient)
+// upsertPatient adds the patient if it doesn't exist yet or updates it's identity otherwise
+func (service *defaultService) upsertPatient(tx Tx, patient, existing *model.Patient) error {
+	var err error
+	if existing == nil {
+		err = tx.AddPatient(patient)
+	} else {
+		err = tx.UpdatePatient(patient)
 	}
 
-	return nil
-}
-
-func (service *defaultService) removeInactivePatientsWithoutPagerFromClient(tx PatientTx, clientID uint) error {
-	patients, err := tx.GetPatientsByClient(clientID, false, false)
 	if err != nil {
-		return errors.Wrap(err, "get all patients by client failed")
-	}
+		if isEntryNotValidErr(err) {
+			return &modelValidationErr{err.Error()}
+		}
 
-	if err := tx.RemovePatientsByClient(clientID, false, false); err != nil {
-		return errors.Wrap(err, "remove all inactive patients without pager failed")
-	}
+		if isEntryExistErr(err) {
+			return &modelExistErr{"patient with social security number already exists"}
+		}
 
-	for _, patient := range patients {
-		service.notifyDeletedPatient(patient)
+		return errors.Wrap(err, "save patient failed")
 	}
 
 	return nil
 }
-
-func (service *defaultService) notifyNewPatient(patient *model.Patient) {
-	if service.notifier != nil {
-		service.notifier.NotifyNewPatient(patient)
-	}
-}
-
-func (service *defaultService) notifyUpdatedPatient(patient *model.Patient) {
-	if service.notifier != nil {
-		service.notifier.NotifyUpdatedPatient(patient)
-	}
-}
-
-func (service *defaultService) notifyDeletedPatient(patient *model.Patient) {
-	if service.notifier != nil {
-		service.notifier.NotifyDeletedPatient(patient)
-	}
-}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pagient/pagient-server/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDefaultService_PurgeFinishedPatients(t *testing.T) {
+	before := time.Now().Add(-24 * time.Hour)
+	patients := []*model.Patient{
+		{ID: 1, Name: "Max Mustermann"},
+		{ID: 2, Name: "Erika Mustermann"},
+	}
+	visits := map[uint][]*model.Visit{
+		1: {{ID: 11, PatientID: 1, Status: model.VisitStatusFinished}},
+		2: nil,
+	}
+
+	tests := map[string]struct {
+		mode       model.PurgeMode
+		dryRun     bool
+		invalidErr bool
+	}{
+		"list patients on dry run": {
+			mode:       model.PurgeModeDelete,
+			dryRun:     true,
+			invalidErr: false,
+		},
+		"delete patients": {
+			mode:       model.PurgeModeDelete,
+			dryRun:     false,
+			invalidErr: false,
+		},
+		"anonymize patients": {
+			mode:       model.PurgeModeAnonymize,
+			dryRun:     false,
+			invalidErr: false,
+		},
+		"reject unknown mode": {
+			mode:       model.PurgeMode("archive"),
+			dryRun:     false,
+			invalidErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		tx := &MockTx{}
+		db := &MockDB{}
+		notifier := &MockUINotifier{}
+
+		if !test.invalidErr {
+			db.On("Begin").Return(tx, nil).Once()
+			tx.On("GetFinishedPatientsBefore", before).Return(patients, nil).Once()
+		}
+
+		if test.dryRun {
+			tx.On("Rollback").Return(nil).Once()
+		} else if !test.invalidErr {
+			for _, patient := range patients {
+				tx.On("GetVisitsByPatient", patient.ID).Return(visits[patient.ID], nil).Once()
+
+				if test.mode == model.PurgeModeAnonymize {
+					tx.On("AnonymizePatient", patient).Return(nil).Once()
+				} else {
+					tx.On("RemovePatient", patient).Return(nil).Once()
+				}
+
+				for _, visit := range visits[patient.ID] {
+					if test.mode == model.PurgeModeAnonymize {
+						notifier.On("NotifyUpdatedVisit", visit).Return().Once()
+					} else {
+						notifier.On("NotifyDeletedVisit", visit).Return().Once()
+					}
+				}
+			}
+
+			tx.On("AddAuditEntry", mock.MatchedBy(func(entry *model.AuditEntry) bool {
+				return entry.Action == model.AuditActionPurgePatients && entry.Actor == "janitor"
+			})).Return(nil).Once()
+			tx.On("Commit").Return(nil).Once()
+		}
+
+		s := NewService(db, notifier)
+		purged, err := s.PurgeFinishedPatients(before, test.mode, "janitor", test.dryRun)
+
+		assert.Equal(t, test.invalidErr, IsInvalidArgumentErr(err))
+		if !test.invalidErr {
+			assert.NoError(t, err)
+			assert.Equal(t, patients, purged)
+		}
+
+		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
+		notifier.AssertExpectations(t)
+	}
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/tracing"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// serviceTimeWindow is how far back calls are taken into account for the service times of rooms
+	serviceTimeWindow = 7 * 24 * time.Hour
+	// maxServiceTime limits the time between two calls in a room, longer gaps are breaks
+	maxServiceTime = time.Hour
+)
+
+// UpdateQueuePositions stores the queue positions of patients on their open visits
+// and estimates when they get called by the service times of their rooms
+func (service *defaultService) UpdateQueuePositions(positions []*model.QueuePosition) (err error) {
+	service, span := service.trace("UpdateQueuePositions")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := service.begin()
+	if err != nil {
+		return errors.Wrap(err, "create transaction failed")
+	}
+
+	visits, err := tx.GetVisits()
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "get all visits failed")
+	}
+
+	now := time.Now()
+	called, err := tx.GetVisitsFirstCalledAfter(now.Add(-serviceTimeWindow))
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "get recently called visits failed")
+	}
+
+	serviceTimes := serviceTimesByRoom(called)
+
+	positionsByPatient := make(map[uint]*model.QueuePosition, len(positions))
+	for _, position := range positions {
+		positionsByPatient[position.PatientID] = position
+	}
+
+	for _, visit := range visits {
+		room := visit.Room
+		var queuePosition uint
+		var estimatedCallAt *time.Time
+
+		// positions of called patients are outdated
+		if position, ok := positionsByPatient[visit.PatientID]; ok && visit.FirstCalledAt == nil {
+			queuePosition = position.Position
+			if room == "" {
+				room = position.Room
+			}
+
+			if serviceTime, ok := serviceTimes[room]; ok {
+				at := now.Add(time.Duration(queuePosition) * serviceTime)
+				estimatedCallAt = &at
+			}
+		}
+
+		if queuePosition == visit.QueuePosition && room == visit.Room && !estimateChanged(visit.EstimatedCallAt, estimatedCallAt) {
+			continue
+		}
+
+		visit.Room = room
+		visit.QueuePosition = queuePosition
+		visit.EstimatedCallAt = estimatedCallAt
+
+		if err := tx.UpdateVisit(visit); err != nil {
+			service.logger().Error().
+				Err(err).
+				Uint("visit", visit.ID).
+				Msg("update queue position of visit failed")
+
+			tx.Rollback()
+			return errors.Wrap(err, "update visit failed")
+		}
+
+		tx.notifyUpdatedVisit(visit)
+	}
+
+	return tx.Commit()
+}
+
+// serviceTimesByRoom returns the average time between successive calls per room
+func serviceTimesByRoom(visits []*model.Visit) map[string]time.Duration {
+	callsByRoom := make(map[string][]time.Time)
+	for _, visit := range visits {
+		if visit.FirstCalledAt != nil {
+			callsByRoom[visit.Room] = append(callsByRoom[visit.Room], *visit.FirstCalledAt)
+		}
+	}
+
+	serviceTimes := make(map[string]time.Duration, len(callsByRoom))
+	for room, calls := range callsByRoom {
+		sort.Slice(calls, func(i, j int) bool {
+			return calls[i].Before(calls[j])
+		})
+
+		var total time.Duration
+		var count int
+		for i := 1; i < len(calls); i++ {
+			if gap := calls[i].Sub(calls[i-1]); gap <= maxServiceTime {
+				total += gap
+				count++
+			}
+		}
+
+		if count > 0 {
+			serviceTimes[room] = total / time.Duration(count)
+		}
+	}
+
+	return serviceTimes
+}
+
+// estimateChanged reports if the estimated call time changed by at least a minute,
+// smaller changes aren't worth pushing to the views
+func estimateChanged(before, after *time.Time) bool {
+	if before == nil || after == nil {
+		return before != after
+	}
+
+	diff := after.Sub(*before)
+	return diff >= time.Minute || diff <= -time.Minute
+}
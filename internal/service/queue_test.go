@@ -0,0 +1,95 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pagient/pagient-server/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDefaultService_UpdateQueuePositions(t *testing.T) {
+	now := time.Now()
+	calledAt := func(d time.Duration) *time.Time {
+		at := now.Add(-2 * time.Hour).Add(d)
+		return &at
+	}
+
+	// patients in room "A" have been called every 10 minutes
+	called := []*model.Visit{
+		{ID: 1, Room: "A", FirstCalledAt: calledAt(0)},
+		{ID: 2, Room: "A", FirstCalledAt: calledAt(10 * time.Minute)},
+		{ID: 3, Room: "A", FirstCalledAt: calledAt(20 * time.Minute)},
+	}
+
+	tests := map[string]struct {
+		visit           *model.Visit
+		positions       []*model.QueuePosition
+		updated         bool
+		queuePosition   uint
+		room            string
+		estimatedCallIn time.Duration
+	}{
+		"estimate call of queued patient": {
+			visit: &model.Visit{ID: 4, PatientID: 1},
+			positions: []*model.QueuePosition{
+				{PatientID: 1, Room: "A", Position: 2},
+			},
+			updated:         true,
+			queuePosition:   2,
+			room:            "A",
+			estimatedCallIn: 20 * time.Minute,
+		},
+		"keep unchanged queue position": {
+			visit: &model.Visit{ID: 4, PatientID: 1, Room: "B", QueuePosition: 3},
+			positions: []*model.QueuePosition{
+				{PatientID: 1, Room: "B", Position: 3},
+			},
+			updated:       false,
+			queuePosition: 3,
+			room:          "B",
+		},
+		"reset queue position of dequeued patient": {
+			visit:         &model.Visit{ID: 4, PatientID: 1, Room: "B", QueuePosition: 3},
+			positions:     nil,
+			updated:       true,
+			queuePosition: 0,
+			room:          "B",
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		tx := &MockTx{}
+		db := &MockDB{}
+		notifier := &MockUINotifier{}
+
+		db.On("Begin").Return(tx, nil).Once()
+		tx.On("GetVisits").Return([]*model.Visit{test.visit}, nil).Once()
+		tx.On("GetVisitsFirstCalledAfter", mock.AnythingOfType("time.Time")).Return(called, nil).Once()
+		if test.updated {
+			tx.On("UpdateVisit", test.visit).Return(nil).Once()
+			notifier.On("NotifyUpdatedVisit", test.visit).Return().Once()
+		}
+		tx.On("Commit").Return(nil).Once()
+
+		s := NewService(db, notifier)
+		err := s.UpdateQueuePositions(test.positions)
+
+		assert.NoError(t, err)
+		assert.Equal(t, test.queuePosition, test.visit.QueuePosition)
+		assert.Equal(t, test.room, test.visit.Room)
+		if test.estimatedCallIn > 0 {
+			assert.WithinDuration(t, now.Add(test.estimatedCallIn), *test.visit.EstimatedCallAt, time.Second)
+		} else {
+			assert.Nil(t, test.visit.EstimatedCallAt)
+		}
+
+		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
+		notifier.AssertExpectations(t)
+	}
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/tracing"
+
+	"github.com/pkg/errors"
+)
+
+// ReportVisits aggregates the statistics of the visits checked in within the given time range by group
+func (service *defaultService) ReportVisits(from, to time.Time, group model.ReportGroup) (rows []*model.ReportRow, err error) {
+	service, span := service.trace("ReportVisits")
+	defer func() { tracing.End(span, err) }()
+
+	if group != model.ReportGroupDay && group != model.ReportGroupHour && group != model.ReportGroupRoom && group != model.ReportGroupClient {
+		return nil, &invalidArgumentErr{fmt.Sprintf("report group %q is unknown", group)}
+	}
+
+	if !from.Before(to) {
+		return nil, &invalidArgumentErr{"report range is empty"}
+	}
+
+	tx, err := service.db.Begin()
+	if err != nil {
+		return nil, errors.Wrap(err, "create transaction failed")
+	}
+
+	visits, err := tx.GetVisitsCheckedInBetween(from, to)
+	if err != nil {
+		service.logger().Error().
+			Err(err).
+			Msg("get visits checked in between failed")
+
+		tx.Rollback()
+		return nil, errors.Wrap(err, "get visits checked in between failed")
+	}
+
+	pagers, err := tx.GetPagers()
+	if err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "get all pagers failed")
+	}
+
+	tx.Commit()
+
+	grouped := make(map[string][]*model.Visit)
+	for _, visit := range visits {
+		key := reportGroupKey(visit, group)
+		grouped[key] = append(grouped[key], visit)
+	}
+
+	keys := make([]string, 0, len(grouped))
+	for key := range grouped {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	end := to
+	if now := time.Now(); now.Before(end) {
+		end = now
+	}
+
+	rows = make([]*model.ReportRow, len(keys))
+	for i, key := range keys {
+		rows[i] = reportRow(key, grouped[key], end)
+
+		// share of the time pagers have been available in the group
+		available := time.Duration(len(pagers)) * reportGroupSpan(key, group, from, to)
+		if available > 0 {
+			rows[i].PagerUtilization = float64(rows[i].PagerTime) / float64(available)
+		}
+	}
+
+	return rows, nil
+}
+
+// reportGroupKey returns the key of the group the visit belongs to
+func reportGroupKey(visit *model.Visit, group model.ReportGroup) string {
+	switch group {
+	case model.ReportGroupDay:
+		return visit.CreatedAt.Local().Format("2006-01-02")
+	case model.ReportGroupHour:
+		return visit.CreatedAt.Local().Format("15")
+	case model.ReportGroupRoom:
+		return visit.Room
+	default:
+		if visit.Client.Name != "" {
+			return visit.Client.Name
+		}
+
+		return fmt.Sprintf("%d", visit.ClientID)
+	}
+}
+
+// reportGroupSpan returns the time covered by a group within the report range
+func reportGroupSpan(key string, group model.ReportGroup, from, to time.Time) time.Duration {
+	switch group {
+	case model.ReportGroupDay:
+		day, err := time.ParseInLocation("2006-01-02", key, time.Local)
+		if err != nil {
+			return 0
+		}
+
+		start, end := day, day.AddDate(0, 0, 1)
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+
+		return end.Sub(start)
+	case model.ReportGroupHour:
+		days := math.Ceil(to.Sub(from).Hours() / 24)
+
+		return time.Duration(days) * time.Hour
+	default:
+		return to.Sub(from)
+	}
+}
+
+// reportRow aggregates the statistics of the visits of one group,
+// pagers still assigned count until the given end
+func reportRow(key string, visits []*model.Visit, end time.Time) *model.ReportRow {
+	row := &model.ReportRow{
+		Group:  key,
+		Visits: len(visits),
+	}
+
+	var waits, arrivals []time.Duration
+	for _, visit := range visits {
+		if visit.FirstCalledAt != nil {
+			row.Called++
+			waits = append(waits, visit.WaitTime(*visit.FirstCalledAt))
+
+			if visit.ArrivedAt != nil {
+				arrivals = append(arrivals, visit.ArrivedAt.Sub(*visit.FirstCalledAt))
+			} else if visit.ClosedAt != nil {
+				row.NoShows++
+			}
+		}
+
+		if visit.PagerAssignedAt != nil {
+			returnedAt := end
+			if visit.PagerReturnedAt != nil {
+				returnedAt = *visit.PagerReturnedAt
+			} else if visit.ClosedAt != nil {
+				returnedAt = *visit.ClosedAt
+			}
+
+			if returnedAt.After(*visit.PagerAssignedAt) {
+				row.PagerTime += returnedAt.Sub(*visit.PagerAssignedAt)
+			}
+		}
+	}
+
+	row.WaitAverage, row.WaitMedian, row.WaitP90 = durationStats(waits)
+	row.ArrivalAverage, row.ArrivalMedian, row.ArrivalP90 = durationStats(arrivals)
+
+	if row.Called > 0 {
+		row.NoShowRate = float64(row.NoShows) / float64(row.Called)
+	}
+
+	return row
+}
+
+// durationStats returns the average, median and 90th percentile of the durations
+func durationStats(durations []time.Duration) (average, median, p90 time.Duration) {
+	if len(durations) == 0 {
+		return 0, 0, 0
+	}
+
+	sort.Slice(durations, func(i, j int) bool {
+		return durations[i] < durations[j]
+	})
+
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+
+	return total / time.Duration(len(durations)), percentile(durations, 0.5), percentile(durations, 0.9)
+}
+
+// percentile returns the nearest-rank percentile of sorted durations
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+
+	return sorted[rank]
+}
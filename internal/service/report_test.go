@@ -0,0 +1,96 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pagient/pagient-server/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultService_ReportVisits(t *testing.T) {
+	from := time.Now().Add(-4 * time.Hour)
+	to := time.Now()
+	at := func(d time.Duration) *time.Time {
+		t := from.Add(d)
+		return &t
+	}
+
+	visits := []*model.Visit{
+		// called after 10 minutes, arrived 2 minutes later
+		{ID: 1, Room: "1", CreatedAt: *at(0), PagerAssignedAt: at(0), FirstCalledAt: at(10 * time.Minute), ArrivedAt: at(12 * time.Minute), PagerReturnedAt: at(12 * time.Minute)},
+		// called after 30 minutes, never arrived
+		{ID: 2, Room: "1", CreatedAt: *at(0), PagerAssignedAt: at(0), FirstCalledAt: at(30 * time.Minute), ClosedAt: at(60 * time.Minute)},
+		// not called yet
+		{ID: 3, Room: "2", CreatedAt: *at(time.Hour)},
+	}
+
+	tests := map[string]struct {
+		group      model.ReportGroup
+		invalidErr bool
+		rows       []*model.ReportRow
+	}{
+		"report by room": {
+			group:      model.ReportGroupRoom,
+			invalidErr: false,
+			rows: []*model.ReportRow{
+				{
+					Group:            "1",
+					Visits:           2,
+					Called:           2,
+					WaitAverage:      20 * time.Minute,
+					WaitMedian:       10 * time.Minute,
+					WaitP90:          30 * time.Minute,
+					ArrivalAverage:   2 * time.Minute,
+					ArrivalMedian:    2 * time.Minute,
+					ArrivalP90:       2 * time.Minute,
+					NoShows:          1,
+					NoShowRate:       0.5,
+					PagerTime:        72 * time.Minute,
+					PagerUtilization: 0.15,
+				},
+				{
+					Group:  "2",
+					Visits: 1,
+				},
+			},
+		},
+		"reject unknown group": {
+			group:      model.ReportGroup("week"),
+			invalidErr: true,
+			rows:       nil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		tx := &MockTx{}
+		db := &MockDB{}
+
+		if !test.invalidErr {
+			db.On("Begin").Return(tx, nil).Once()
+			tx.On("GetVisitsCheckedInBetween", from, to).Return(visits, nil).Once()
+			tx.On("GetPagers").Return([]*model.Pager{{ID: 1}, {ID: 2}}, nil).Once()
+			tx.On("Commit").Return(nil).Once()
+		}
+
+		s := NewService(db, nil)
+		rows, err := s.ReportVisits(from, to, test.group)
+
+		assert.Equal(t, test.invalidErr, IsInvalidArgumentErr(err))
+		if !test.invalidErr {
+			assert.NoError(t, err)
+			assert.Len(t, rows, len(test.rows))
+			for i, row := range test.rows {
+				assert.InDelta(t, row.PagerUtilization, rows[i].PagerUtilization, 0.01)
+				rows[i].PagerUtilization = row.PagerUtilization
+				assert.Equal(t, row, rows[i])
+			}
+		}
+
+		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
+	}
+}
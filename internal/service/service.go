@@ -1,68 +1,216 @@
 package service
 
 import (
+	"context"
+	"time"
+
 	"github.com/pagient/pagient-server/internal/model"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
 )
 
+// Scope is implemented by all services to scope their log entries and spans, e.g. to a request
+type Scope interface {
+	// WithLogger returns the service writing log entries to given logger,
+	// e.g. to correlate them by the id of a request
+	WithLogger(zerolog.Logger) Service
+	// WithContext returns the service tracing its operations as children of the span in given context
+	WithContext(context.Context) Service
+}
+
+// APIKeyService interface
+type APIKeyService interface {
+	Scope
+
+	// List api keys of a user or all if username is empty
+	ListAPIKeys(string) ([]*model.APIKey, error)
+	ShowAPIKey(uint) (*model.APIKey, error)
+	ShowAPIKeyByKey(string) (*model.APIKey, error)
+	CreateAPIKey(*model.APIKey) error
+	TouchAPIKey(*model.APIKey) error
+	DeleteAPIKey(*model.APIKey) error
+}
+
+// AuditService interface
+type AuditService interface {
+	Scope
+
+	ListAuditEntries(time.Time) ([]*model.AuditEntry, error)
+}
+
 // ClientService interface
 type ClientService interface {
+	Scope
+
 	ListClients() ([]*model.Client, error)
 	ShowClient(uint) (*model.Client, error)
 	ShowClientByUser(string) (*model.Client, error)
 	CreateClient(*model.Client) error
+	UpdateClient(*model.Client) error
+	DeleteClient(*model.Client) error
+}
+
+// EventService interface
+type EventService interface {
+	Scope
+
+	ListEventsAfter(uint) ([]*model.Event, error)
+	CreateEvent(*model.Event) error
+	DeleteEventsBefore(time.Time) error
+}
+
+// InventoryService interface
+type InventoryService interface {
+	Scope
+
+	// Import the inventory unless any row is invalid, changes are rolled back on dry run
+	ImportInventory(*model.Inventory, bool) ([]*model.ImportError, error)
+	ExportInventory() (*model.Inventory, error)
+}
+
+// LoginAttemptService interface
+type LoginAttemptService interface {
+	Scope
+
+	ListLoginAttempts(string, time.Time) ([]*model.LoginAttempt, error)
 }
 
 // PagerService interface
 type PagerService interface {
+	Scope
+
 	ListPagers() ([]*model.Pager, error)
 	ShowPager(uint) (*model.Pager, error)
 	CreatePager(*model.Pager) error
+	UpdatePager(*model.Pager) error
+	DeletePager(*model.Pager) error
 }
 
 // PatientService interface
 type PatientService interface {
+	Scope
+
 	ListPatients() ([]*model.Patient, error)
-	ListPagerPatientsByStatus(...model.PatientStatus) ([]*model.Patient, error)
 	ShowPatient(uint) (*model.Patient, error)
-	CreatePatient(*model.Patient) error
 	UpdatePatient(*model.Patient) error
 	DeletePatient(*model.Patient) error
-	CallPatient(*model.Patient) error
+	// Purge patients finished before given time by purge mode, actor and dry run
+	PurgeFinishedPatients(time.Time, model.PurgeMode, string, bool) ([]*model.Patient, error)
+}
+
+// ReportService interface
+type ReportService interface {
+	Scope
+
+	// Report visits checked in between given times by group
+	ReportVisits(time.Time, time.Time, model.ReportGroup) ([]*model.ReportRow, error)
 }
 
 // TokenService interface
 type TokenService interface {
+	Scope
+
 	ListTokensByUser(string) ([]*model.Token, error)
 	ShowToken(string) (*model.Token, error)
+	ShowTokenByID(uint) (*model.Token, error)
 	CreateToken(*model.Token) error
+	RefreshToken(string, *model.Token) error
+	TouchToken(*model.Token) error
 	DeleteToken(*model.Token) error
+	DeleteTokensByUser(string, string) ([]*model.Token, error)
+	DeleteExpiredTokens() error
 }
 
 // UserService interface
 type UserService interface {
+	Scope
+
 	ListUsers() ([]*model.User, error)
 	ShowUser(string) (*model.User, error)
 	ShowUserByToken(string) (*model.User, error)
 	CreateUser(*model.User) error
 	ChangeUserPassword(*model.User) error
-	Login(string, string) (*model.User, bool, error)
+	ChangeUserRole(*model.User) error
+	// UpdateUser changes role and client of given user
+	UpdateUser(*model.User) error
+	// LinkUser links the account of given username to the subject at the openid connect provider
+	LinkUser(*model.User) error
+	DeleteUser(*model.User) error
+	// Login by username, password and ip address of the client
+	Login(string, string, string) (*model.User, bool, error)
+	// LoginExternal by the user authenticated by a single sign-on provider and ip address of the client
+	LoginExternal(*model.User, string) (*model.User, error)
+}
+
+// VisitService interface
+type VisitService interface {
+	Scope
+
+	ListVisits() ([]*model.Visit, error)
+	ListPagerVisitsByStatus(...model.VisitStatus) ([]*model.Visit, error)
+	ShowVisit(uint) (*model.Visit, error)
+	ShowAverageWaitTime() (time.Duration, error)
+	CountVisitsByStatus() (map[model.VisitStatus]int, error)
+	CheckIn(*model.Visit) error
+	UpdateVisit(*model.Visit) error
+	CloseVisit(*model.Visit) error
+	CallVisit(*model.Visit) error
+	UpdateQueuePositions([]*model.QueuePosition) error
 }
 
 // Service interface combines all concrete model services
 type Service interface {
+	APIKeyService
+	AuditService
 	ClientService
+	EventService
+	InventoryService
+	LoginAttemptService
 	PagerService
 	PatientService
+	ReportService
 	TokenService
 	UserService
+	VisitService
 }
 
 type defaultService struct {
-	db       DB
-	notifier UINotifier
+	db             DB
+	notifier       UINotifier
+	authenticators []Authenticator
+	// log is the global logger if nil
+	log *zerolog.Logger
+	// ctx holds the span operations are traced as children of, a new trace is started if nil
+	ctx context.Context
 }
 
-// NewService constructs a new service layer
-func NewService(db DB, notifier UINotifier) Service {
-	return &defaultService{db, notifier}
+// NewService constructs a new service layer,
+// logins are verified by the authenticators before falling back to local accounts
+func NewService(db DB, notifier UINotifier, authenticators ...Authenticator) Service {
+	return &defaultService{db, notifier, authenticators, nil, nil}
+}
+
+// WithLogger returns a copy of the service writing log entries to given logger
+func (service *defaultService) WithLogger(logger zerolog.Logger) Service {
+	scoped := *service
+	scoped.log = &logger
+
+	return &scoped
+}
+
+// WithContext returns a copy of the service tracing its operations as children of the span in given context
+func (service *defaultService) WithContext(ctx context.Context) Service {
+	scoped := *service
+	scoped.ctx = ctx
+
+	return &scoped
+}
+
+func (service *defaultService) logger() *zerolog.Logger {
+	if service.log == nil {
+		return &log.Logger
+	}
+
+	return service.log
 }
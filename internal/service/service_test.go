@@ -0,0 +1,112 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/pagient/pagient-server/internal/model"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"go.opencensus.io/trace"
+)
+
+// spanRecorder records the ended spans
+type spanRecorder struct {
+	mu    sync.Mutex
+	spans []*trace.SpanData
+}
+
+func (r *spanRecorder) ExportSpan(span *trace.SpanData) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.spans = append(r.spans, span)
+}
+
+func TestDefaultService_WithLogger(t *testing.T) {
+	tx := &MockTx{}
+	tx.On("GetClients").Return(nil, errors.New("test error"))
+	tx.On("Rollback").Return(nil)
+
+	db := &MockDB{}
+	db.On("Begin").Return(tx, nil)
+
+	var out bytes.Buffer
+	logger := zerolog.New(&out).With().Str("request_id", "test").Logger()
+
+	s := NewService(db, nil)
+	scoped := s.WithLogger(logger)
+
+	_, err := scoped.ListClients()
+	assert.Error(t, err)
+	assert.Contains(t, out.String(), `"request_id":"test"`)
+	assert.Contains(t, out.String(), "get all clients failed")
+
+	// the original service keeps logging to the global logger
+	out.Reset()
+	s.ListClients()
+	assert.Empty(t, out.String())
+}
+
+func TestDefaultService_WithContext(t *testing.T) {
+	recorder := &spanRecorder{}
+	trace.RegisterExporter(recorder)
+	defer trace.UnregisterExporter(recorder)
+
+	tx := &MockTx{}
+	tx.On("GetClients").Return([]*model.Client{}, nil)
+	tx.On("Commit").Return(nil)
+
+	db := &MockDB{}
+	db.On("Begin").Return(tx, nil)
+
+	// children of sampled spans are sampled as well
+	ctx, parent := trace.StartSpan(context.Background(), "request", trace.WithSampler(trace.AlwaysSample()))
+	_, err := NewService(db, nil).WithContext(ctx).ListClients()
+	assert.NoError(t, err)
+	parent.End()
+
+	spans := map[string]*trace.SpanData{}
+	for _, span := range recorder.spans {
+		spans[span.Name] = span
+	}
+
+	if assert.Contains(t, spans, "service.ListClients") && assert.Contains(t, spans, "db.transaction") {
+		// operations of a service are traced as children of the span of it's caller
+		assert.Equal(t, parent.SpanContext().SpanID, spans["service.ListClients"].ParentSpanID)
+		assert.Equal(t, spans["service.ListClients"].SpanID, spans["db.transaction"].ParentSpanID)
+	}
+}
+
+func TestDefaultService_TraceError(t *testing.T) {
+	recorder := &spanRecorder{}
+	trace.RegisterExporter(recorder)
+	defer trace.UnregisterExporter(recorder)
+
+	tx := &MockTx{}
+	tx.On("GetClients").Return(nil, errors.New("test error"))
+	tx.On("Rollback").Return(nil)
+
+	db := &MockDB{}
+	db.On("Begin").Return(tx, nil)
+
+	ctx, parent := trace.StartSpan(context.Background(), "request", trace.WithSampler(trace.AlwaysSample()))
+	_, err := NewService(db, nil).WithContext(ctx).ListClients()
+	assert.Error(t, err)
+	parent.End()
+
+	spans := map[string]*trace.SpanData{}
+	for _, span := range recorder.spans {
+		spans[span.Name] = span
+	}
+
+	// failed service calls mark their span as failed
+	if assert.Contains(t, spans, "service.ListClients") {
+		assert.NotEqual(t, int32(trace.StatusCodeOK), spans["service.ListClients"].Status.Code)
+		assert.Contains(t, spans["service.ListClients"].Status.Message, "test error")
+	}
+}
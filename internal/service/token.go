@@ -1,22 +1,30 @@
 package service
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
 	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/tracing"
 
 	"github.com/pkg/errors"
-	"github.com/rs/zerolog/log"
+	"github.com/rs/xid"
 )
 
 // ListTokensByUser returns all active tokens by username
-func (service *defaultService) ListTokensByUser(username string) ([]*model.Token, error) {
+func (service *defaultService) ListTokensByUser(username string) (tokens []*model.Token, err error) {
+	service, span := service.trace("ListTokensByUser")
+	defer func() { tracing.End(span, err) }()
+
 	tx, err := service.db.Begin()
 	if err != nil {
 		return nil, errors.Wrap(err, "create transaction failed")
 	}
 
-	tokens, err := tx.GetTokensByUser(username)
+	tokens, err = tx.GetTokensByUser(username)
 	if err != nil {
-		log.Error().
+		service.logger().Error().
 			Err(err).
 			Str("user", username).
 			Msg("get token failed")
@@ -30,15 +38,18 @@ func (service *defaultService) ListTokensByUser(username string) ([]*model.Token
 }
 
 // ShowToken returns a token
-func (service *defaultService) ShowToken(rawToken string) (*model.Token, error) {
+func (service *defaultService) ShowToken(rawToken string) (token *model.Token, err error) {
+	service, span := service.trace("ShowToken")
+	defer func() { tracing.End(span, err) }()
+
 	tx, err := service.db.Begin()
 	if err != nil {
 		return nil, errors.Wrap(err, "create transaction failed")
 	}
 
-	token, err := tx.GetToken(rawToken)
+	token, err = tx.GetToken(rawToken)
 	if err != nil {
-		log.Error().
+		service.logger().Error().
 			Err(err).
 			Msg("get token failed")
 
@@ -50,8 +61,41 @@ func (service *defaultService) ShowToken(rawToken string) (*model.Token, error)
 	return token, nil
 }
 
-// CreateToken adds an active token to a user
-func (service *defaultService) CreateToken(token *model.Token) error {
+// ShowTokenByID returns a token by it's id
+func (service *defaultService) ShowTokenByID(id uint) (token *model.Token, err error) {
+	service, span := service.trace("ShowTokenByID")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := service.db.Begin()
+	if err != nil {
+		return nil, errors.Wrap(err, "create transaction failed")
+	}
+
+	token, err = tx.GetTokenByID(id)
+	if err != nil {
+		service.logger().Error().
+			Err(err).
+			Uint("token", id).
+			Msg("get token by id failed")
+
+		tx.Rollback()
+		return nil, errors.Wrap(err, "get token by id failed")
+	}
+
+	tx.Commit()
+	return token, nil
+}
+
+// CreateToken adds an active token to a user starting a new session
+func (service *defaultService) CreateToken(token *model.Token) (err error) {
+	service, span := service.trace("CreateToken")
+	defer func() { tracing.End(span, err) }()
+
+	if token.Family == "" {
+		token.Family = xid.New().String()
+	}
+	token.RefreshHash = hashToken(token.Refresh)
+
 	tx, err := service.db.Begin()
 	if err != nil {
 		return errors.Wrap(err, "create transaction failed")
@@ -59,7 +103,7 @@ func (service *defaultService) CreateToken(token *model.Token) error {
 
 	err = tx.AddToken(token)
 	if err != nil {
-		log.Error().
+		service.logger().Error().
 			Err(err).
 			Msg("add token failed")
 
@@ -71,8 +115,107 @@ func (service *defaultService) CreateToken(token *model.Token) error {
 	return nil
 }
 
-// DeleteToken removes an active token from a user
-func (service *defaultService) DeleteToken(token *model.Token) error {
+// RefreshToken replaces the token belonging to the refresh token by the given token.
+// The replaced token is kept as rotated to detect reuse of it's refresh token,
+// which revokes the whole session as the refresh token has probably been stolen.
+func (service *defaultService) RefreshToken(refresh string, token *model.Token) (err error) {
+	service, span := service.trace("RefreshToken")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := service.db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "create transaction failed")
+	}
+
+	oldToken, err := tx.GetTokenByRefreshHash(hashToken(refresh))
+	if err != nil {
+		service.logger().Error().
+			Err(err).
+			Msg("get token by refresh token failed")
+
+		tx.Rollback()
+		return errors.Wrap(err, "get token by refresh token failed")
+	}
+
+	if oldToken == nil {
+		tx.Rollback()
+		return &modelNotExistErr{"refresh token doesn't exist"}
+	}
+
+	if oldToken.Rotated {
+		service.logger().Warn().
+			Uint("user", oldToken.UserID).
+			Str("session", oldToken.Family).
+			Msg("reuse of refresh token detected, revoking session")
+
+		if err := tx.RemoveTokensByFamily(oldToken.Family); err != nil {
+			tx.Rollback()
+			return errors.Wrap(err, "remove tokens of session failed")
+		}
+
+		tx.Commit()
+		return &modelNotExistErr{"refresh token has already been used"}
+	}
+
+	if oldToken.RefreshExpiresAt.Before(time.Now()) {
+		tx.Rollback()
+		return &modelNotExistErr{"refresh token expired"}
+	}
+
+	oldToken.Rotated = true
+	if err := tx.UpdateToken(oldToken); err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "update token failed")
+	}
+
+	token.UserID = oldToken.UserID
+	token.Family = oldToken.Family
+	// the session keeps the time it has been started at
+	token.CreatedAt = oldToken.CreatedAt
+	token.RefreshHash = hashToken(token.Refresh)
+
+	if err := tx.AddToken(token); err != nil {
+		service.logger().Error().
+			Err(err).
+			Msg("add token failed")
+
+		tx.Rollback()
+		return errors.Wrap(err, "add token failed")
+	}
+
+	tx.Commit()
+	return nil
+}
+
+// TouchToken stores when and from where the token has been used last
+func (service *defaultService) TouchToken(token *model.Token) (err error) {
+	service, span := service.trace("TouchToken")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := service.db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "create transaction failed")
+	}
+
+	if err := tx.UpdateTokenLastSeen(token); err != nil {
+		service.logger().Error().
+			Err(err).
+			Uint("token", token.ID).
+			Msg("update token last seen failed")
+
+		tx.Rollback()
+		return errors.Wrap(err, "update token last seen failed")
+	}
+
+	tx.Commit()
+	return nil
+}
+
+// DeleteToken removes an active token from a user and ends it's session
+func (service *defaultService) DeleteToken(token *model.Token) (err error) {
+	service, span := service.trace("DeleteToken")
+	defer func() { tracing.End(span, err) }()
+
 	tx, err := service.db.Begin()
 	if err != nil {
 		return errors.Wrap(err, "create transaction failed")
@@ -86,13 +229,101 @@ func (service *defaultService) DeleteToken(token *model.Token) error {
 			return &modelNotExistErr{"token doesn't exist"}
 		}
 
-		log.Error().
+		service.logger().Error().
 			Err(err).
 			Msg("remove token failed")
 
 		return errors.Wrap(err, "remove token failed")
 	}
 
+	// remove rotated tokens of the session as well
+	if token.Family != "" {
+		if err := tx.RemoveTokensByFamily(token.Family); err != nil {
+			tx.Rollback()
+			return errors.Wrap(err, "remove tokens of session failed")
+		}
+	}
+
+	tx.Commit()
+	return nil
+}
+
+// DeleteTokensByUser ends all sessions of a user except the given one
+// and returns the active tokens of the ended sessions
+func (service *defaultService) DeleteTokensByUser(username, keepSession string) (tokens []*model.Token, err error) {
+	service, span := service.trace("DeleteTokensByUser")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := service.db.Begin()
+	if err != nil {
+		return nil, errors.Wrap(err, "create transaction failed")
+	}
+
+	tokens, err = tx.GetTokensByUser(username)
+	if err != nil {
+		service.logger().Error().
+			Err(err).
+			Str("user", username).
+			Msg("get tokens by user failed")
+
+		tx.Rollback()
+		return nil, errors.Wrap(err, "get tokens by user failed")
+	}
+
+	var removed []*model.Token
+	for _, token := range tokens {
+		if token.Session() == keepSession {
+			continue
+		}
+
+		if err := tx.RemoveToken(token); err != nil {
+			service.logger().Error().
+				Err(err).
+				Uint("token", token.ID).
+				Msg("remove token failed")
+
+			tx.Rollback()
+			return nil, errors.Wrap(err, "remove token failed")
+		}
+
+		if token.Family != "" {
+			if err := tx.RemoveTokensByFamily(token.Family); err != nil {
+				tx.Rollback()
+				return nil, errors.Wrap(err, "remove tokens of session failed")
+			}
+		}
+
+		removed = append(removed, token)
+	}
+
+	tx.Commit()
+	return removed, nil
+}
+
+// DeleteExpiredTokens removes all tokens which can't be refreshed anymore
+func (service *defaultService) DeleteExpiredTokens() (err error) {
+	service, span := service.trace("DeleteExpiredTokens")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := service.db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "create transaction failed")
+	}
+
+	if err := tx.RemoveExpiredTokens(time.Now()); err != nil {
+		service.logger().Error().
+			Err(err).
+			Msg("remove expired tokens failed")
+
+		tx.Rollback()
+		return errors.Wrap(err, "remove expired tokens failed")
+	}
+
 	tx.Commit()
 	return nil
 }
+
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
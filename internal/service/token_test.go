@@ -0,0 +1,165 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pagient/pagient-server/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDefaultService_RefreshToken(t *testing.T) {
+	tests := map[string]struct {
+		oldToken    *model.Token
+		revoked     bool
+		rotated     bool
+		notExistErr bool
+	}{
+		"unknown refresh token": {
+			oldToken:    nil,
+			revoked:     false,
+			rotated:     false,
+			notExistErr: true,
+		},
+		"expired refresh token": {
+			oldToken: &model.Token{
+				ID:               1,
+				UserID:           2,
+				Family:           "session",
+				RefreshExpiresAt: time.Now().Add(-time.Minute),
+			},
+			revoked:     false,
+			rotated:     false,
+			notExistErr: true,
+		},
+		"revoke session on reuse of refresh token": {
+			oldToken: &model.Token{
+				ID:               1,
+				UserID:           2,
+				Family:           "session",
+				Rotated:          true,
+				RefreshExpiresAt: time.Now().Add(time.Hour),
+			},
+			revoked:     true,
+			rotated:     false,
+			notExistErr: true,
+		},
+		"successfully rotate token": {
+			oldToken: &model.Token{
+				ID:               1,
+				UserID:           2,
+				Family:           "session",
+				RefreshExpiresAt: time.Now().Add(time.Hour),
+			},
+			revoked:     false,
+			rotated:     true,
+			notExistErr: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		tx := &MockTx{}
+		tx.On("GetTokenByRefreshHash", hashToken("refresh")).Return(test.oldToken, nil).Once()
+
+		if test.revoked {
+			tx.On("RemoveTokensByFamily", "session").Return(nil).Once()
+		}
+
+		if test.rotated {
+			tx.On("UpdateToken", mock.MatchedBy(func(token *model.Token) bool {
+				return token.ID == 1 && token.Rotated
+			})).Return(nil).Once()
+			tx.On("AddToken", mock.MatchedBy(func(token *model.Token) bool {
+				return token.UserID == 2 && token.Family == "session" && token.RefreshHash == hashToken("new refresh")
+			})).Return(nil).Once()
+		}
+
+		if test.revoked || test.rotated {
+			tx.On("Commit").Return(nil).Once()
+		} else {
+			tx.On("Rollback").Return(nil).Once()
+		}
+
+		db := &MockDB{}
+		db.On("Begin").Return(tx, nil).Once()
+
+		s := NewService(db, nil)
+		err := s.RefreshToken("refresh", &model.Token{Refresh: "new refresh"})
+
+		assert.Equal(t, test.notExistErr, IsModelNotExistErr(err))
+		if !test.notExistErr {
+			assert.NoError(t, err)
+		}
+
+		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
+	}
+}
+
+func TestDefaultService_DeleteTokensByUser(t *testing.T) {
+	tokenPool := map[int]*model.Token{
+		1: {
+			ID:     1,
+			Family: "current",
+		},
+		2: {
+			ID:     2,
+			Family: "other",
+		},
+		3: {
+			// token created before sessions could be refreshed
+			ID: 3,
+		},
+	}
+
+	tests := map[string]struct {
+		keepSession string
+		removed     []*model.Token
+	}{
+		"revoke all sessions": {
+			keepSession: "",
+			removed: []*model.Token{
+				tokenPool[1],
+				tokenPool[2],
+				tokenPool[3],
+			},
+		},
+		"revoke all sessions except current": {
+			keepSession: "current",
+			removed: []*model.Token{
+				tokenPool[2],
+				tokenPool[3],
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		tx := &MockTx{}
+		tx.On("GetTokensByUser", "user").Return([]*model.Token{tokenPool[1], tokenPool[2], tokenPool[3]}, nil).Once()
+		for _, token := range test.removed {
+			tx.On("RemoveToken", token).Return(nil).Once()
+			if token.Family != "" {
+				tx.On("RemoveTokensByFamily", token.Family).Return(nil).Once()
+			}
+		}
+		tx.On("Commit").Return(nil).Once()
+
+		db := &MockDB{}
+		db.On("Begin").Return(tx, nil).Once()
+
+		s := NewService(db, nil)
+		removed, err := s.DeleteTokensByUser("user", test.keepSession)
+
+		assert.NoError(t, err)
+		assert.Equal(t, test.removed, removed)
+
+		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
+	}
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+
+	"github.com/pagient/pagient-server/internal/tracing"
+
+	"go.opencensus.io/trace"
+)
+
+// trace starts the span of a service method,
+// the returned copy of the service traces its operations as children of it
+func (service *defaultService) trace(method string) (*defaultService, *trace.Span) {
+	ctx, span := tracing.Start(service.context(), "service."+method)
+
+	db := service.db
+	if traced, ok := db.(*tracedDB); ok {
+		db = traced.DB
+	}
+
+	scoped := *service
+	scoped.ctx = ctx
+	scoped.db = &tracedDB{db, ctx}
+
+	return &scoped, span
+}
+
+func (service *defaultService) context() context.Context {
+	if service.ctx == nil {
+		return context.Background()
+	}
+
+	return service.ctx
+}
+
+// tracedDB traces transactions from begin until commit or rollback
+type tracedDB struct {
+	DB
+	ctx context.Context
+}
+
+func (db *tracedDB) Begin() (Tx, error) {
+	_, span := tracing.Start(db.ctx, "db.transaction")
+
+	tx, err := db.DB.Begin()
+	if err != nil {
+		tracing.End(span, err)
+		return tx, err
+	}
+
+	return &tracedTx{tx, span}, nil
+}
+
+type tracedTx struct {
+	Tx
+	span *trace.Span
+}
+
+func (tx *tracedTx) Commit() error {
+	err := tx.Tx.Commit()
+	tracing.End(tx.span, err)
+
+	return err
+}
+
+func (tx *tracedTx) Rollback() error {
+	err := tx.Tx.Rollback()
+	tx.span.AddAttributes(trace.BoolAttribute("db.rollback", true))
+	tracing.End(tx.span, err)
+
+	return err
+}
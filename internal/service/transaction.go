@@ -0,0 +1,71 @@
+package service
+
+import (
+	"github.com/pagient/pagient-server/internal/model"
+
+	"github.com/pkg/errors"
+)
+
+// transaction wraps a database transaction and collects ui notifications,
+// they get dispatched only after the transaction has been committed successfully
+type transaction struct {
+	Tx
+
+	notifier UINotifier
+	pending  []func()
+}
+
+// begin starts a new transaction collecting ui notifications
+func (service *defaultService) begin() (*transaction, error) {
+	tx, err := service.db.Begin()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &transaction{Tx: tx, notifier: service.notifier}, nil
+}
+
+// Commit commits the transaction and dispatches all collected notifications
+func (t *transaction) Commit() error {
+	if err := t.Tx.Commit(); err != nil {
+		t.pending = nil
+		return errors.Wrap(err, "commit transaction failed")
+	}
+
+	for _, notify := range t.pending {
+		notify()
+	}
+	t.pending = nil
+
+	return nil
+}
+
+// Rollback aborts the transaction and discards all collected notifications
+func (t *transaction) Rollback() error {
+	t.pending = nil
+	return t.Tx.Rollback()
+}
+
+func (t *transaction) notifyNewVisit(visit *model.Visit) {
+	if t.notifier != nil {
+		t.pending = append(t.pending, func() {
+			t.notifier.NotifyNewVisit(visit)
+		})
+	}
+}
+
+func (t *transaction) notifyUpdatedVisit(visit *model.Visit) {
+	if t.notifier != nil {
+		t.pending = append(t.pending, func() {
+			t.notifier.NotifyUpdatedVisit(visit)
+		})
+	}
+}
+
+func (t *transaction) notifyDeletedVisit(visit *model.Visit) {
+	if t.notifier != nil {
+		t.pending = append(t.pending, func() {
+			t.notifier.NotifyDeletedVisit(visit)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/pagient/pagient-server/internal/model"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTransaction_Commit(t *testing.T) {
+	tests := map[string]struct {
+		commitErr error
+		rollback  bool
+		notified  bool
+	}{
+		"notify after successful commit": {
+			commitErr: nil,
+			rollback:  false,
+			notified:  true,
+		},
+		"discard notifications on failed commit": {
+			commitErr: errors.New("test error"),
+			rollback:  false,
+			notified:  false,
+		},
+		"discard notifications on rollback": {
+			commitErr: nil,
+			rollback:  true,
+			notified:  false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		tx := &MockTx{}
+		if test.rollback {
+			tx.On("Rollback").Return(nil).Once()
+		} else {
+			tx.On("Commit").Return(test.commitErr).Once()
+		}
+
+		db := &MockDB{}
+		db.On("Begin").Return(tx, nil).Once()
+
+		notifier := &MockUINotifier{}
+		if test.notified {
+			notifier.On("NotifyDeletedVisit", mock.AnythingOfType("*model.Visit")).Return().Once()
+		}
+
+		s := &defaultService{db: db, notifier: notifier}
+		trx, err := s.begin()
+		assert.NoError(t, err)
+
+		trx.notifyDeletedVisit(&model.Visit{ID: 1})
+		notifier.AssertNotCalled(t, "NotifyDeletedVisit", mock.Anything)
+
+		if test.rollback {
+			assert.NoError(t, trx.Rollback())
+		} else if test.commitErr != nil {
+			assert.EqualError(t, errors.Cause(trx.Commit()), test.commitErr.Error())
+		} else {
+			assert.NoError(t, trx.Commit())
+		}
+
+		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
+		notifier.AssertExpectations(t)
+	}
+}
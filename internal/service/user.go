@@ -1,23 +1,30 @@
 package service
 
 import (
+	"fmt"
+	"time"
+
+	"github.com/pagient/pagient-server/internal/config"
 	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/tracing"
 
 	"github.com/pkg/errors"
-	"github.com/rs/zerolog/log"
 	"golang.org/x/crypto/bcrypt"
 )
 
 // ListUsers returns all users
-func (service *defaultService) ListUsers() ([]*model.User, error) {
+func (service *defaultService) ListUsers() (users []*model.User, err error) {
+	service, span := service.trace("ListUsers")
+	defer func() { tracing.End(span, err) }()
+
 	tx, err := service.db.Begin()
 	if err != nil {
 		return nil, errors.Wrap(err, "create transaction failed")
 	}
 
-	users, err := tx.GetUsers()
+	users, err = tx.GetUsers()
 	if err != nil {
-		log.Error().
+		service.logger().Error().
 			Err(err).
 			Msg("get all users failed")
 
@@ -30,15 +37,18 @@ func (service *defaultService) ListUsers() ([]*model.User, error) {
 }
 
 // ShowUser returns a user by it's username
-func (service *defaultService) ShowUser(username string) (*model.User, error) {
+func (service *defaultService) ShowUser(username string) (user *model.User, err error) {
+	service, span := service.trace("ShowUser")
+	defer func() { tracing.End(span, err) }()
+
 	tx, err := service.db.Begin()
 	if err != nil {
 		return nil, errors.Wrap(err, "create transaction failed")
 	}
 
-	user, err := tx.GetUser(username)
+	user, err = tx.GetUser(username)
 	if err != nil {
-		log.Error().
+		service.logger().Error().
 			Err(err).
 			Msg("get user failed")
 
@@ -51,15 +61,18 @@ func (service *defaultService) ShowUser(username string) (*model.User, error) {
 }
 
 // ShowUserByToken returns a user by token
-func (service *defaultService) ShowUserByToken(rawToken string) (*model.User, error) {
+func (service *defaultService) ShowUserByToken(rawToken string) (user *model.User, err error) {
+	service, span := service.trace("ShowUserByToken")
+	defer func() { tracing.End(span, err) }()
+
 	tx, err := service.db.Begin()
 	if err != nil {
 		return nil, errors.Wrap(err, "create transaction failed")
 	}
 
-	user, err := tx.GetUserByToken(rawToken)
+	user, err = tx.GetUserByToken(rawToken)
 	if err != nil {
-		log.Error().
+		service.logger().Error().
 			Err(err).
 			Msg("get user by token failed")
 
@@ -72,7 +85,14 @@ func (service *defaultService) ShowUserByToken(rawToken string) (*model.User, er
 }
 
 // CreateUser creates a new user
-func (service *defaultService) CreateUser(user *model.User) error {
+func (service *defaultService) CreateUser(user *model.User) (err error) {
+	service, span := service.trace("CreateUser")
+	defer func() { tracing.End(span, err) }()
+
+	if user.Role == "" {
+		user.Role = model.UserRoleUser
+	}
+
 	tx, err := service.db.Begin()
 	if err != nil {
 		return errors.Wrap(err, "create transaction failed")
@@ -91,7 +111,7 @@ func (service *defaultService) CreateUser(user *model.User) error {
 
 	err = tx.AddUser(user)
 	if err != nil {
-		log.Error().
+		service.logger().Error().
 			Err(err).
 			Msg("add user failed")
 
@@ -104,8 +124,11 @@ func (service *defaultService) CreateUser(user *model.User) error {
 }
 
 // ChangeUserPassword changes password of given user
-func (service *defaultService) ChangeUserPassword(user *model.User) error {
-	if err := user.ValidatePasswordChange(); err != nil {
+func (service *defaultService) ChangeUserPassword(user *model.User) (err error) {
+	service, span := service.trace("ChangeUserPassword")
+	defer func() { tracing.End(span, err) }()
+
+	if err := user.ValidatePasswordChange(passwordPolicy()); err != nil {
 		if model.IsValidationErr(err) {
 			return &modelValidationErr{err.Error()}
 		}
@@ -125,18 +148,22 @@ func (service *defaultService) ChangeUserPassword(user *model.User) error {
 
 	user, err = tx.GetUser(user.Username)
 	if err != nil {
-		log.Error().
+		service.logger().Error().
 			Err(err).
 			Msg("get user failed")
+
+		tx.Rollback()
+		return errors.Wrap(err, "get user failed")
 	}
 	if user == nil {
+		tx.Rollback()
 		return &modelNotExistErr{"user doesn't exist"}
 	}
 
 	user.Password = passwordHash
 	err = tx.UpdateUserPassword(user)
 	if err != nil {
-		log.Error().
+		service.logger().Error().
 			Err(err).
 			Msg("update user password failed")
 
@@ -148,31 +175,537 @@ func (service *defaultService) ChangeUserPassword(user *model.User) error {
 	return nil
 }
 
-// Login checks whether the combination of username and password is valid
-func (service *defaultService) Login(username, password string) (*model.User, bool, error) {
+// ChangeUserRole changes role of given user
+func (service *defaultService) ChangeUserRole(user *model.User) (err error) {
+	service, span := service.trace("ChangeUserRole")
+	defer func() { tracing.End(span, err) }()
+
+	if err := user.ValidateRoleChange(); err != nil {
+		if model.IsValidationErr(err) {
+			return &modelValidationErr{err.Error()}
+		}
+
+		return errors.Wrap(err, "validate user failed")
+	}
+
 	tx, err := service.db.Begin()
 	if err != nil {
-		return nil, false, errors.Wrap(err, "create transaction failed")
+		return errors.Wrap(err, "create transaction failed")
 	}
 
-	user, err := tx.GetUser(username)
+	existing, err := tx.GetUser(user.Username)
 	if err != nil {
-		log.Error().
+		service.logger().Error().
 			Err(err).
 			Msg("get user failed")
 
 		tx.Rollback()
-		return nil, false, errors.Wrap(err, "get user failed")
+		return errors.Wrap(err, "get user failed")
+	}
+	if existing == nil {
+		tx.Rollback()
+		return &modelNotExistErr{"user doesn't exist"}
+	}
+
+	existing.Role = user.Role
+	if err := tx.UpdateUserRole(existing); err != nil {
+		service.logger().Error().
+			Err(err).
+			Msg("update user role failed")
+
+		tx.Rollback()
+		return errors.Wrap(err, "update user role failed")
 	}
 
 	tx.Commit()
-	return user, user != nil && comparePasswords(user.Password, password), nil
+	return nil
+}
+
+// UpdateUser changes role and client of given user,
+// every client can only be assigned to one user
+func (service *defaultService) UpdateUser(user *model.User) (err error) {
+	service, span := service.trace("UpdateUser")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := service.db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "create transaction failed")
+	}
+
+	clients, err := tx.GetClients()
+	if err != nil {
+		service.logger().Error().
+			Err(err).
+			Msg("get all clients failed")
+
+		tx.Rollback()
+		return errors.Wrap(err, "get all clients failed")
+	}
+
+	if err := user.ValidateUpdate(clients); err != nil {
+		tx.Rollback()
+
+		if model.IsValidationErr(err) {
+			return &modelValidationErr{err.Error()}
+		}
+
+		return errors.Wrap(err, "validate user failed")
+	}
+
+	users, err := tx.GetUsers()
+	if err != nil {
+		service.logger().Error().
+			Err(err).
+			Msg("get all users failed")
+
+		tx.Rollback()
+		return errors.Wrap(err, "get all users failed")
+	}
+
+	var existing *model.User
+	for _, u := range users {
+		if u.Username == user.Username {
+			existing = u
+			continue
+		}
+
+		if user.ClientID != nil && u.ClientID != nil && *u.ClientID == *user.ClientID {
+			tx.Rollback()
+			return &modelValidationErr{fmt.Sprintf("clientId: is already assigned to user %s.", u.Username)}
+		}
+	}
+	if existing == nil {
+		tx.Rollback()
+		return &modelNotExistErr{"user doesn't exist"}
+	}
+
+	existing.Role = user.Role
+	existing.ClientID = user.ClientID
+	if err := tx.UpdateUser(existing); err != nil {
+		service.logger().Error().
+			Err(err).
+			Msg("update user failed")
+
+		tx.Rollback()
+		return errors.Wrap(err, "update user failed")
+	}
+
+	tx.Commit()
+
+	*user = *existing
+	return nil
+}
+
+// LinkUser links an existing account to the subject of the openid connect provider,
+// so accounts with a local password can be taken over by single sign-on explicitly only
+func (service *defaultService) LinkUser(user *model.User) (err error) {
+	service, span := service.trace("LinkUser")
+	defer func() { tracing.End(span, err) }()
+
+	if err := user.ValidateLink(); err != nil {
+		if model.IsValidationErr(err) {
+			return &modelValidationErr{err.Error()}
+		}
+
+		return errors.Wrap(err, "validate user failed")
+	}
+
+	tx, err := service.db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "create transaction failed")
+	}
+
+	existing, err := tx.GetUser(user.Username)
+	if err != nil {
+		service.logger().Error().
+			Err(err).
+			Msg("get user failed")
+
+		tx.Rollback()
+		return errors.Wrap(err, "get user failed")
+	}
+	if existing == nil {
+		tx.Rollback()
+		return &modelNotExistErr{"user doesn't exist"}
+	}
+
+	linked, err := tx.GetUserBySubject(*user.Subject)
+	if err != nil {
+		service.logger().Error().
+			Err(err).
+			Msg("get user by subject failed")
+
+		tx.Rollback()
+		return errors.Wrap(err, "get user by subject failed")
+	}
+	if linked != nil && linked.ID != existing.ID {
+		tx.Rollback()
+		return &modelExistErr{fmt.Sprintf("subject is already linked to user %s", linked.Username)}
+	}
+
+	existing.Origin = model.UserOriginOIDC
+	existing.Subject = user.Subject
+	if err := tx.UpdateUser(existing); err != nil {
+		service.logger().Error().
+			Err(err).
+			Msg("update user failed")
+
+		tx.Rollback()
+		return errors.Wrap(err, "update user failed")
+	}
+
+	tx.Commit()
+
+	service.logger().Info().
+		Str("user", existing.Username).
+		Str("subject", *existing.Subject).
+		Msg("user linked to openid connect provider")
+
+	*user = *existing
+	return nil
+}
+
+// DeleteUser deletes given user and ends all of it's sessions
+func (service *defaultService) DeleteUser(user *model.User) (err error) {
+	service, span := service.trace("DeleteUser")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := service.db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "create transaction failed")
+	}
+
+	existing, err := tx.GetUser(user.Username)
+	if err != nil {
+		service.logger().Error().
+			Err(err).
+			Msg("get user failed")
+
+		tx.Rollback()
+		return errors.Wrap(err, "get user failed")
+	}
+	if existing == nil {
+		tx.Rollback()
+		return &modelNotExistErr{"user doesn't exist"}
+	}
+
+	if err := tx.RemoveUser(existing); err != nil {
+		service.logger().Error().
+			Err(err).
+			Msg("remove user failed")
+
+		tx.Rollback()
+		return errors.Wrap(err, "remove user failed")
+	}
+
+	tx.Commit()
+	return nil
+}
+
+// Login checks whether the combination of username and password is valid,
+// against the user directories for new and directory users, otherwise against the local accounts.
+// Failed logins are recorded and lock the user out after too many of them.
+func (service *defaultService) Login(username, password, ip string) (user *model.User, valid bool, err error) {
+	service, span := service.trace("Login")
+	defer func() { tracing.End(span, err) }()
+
+	// the directories are queried without holding a transaction
+	user, err = service.ShowUser(username)
+	if err != nil {
+		return nil, false, errors.WithStack(err)
+	}
+
+	now := time.Now()
+	var external *model.User
+	// local accounts are never looked up in the directories, so directory users can't take them over
+	if user == nil || (!user.IsLocked(now) && user.IsProvisionedBy(model.UserOriginLDAP)) {
+		external = service.authenticate(username, password)
+	}
+
+	tx, err := service.db.Begin()
+	if err != nil {
+		return nil, false, errors.Wrap(err, "create transaction failed")
+	}
+
+	attempt := &model.LoginAttempt{
+		Username: username,
+		IP:       ip,
+	}
+
+	switch {
+	case user != nil && user.IsLocked(now):
+		attempt.Reason = model.LoginFailureLocked
+	case external != nil:
+		user, err = service.provisionUser(tx, user, external)
+		if err != nil {
+			tx.Rollback()
+			return nil, false, errors.WithStack(err)
+		}
+
+		tx.Commit()
+		return user, true, nil
+	case user == nil:
+		attempt.Reason = model.LoginFailureUnknownUser
+	case comparePasswords(user.Password, password):
+		if user.FailedLogins > 0 {
+			user.FailedLogins = 0
+			if err := tx.UpdateUserLogin(user); err != nil {
+				tx.Rollback()
+				return nil, false, errors.Wrap(err, "update user login failed")
+			}
+		}
+
+		tx.Commit()
+		return user, true, nil
+	default:
+		attempt.Reason = model.LoginFailureWrongPassword
+
+		if err := tx.IncrementUserFailedLogins(user); err != nil {
+			tx.Rollback()
+			return nil, false, errors.Wrap(err, "increment failed logins failed")
+		}
+
+		// concurrent logins may have failed as well, so the threshold is evaluated on the stored count
+		stored, err := tx.GetUser(username)
+		if err != nil {
+			tx.Rollback()
+			return nil, false, errors.Wrap(err, "get user failed")
+		}
+		if stored != nil {
+			user = stored
+		}
+
+		if config.Auth.LockoutThreshold > 0 && user.FailedLogins >= config.Auth.LockoutThreshold {
+			service.logger().Warn().
+				Str("user", username).
+				Str("ip", ip).
+				Int("failed", user.FailedLogins).
+				Msg("too many failed logins, locking user out")
+
+			user.FailedLogins = 0
+			user.LockedUntil = now.Add(config.Auth.LockoutDuration)
+
+			if err := tx.UpdateUserLogin(user); err != nil {
+				tx.Rollback()
+				return nil, false, errors.Wrap(err, "update user login failed")
+			}
+		}
+	}
+
+	if err := tx.AddLoginAttempt(attempt); err != nil {
+		service.logger().Error().
+			Err(err).
+			Msg("add login attempt failed")
+
+		tx.Rollback()
+		return nil, false, errors.Wrap(err, "add login attempt failed")
+	}
+
+	tx.Commit()
+
+	if user != nil && user.IsLocked(now) {
+		return user, false, &userLockedErr{"user is locked out"}
+	}
+
+	return user, false, nil
+}
+
+// LoginExternal provisions the local user of a user authenticated by a single sign-on provider.
+// Users are identified by their subject, accounts are linked by username only if they have neither
+// a local password nor a subject yet, other accounts have to be linked by an admin.
+func (service *defaultService) LoginExternal(external *model.User, ip string) (user *model.User, err error) {
+	service, span := service.trace("LoginExternal")
+	defer func() { tracing.End(span, err) }()
+
+	if external.Subject == nil || *external.Subject == "" {
+		return nil, &invalidArgumentErr{"subject of external user is missing"}
+	}
+
+	tx, err := service.db.Begin()
+	if err != nil {
+		return nil, errors.Wrap(err, "create transaction failed")
+	}
+
+	user, err = tx.GetUserBySubject(*external.Subject)
+	if err != nil {
+		service.logger().Error().
+			Err(err).
+			Msg("get user by subject failed")
+
+		tx.Rollback()
+		return nil, errors.Wrap(err, "get user by subject failed")
+	}
+
+	if user == nil {
+		user, err = tx.GetUser(external.Username)
+		if err != nil {
+			service.logger().Error().
+				Err(err).
+				Msg("get user failed")
+
+			tx.Rollback()
+			return nil, errors.Wrap(err, "get user failed")
+		}
+
+		if user != nil && (user.Password != "" || user.Subject != nil) {
+			service.logger().Warn().
+				Str("user", user.Username).
+				Str("subject", *external.Subject).
+				Msg("single sign-on user matches an account which isn't linked to it")
+
+			tx.Rollback()
+			return nil, &userNotLinkedErr{"account has to be linked by an admin"}
+		}
+	}
+
+	if user != nil && user.IsLocked(time.Now()) {
+		if err := tx.AddLoginAttempt(&model.LoginAttempt{
+			Username: external.Username,
+			IP:       ip,
+			Reason:   model.LoginFailureLocked,
+		}); err != nil {
+			tx.Rollback()
+			return nil, errors.Wrap(err, "add login attempt failed")
+		}
+
+		tx.Commit()
+		return nil, &userLockedErr{"user is locked out"}
+	}
+
+	user, err = service.provisionUser(tx, user, external)
+	if err != nil {
+		tx.Rollback()
+		return nil, errors.WithStack(err)
+	}
+
+	tx.Commit()
+	return user, nil
+}
+
+// authenticate returns the user of the first directory accepting the credentials,
+// unreachable directories are skipped so local accounts keep working
+func (service *defaultService) authenticate(username, password string) *model.User {
+	for _, authenticator := range service.authenticators {
+		user, err := authenticator.Authenticate(username, password)
+		if err != nil {
+			service.logger().Error().
+				Err(err).
+				Str("user", username).
+				Msg("authenticate user against directory failed")
+
+			continue
+		}
+
+		if user != nil {
+			return user
+		}
+	}
+
+	return nil
+}
+
+// provisionUser creates or updates the local user of a user authenticated by a directory,
+// so role and client follow the group memberships in the directory
+func (service *defaultService) provisionUser(tx Tx, user, external *model.User) (*model.User, error) {
+	clientID, err := service.directoryClientID(tx, external)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if user == nil {
+		// directory users have no local password, so they can only login through the directory
+		user = &model.User{
+			Username: external.Username,
+			Role:     external.Role,
+			ClientID: clientID,
+			Origin:   external.Origin,
+			Subject:  external.Subject,
+		}
+
+		if err := tx.AddUser(user); err != nil {
+			service.logger().Error().
+				Err(err).
+				Msg("add user failed")
+
+			return nil, errors.Wrap(err, "add user failed")
+		}
+
+		service.logger().Info().
+			Str("user", user.Username).
+			Str("role", string(user.Role)).
+			Msg("user provisioned from directory")
+
+		return user, nil
+	}
+
+	user.Role = external.Role
+	user.ClientID = clientID
+	user.Origin = external.Origin
+	if external.Subject != nil {
+		user.Subject = external.Subject
+	}
+	user.FailedLogins = 0
+
+	if err := tx.UpdateUser(user); err != nil {
+		service.logger().Error().
+			Err(err).
+			Msg("update user failed")
+
+		return nil, errors.Wrap(err, "update user failed")
+	}
+
+	return user, nil
+}
+
+// directoryClientID returns the id of the client assigned by the directory,
+// clients already assigned to other users are skipped as every client belongs to one user
+func (service *defaultService) directoryClientID(tx Tx, external *model.User) (*uint, error) {
+	if external.Client.Name == "" {
+		return nil, nil
+	}
+
+	clients, err := tx.GetClients()
+	if err != nil {
+		return nil, errors.Wrap(err, "get all clients failed")
+	}
+
+	for _, client := range clients {
+		if client.Name != external.Client.Name {
+			continue
+		}
+
+		users, err := tx.GetUsers()
+		if err != nil {
+			return nil, errors.Wrap(err, "get all users failed")
+		}
+
+		for _, user := range users {
+			if user.Username != external.Username && user.ClientID != nil && *user.ClientID == client.ID {
+				service.logger().Warn().
+					Str("user", external.Username).
+					Str("client", client.Name).
+					Msg("client of directory user already assigned to another user")
+
+				return nil, nil
+			}
+		}
+
+		clientID := client.ID
+		return &clientID, nil
+	}
+
+	service.logger().Warn().
+		Str("user", external.Username).
+		Str("client", external.Client.Name).
+		Msg("client of directory user doesn't exist")
+
+	return nil, nil
 }
 
 func (service *defaultService) validateUser(tx Tx, user *model.User) error {
 	var clients []*model.Client
 
-	if user.ClientID != 0 {
+	if user.ClientID != nil {
 		// load clients to validate if client sent with request is valid
 		var err error
 		clients, err = tx.GetClients()
@@ -182,7 +715,7 @@ func (service *defaultService) validateUser(tx Tx, user *model.User) error {
 	}
 
 	// validate user
-	if err := user.Validate(clients); err != nil {
+	if err := user.Validate(clients, passwordPolicy()); err != nil {
 		if model.IsValidationErr(err) {
 			return &modelValidationErr{err.Error()}
 		}
@@ -193,6 +726,17 @@ func (service *defaultService) validateUser(tx Tx, user *model.User) error {
 	return nil
 }
 
+// passwordPolicy returns the configured requirements for user passwords
+func passwordPolicy() *model.PasswordPolicy {
+	return &model.PasswordPolicy{
+		MinLength:     config.Auth.PasswordMinLength,
+		RequireUpper:  config.Auth.PasswordRequireUpper,
+		RequireLower:  config.Auth.PasswordRequireLower,
+		RequireDigit:  config.Auth.PasswordRequireDigit,
+		RequireSymbol: config.Auth.PasswordRequireSymbol,
+	}
+}
+
 func hashPassword(plainPwd string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(plainPwd), bcrypt.DefaultCost)
 	return string(bytes), errors.Wrap(err, "generate bcrypt hash from password failed")
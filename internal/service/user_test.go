@@ -0,0 +1,523 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pagient/pagient-server/internal/config"
+	"github.com/pagient/pagient-server/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDefaultService_Login(t *testing.T) {
+	config.Auth.LockoutThreshold = 3
+	config.Auth.LockoutDuration = time.Minute
+
+	hash, err := hashPassword("secret")
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		user         *model.User
+		external     *model.User
+		externalErr  error
+		password     string
+		provisioned  bool
+		updated      bool
+		valid        bool
+		lockedErr    bool
+		reason       model.LoginFailure
+		failedLogins int
+		locked       bool
+	}{
+		"unknown user": {
+			user:     nil,
+			password: "secret",
+			valid:    false,
+			reason:   model.LoginFailureUnknownUser,
+		},
+		"successful login resets failed logins": {
+			user: &model.User{
+				ID:           1,
+				Password:     hash,
+				FailedLogins: 2,
+			},
+			password:     "secret",
+			valid:        true,
+			failedLogins: 0,
+		},
+		"wrong password counts failed login": {
+			user: &model.User{
+				ID:           1,
+				Password:     hash,
+				FailedLogins: 1,
+			},
+			password:     "wrong",
+			valid:        false,
+			reason:       model.LoginFailureWrongPassword,
+			failedLogins: 2,
+		},
+		"too many wrong passwords lock user out": {
+			user: &model.User{
+				ID:           1,
+				Password:     hash,
+				FailedLogins: 2,
+			},
+			password:     "wrong",
+			valid:        false,
+			lockedErr:    true,
+			reason:       model.LoginFailureWrongPassword,
+			failedLogins: 0,
+			locked:       true,
+		},
+		"directory user gets provisioned": {
+			user: nil,
+			external: &model.User{
+				Username: "user",
+				Role:     model.UserRoleAdmin,
+				Origin:   model.UserOriginLDAP,
+			},
+			password:    "directory secret",
+			provisioned: true,
+			valid:       true,
+		},
+		"directory user gets updated": {
+			user: &model.User{
+				ID:       1,
+				Username: "user",
+				Role:     model.UserRoleUser,
+				Origin:   model.UserOriginLDAP,
+			},
+			external: &model.User{
+				Username: "user",
+				Role:     model.UserRoleAdmin,
+				Origin:   model.UserOriginLDAP,
+			},
+			password: "directory secret",
+			updated:  true,
+			valid:    true,
+		},
+		"local account isn't looked up in directory": {
+			user: &model.User{
+				ID:       1,
+				Username: "user",
+				Password: hash,
+				Origin:   model.UserOriginLocal,
+			},
+			password:     "directory secret",
+			valid:        false,
+			reason:       model.LoginFailureWrongPassword,
+			failedLogins: 1,
+		},
+		"unreachable directory rejects directory user": {
+			user: &model.User{
+				ID:     1,
+				Origin: model.UserOriginLDAP,
+			},
+			externalErr:  errors.New("connect to ldap server failed"),
+			password:     "secret",
+			valid:        false,
+			reason:       model.LoginFailureWrongPassword,
+			failedLogins: 1,
+		},
+		"locked user can't login with right password": {
+			user: &model.User{
+				ID:          1,
+				Password:    hash,
+				LockedUntil: time.Now().Add(time.Minute),
+			},
+			password:  "secret",
+			valid:     false,
+			lockedErr: true,
+			reason:    model.LoginFailureLocked,
+			locked:    true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		tx := &MockTx{}
+		tx.On("GetUser", "user").Return(test.user, nil).Once()
+
+		authenticator := &MockAuthenticator{}
+		if test.user == nil || (test.reason != model.LoginFailureLocked && test.user.Origin == model.UserOriginLDAP) {
+			authenticator.On("Authenticate", "user", test.password).Return(test.external, test.externalErr).Once()
+		}
+
+		if test.provisioned {
+			tx.On("AddUser", mock.MatchedBy(func(user *model.User) bool {
+				return user.Username == "user" && user.Role == model.UserRoleAdmin && user.Password == "" && user.Origin == model.UserOriginLDAP
+			})).Return(nil).Once()
+		}
+		if test.updated {
+			tx.On("UpdateUser", mock.MatchedBy(func(user *model.User) bool {
+				return user.ID == 1 && user.Role == model.UserRoleAdmin
+			})).Return(nil).Once()
+		}
+
+		if test.reason == model.LoginFailureWrongPassword {
+			tx.On("IncrementUserFailedLogins", test.user).Return(nil).Once()
+
+			// the failed login is counted by the database
+			stored := *test.user
+			stored.FailedLogins++
+			tx.On("GetUser", "user").Return(&stored, nil).Once()
+		}
+
+		if (test.valid && test.user != nil && test.user.FailedLogins > 0) || (test.locked && test.reason == model.LoginFailureWrongPassword) {
+			tx.On("UpdateUserLogin", mock.MatchedBy(func(user *model.User) bool {
+				return user.FailedLogins == test.failedLogins && user.IsLocked(time.Now()) == test.locked
+			})).Return(nil).Once()
+		}
+
+		if !test.valid {
+			tx.On("AddLoginAttempt", mock.MatchedBy(func(attempt *model.LoginAttempt) bool {
+				return attempt.Username == "user" && attempt.IP == "127.0.0.1" && attempt.Reason == test.reason
+			})).Return(nil).Once()
+		}
+
+		// the user is loaded before querying the directory in it's own transaction
+		tx.On("Commit").Return(nil).Twice()
+
+		db := &MockDB{}
+		db.On("Begin").Return(tx, nil).Twice()
+
+		s := NewService(db, nil, authenticator)
+		_, valid, err := s.Login("user", test.password, "127.0.0.1")
+
+		assert.Equal(t, test.valid, valid)
+		assert.Equal(t, test.lockedErr, IsUserLockedErr(err))
+		if !test.lockedErr {
+			assert.NoError(t, err)
+		}
+
+		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
+		authenticator.AssertExpectations(t)
+	}
+}
+
+func TestDefaultService_LoginExternal(t *testing.T) {
+	subject, otherSubject := "sub", "other"
+
+	tests := map[string]struct {
+		linked       *model.User
+		user         *model.User
+		provisioned  bool
+		updated      bool
+		lockedErr    bool
+		notLinkedErr bool
+	}{
+		"new user gets provisioned": {
+			provisioned: true,
+		},
+		"linked user gets updated": {
+			linked: &model.User{
+				ID:       1,
+				Username: "user",
+				Role:     model.UserRoleUser,
+				Origin:   model.UserOriginOIDC,
+				Subject:  &subject,
+			},
+			updated: true,
+		},
+		"directory user without password gets linked": {
+			user: &model.User{
+				ID:       1,
+				Username: "user",
+				Role:     model.UserRoleUser,
+				Origin:   model.UserOriginLDAP,
+			},
+			updated: true,
+		},
+		"local account isn't taken over": {
+			user: &model.User{
+				ID:       1,
+				Username: "user",
+				Password: "hash",
+				Role:     model.UserRoleUser,
+				Origin:   model.UserOriginLocal,
+			},
+			notLinkedErr: true,
+		},
+		"account linked to another subject isn't taken over": {
+			user: &model.User{
+				ID:       1,
+				Username: "user",
+				Role:     model.UserRoleUser,
+				Origin:   model.UserOriginOIDC,
+				Subject:  &otherSubject,
+			},
+			notLinkedErr: true,
+		},
+		"locked user can't login": {
+			linked: &model.User{
+				ID:          1,
+				Username:    "user",
+				Subject:     &subject,
+				LockedUntil: time.Now().Add(time.Minute),
+			},
+			lockedErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		tx := &MockTx{}
+		tx.On("GetUserBySubject", subject).Return(test.linked, nil).Once()
+		if test.linked == nil {
+			tx.On("GetUser", "user").Return(test.user, nil).Once()
+		}
+
+		isLinkedAdmin := mock.MatchedBy(func(user *model.User) bool {
+			return user.Username == "user" && user.Role == model.UserRoleAdmin &&
+				user.Origin == model.UserOriginOIDC && user.Subject != nil && *user.Subject == subject
+		})
+		if test.provisioned {
+			tx.On("AddUser", isLinkedAdmin).Return(nil).Once()
+		}
+		if test.updated {
+			tx.On("UpdateUser", isLinkedAdmin).Return(nil).Once()
+		}
+		if test.lockedErr {
+			tx.On("AddLoginAttempt", mock.MatchedBy(func(attempt *model.LoginAttempt) bool {
+				return attempt.Reason == model.LoginFailureLocked
+			})).Return(nil).Once()
+		}
+		if test.notLinkedErr {
+			tx.On("Rollback").Return(nil).Once()
+		} else {
+			tx.On("Commit").Return(nil).Once()
+		}
+
+		db := &MockDB{}
+		db.On("Begin").Return(tx, nil).Once()
+
+		s := NewService(db, nil)
+		user, err := s.LoginExternal(&model.User{
+			Username: "user",
+			Role:     model.UserRoleAdmin,
+			Origin:   model.UserOriginOIDC,
+			Subject:  &subject,
+		}, "127.0.0.1")
+
+		assert.Equal(t, test.lockedErr, IsUserLockedErr(err))
+		assert.Equal(t, test.notLinkedErr, IsUserNotLinkedErr(err))
+		if !test.lockedErr && !test.notLinkedErr {
+			assert.NoError(t, err)
+			assert.Equal(t, model.UserRoleAdmin, user.Role)
+		}
+
+		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
+	}
+}
+
+func TestDefaultService_LinkUser(t *testing.T) {
+	subject := "sub"
+
+	tests := map[string]struct {
+		subject     string
+		user        *model.User
+		linked      *model.User
+		invalidErr  bool
+		notExistErr bool
+		existErr    bool
+	}{
+		"local account gets linked": {
+			subject: subject,
+			user:    &model.User{ID: 1, Username: "user", Password: "hash", Origin: model.UserOriginLocal},
+		},
+		"subject missing": {
+			invalidErr: true,
+		},
+		"unknown user": {
+			subject:     subject,
+			notExistErr: true,
+		},
+		"subject linked to another user": {
+			subject:  subject,
+			user:     &model.User{ID: 1, Username: "user"},
+			linked:   &model.User{ID: 2, Username: "other", Subject: &subject},
+			existErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		tx := &MockTx{}
+		db := &MockDB{}
+
+		if !test.invalidErr {
+			db.On("Begin").Return(tx, nil).Once()
+			tx.On("GetUser", "user").Return(test.user, nil).Once()
+		}
+		if test.user != nil {
+			tx.On("GetUserBySubject", subject).Return(test.linked, nil).Once()
+		}
+		if test.user != nil && !test.existErr {
+			tx.On("UpdateUser", mock.MatchedBy(func(user *model.User) bool {
+				return user.ID == 1 && user.Origin == model.UserOriginOIDC && *user.Subject == subject
+			})).Return(nil).Once()
+			tx.On("Commit").Return(nil).Once()
+		} else if !test.invalidErr {
+			tx.On("Rollback").Return(nil).Once()
+		}
+
+		user := &model.User{Username: "user", Subject: &test.subject}
+
+		s := NewService(db, nil)
+		err := s.LinkUser(user)
+
+		assert.Equal(t, test.invalidErr, IsModelValidationErr(err))
+		assert.Equal(t, test.notExistErr, IsModelNotExistErr(err))
+		assert.Equal(t, test.existErr, IsModelExistErr(err))
+		if !test.invalidErr && !test.notExistErr && !test.existErr {
+			assert.NoError(t, err)
+			assert.Equal(t, "hash", user.Password)
+		}
+
+		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
+	}
+}
+
+func TestDefaultService_ChangeUserPassword(t *testing.T) {
+	tests := map[string]struct {
+		user       *model.User
+		getUserErr error
+		notExist   bool
+		dbErr      error
+	}{
+		"successfully change password": {
+			user: &model.User{ID: 1, Username: "user"},
+		},
+		"rollback on get user error": {
+			getUserErr: errors.New("test error"),
+		},
+		"rollback on unknown user": {
+			notExist: true,
+		},
+		"rollback on db error": {
+			user:  &model.User{ID: 1, Username: "user"},
+			dbErr: errors.New("test error"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		tx := &MockTx{}
+		tx.On("GetUser", "user").Return(test.user, test.getUserErr).Once()
+
+		if test.user != nil {
+			tx.On("UpdateUserPassword", test.user).Return(test.dbErr).Once()
+		}
+
+		if test.user == nil || test.dbErr != nil {
+			tx.On("Rollback").Return(nil).Once()
+		} else {
+			tx.On("Commit").Return(nil).Once()
+		}
+
+		db := &MockDB{}
+		db.On("Begin").Return(tx, nil).Once()
+
+		s := NewService(db, nil)
+		err := s.ChangeUserPassword(&model.User{Username: "user", Password: "Secret12!"})
+
+		switch {
+		case test.notExist:
+			assert.True(t, IsModelNotExistErr(err))
+		case test.getUserErr != nil || test.dbErr != nil:
+			assert.Error(t, err)
+		default:
+			assert.NoError(t, err)
+			assert.True(t, comparePasswords(test.user.Password, "Secret12!"))
+		}
+
+		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
+	}
+}
+
+func TestDefaultService_UpdateUser(t *testing.T) {
+	clientID := uint(1)
+	otherClientID := uint(2)
+	unknownClientID := uint(3)
+
+	tests := map[string]struct {
+		user          *model.User
+		validationErr bool
+		notExist      bool
+	}{
+		"successfully assign client": {
+			user: &model.User{Username: "user", Role: model.UserRoleUser, ClientID: &otherClientID},
+		},
+		"successfully remove client": {
+			user: &model.User{Username: "user", Role: model.UserRoleDisplay},
+		},
+		"client assigned to another user": {
+			user:          &model.User{Username: "user", Role: model.UserRoleUser, ClientID: &clientID},
+			validationErr: true,
+		},
+		"unknown client": {
+			user:          &model.User{Username: "user", Role: model.UserRoleUser, ClientID: &unknownClientID},
+			validationErr: true,
+		},
+		"unknown user": {
+			user:     &model.User{Username: "unknown", Role: model.UserRoleUser},
+			notExist: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		user := &model.User{ID: 2, Username: "user", Password: "hash", Role: model.UserRoleUser}
+
+		tx := &MockTx{}
+		tx.On("GetClients").Return([]*model.Client{{ID: clientID}, {ID: otherClientID}}, nil).Once()
+
+		if test.user.ClientID == nil || *test.user.ClientID != unknownClientID {
+			tx.On("GetUsers").Return([]*model.User{
+				{ID: 1, Username: "other", ClientID: &clientID},
+				user,
+			}, nil).Once()
+		}
+
+		if test.validationErr || test.notExist {
+			tx.On("Rollback").Return(nil).Once()
+		} else {
+			tx.On("UpdateUser", user).Return(nil).Once()
+			tx.On("Commit").Return(nil).Once()
+		}
+
+		db := &MockDB{}
+		db.On("Begin").Return(tx, nil).Once()
+
+		s := NewService(db, nil)
+		err := s.UpdateUser(test.user)
+
+		switch {
+		case test.validationErr:
+			assert.True(t, IsModelValidationErr(err))
+		case test.notExist:
+			assert.True(t, IsModelNotExistErr(err))
+		default:
+			assert.NoError(t, err)
+			assert.Equal(t, test.user.Role, user.Role)
+			assert.Equal(t, test.user.ClientID, user.ClientID)
+			// the password is kept as is
+			assert.Equal(t, "hash", user.Password)
+		}
+
+		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
+	}
+}
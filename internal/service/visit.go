@@ -0,0 +1,528 @@
+package service
+
+import (
+	"time"
+
+	"github.com/pagient/pagient-easy-call-go/easycall"
+	"github.com/pagient/pagient-server/internal/config"
+	"github.com/pagient/pagient-server/internal/metrics"
+	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/tracing"
+
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+)
+
+// averageWaitTimeWindow is how far back called visits are taken into account for the average wait time
+const averageWaitTimeWindow = 2 * time.Hour
+
+// ListVisits returns all open visits
+func (service *defaultService) ListVisits() (visits []*model.Visit, err error) {
+	service, span := service.trace("ListVisits")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := service.db.Begin()
+	if err != nil {
+		return nil, errors.Wrap(err, "create transaction failed")
+	}
+
+	visits, err = tx.GetVisits()
+	if err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "get all visits failed")
+	}
+
+	tx.Commit()
+	return visits, nil
+}
+
+// ListPagerVisitsByStatus returns all open visits with a pager by status
+func (service *defaultService) ListPagerVisitsByStatus(statuses ...model.VisitStatus) (visits []*model.Visit, err error) {
+	service, span := service.trace("ListPagerVisitsByStatus")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := service.db.Begin()
+	if err != nil {
+		return nil, errors.Wrap(err, "create transaction failed")
+	}
+
+	visits, err = tx.GetVisitsWithPagerByStatus(statuses...)
+	if err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "get visits having pagers by status failed")
+	}
+
+	tx.Commit()
+	return visits, nil
+}
+
+// CountVisitsByStatus returns the count of open visits per status
+func (service *defaultService) CountVisitsByStatus() (counts map[model.VisitStatus]int, err error) {
+	service, span := service.trace("CountVisitsByStatus")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := service.db.Begin()
+	if err != nil {
+		return nil, errors.Wrap(err, "create transaction failed")
+	}
+
+	counts, err = tx.CountVisitsByStatus()
+	if err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "count visits by status failed")
+	}
+
+	tx.Commit()
+	return counts, nil
+}
+
+// ShowVisit returns a visit by it's id
+func (service *defaultService) ShowVisit(id uint) (visit *model.Visit, err error) {
+	service, span := service.trace("ShowVisit")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := service.db.Begin()
+	if err != nil {
+		return nil, errors.Wrap(err, "create transaction failed")
+	}
+
+	visit, err = tx.GetVisit(id)
+	if err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "get visit failed")
+	}
+
+	tx.Commit()
+	return visit, nil
+}
+
+// ShowAverageWaitTime returns the average wait time until the first call of recently called patients
+func (service *defaultService) ShowAverageWaitTime() (wait time.Duration, err error) {
+	service, span := service.trace("ShowAverageWaitTime")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := service.db.Begin()
+	if err != nil {
+		return 0, errors.Wrap(err, "create transaction failed")
+	}
+
+	visits, err := tx.GetVisitsFirstCalledAfter(time.Now().Add(-averageWaitTimeWindow))
+	if err != nil {
+		tx.Rollback()
+		return 0, errors.Wrap(err, "get recently called visits failed")
+	}
+
+	tx.Commit()
+
+	if len(visits) == 0 {
+		return 0, nil
+	}
+
+	var total time.Duration
+	for _, visit := range visits {
+		total += visit.WaitTime(*visit.FirstCalledAt)
+	}
+
+	return total / time.Duration(len(visits)), nil
+}
+
+// CheckIn adds a new visit of the given patient, the patient is added or it's identity updated if it already exists.
+// A finished visit of the patient that is still open gets closed, unless it still holds a pager that has to be returned first.
+// Other open visits can't be checked in twice
+func (service *defaultService) CheckIn(visit *model.Visit) (err error) {
+	service, span := service.trace("CheckIn")
+	defer func() { tracing.End(span, err) }()
+
+	visit.Status = model.VisitStatusPending
+	visit.PatientID = visit.Patient.ID
+	if visit.PagerID != 0 {
+		now := time.Now()
+		visit.PagerAssignedAt = &now
+	}
+
+	if visit.ClientID == 0 {
+		return &invalidArgumentErr{"clientId: cannot be blank"}
+	}
+
+	if err := visit.Patient.Validate(); err != nil {
+		if model.IsValidationErr(err) {
+			return &modelValidationErr{err.Error()}
+		}
+
+		return errors.Wrap(err, "validate patient failed")
+	}
+
+	tx, err := service.begin()
+	if err != nil {
+		return errors.Wrap(err, "create transaction failed")
+	}
+
+	if err := service.validateVisit(tx, visit); err != nil {
+		tx.Rollback()
+		return errors.WithStack(err)
+	}
+
+	if visit.Active {
+		if err := service.markVisitsInactiveFromClient(tx, visit.ClientID); err != nil {
+			tx.Rollback()
+			return errors.WithStack(err)
+		}
+	}
+
+	if err := service.closeInactiveVisitsWithoutPagerFromClient(tx, visit.ClientID); err != nil {
+		tx.Rollback()
+		return errors.WithStack(err)
+	}
+
+	existing, err := tx.GetPatient(visit.PatientID)
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "get patient failed")
+	}
+
+	// a returning patient gets a new visit, so it's personal data is stored again
+	if err := service.upsertPatient(tx, &visit.Patient, existing); err != nil {
+		tx.Rollback()
+		return errors.WithStack(err)
+	}
+
+	openVisits, err := tx.GetVisitsByPatient(visit.PatientID)
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "get visits by patient failed")
+	}
+
+	for _, openVisit := range openVisits {
+		if openVisit.Status != model.VisitStatusFinished {
+			tx.Rollback()
+			return &modelExistErr{"patient has already checked in"}
+		}
+
+		// closing the visit would free the pager silently, although the patient hasn't returned it
+		if openVisit.PagerID != 0 {
+			tx.Rollback()
+			return &modelExistErr{"patient still holds the pager of the previous visit"}
+		}
+
+		if err := service.closeVisit(tx, openVisit); err != nil {
+			tx.Rollback()
+			return errors.WithStack(err)
+		}
+	}
+
+	// tickets restart every day, so they stay short enough to be read from the waiting-room displays
+	now := time.Now()
+	visit.Ticket, err = tx.NextVisitTicket(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()))
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "get next ticket failed")
+	}
+
+	if err := tx.AddVisit(visit); err != nil {
+		tx.Rollback()
+
+		if isEntryNotValidErr(err) {
+			return &modelValidationErr{err.Error()}
+		}
+
+		return errors.Wrap(err, "add visit failed")
+	}
+
+	tx.notifyNewVisit(visit)
+
+	return tx.Commit()
+}
+
+// UpdateVisit updates an existing open visit if given model is valid, the patient of a visit can't be changed
+func (service *defaultService) UpdateVisit(visit *model.Visit) (err error) {
+	service, span := service.trace("UpdateVisit")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := service.begin()
+	if err != nil {
+		return errors.Wrap(err, "create transaction failed")
+	}
+
+	// load visit's old state to compare changed properties
+	visitBeforeUpdate, err := tx.GetVisit(visit.ID)
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "get visit failed")
+	}
+
+	if visitBeforeUpdate == nil || visitBeforeUpdate.ClosedAt != nil {
+		tx.Rollback()
+		return &modelNotExistErr{"visit doesn't exist"}
+	}
+
+	visit.PatientID = visitBeforeUpdate.PatientID
+	visit.Patient = visitBeforeUpdate.Patient
+	visit.CreatedAt = visitBeforeUpdate.CreatedAt
+	visit.ClosedAt = visitBeforeUpdate.ClosedAt
+
+	// call times are only recorded by calls
+	visit.FirstCalledAt = visitBeforeUpdate.FirstCalledAt
+	visit.CalledAt = visitBeforeUpdate.CalledAt
+	visit.RecalledAt = visitBeforeUpdate.RecalledAt
+
+	// queue positions are only updated from the practitioner software
+	visit.QueuePosition = visitBeforeUpdate.QueuePosition
+	visit.EstimatedCallAt = visitBeforeUpdate.EstimatedCallAt
+
+	// pager times are only recorded by changes of the pager
+	visit.PagerAssignedAt = visitBeforeUpdate.PagerAssignedAt
+	visit.PagerReturnedAt = visitBeforeUpdate.PagerReturnedAt
+	visit.ArrivedAt = visitBeforeUpdate.ArrivedAt
+	if visit.PagerID != visitBeforeUpdate.PagerID {
+		now := time.Now()
+		if visit.PagerID != 0 {
+			visit.PagerAssignedAt = &now
+			visit.PagerReturnedAt = nil
+		} else {
+			visit.PagerReturnedAt = &now
+
+			// a called patient returns the pager on arrival
+			if visit.FirstCalledAt != nil && visit.ArrivedAt == nil {
+				visit.ArrivedAt = &now
+			}
+		}
+	}
+
+	// the retention period of a visit starts when finished
+	visit.FinishedAt = visitBeforeUpdate.FinishedAt
+	if visit.Status != model.VisitStatusFinished {
+		visit.FinishedAt = nil
+	} else if visitBeforeUpdate.Status != model.VisitStatusFinished {
+		now := time.Now()
+		visit.FinishedAt = &now
+	}
+
+	if err := service.validateVisit(tx, visit); err != nil {
+		tx.Rollback()
+		return errors.WithStack(err)
+	}
+
+	if visit.Active {
+		if err := service.markVisitsInactiveFromClient(tx, visit.ClientID); err != nil {
+			tx.Rollback()
+			return errors.WithStack(err)
+		}
+	}
+
+	err = tx.UpdateVisit(visit)
+	if err != nil {
+		tx.Rollback()
+
+		if isEntryNotValidErr(err) {
+			return &modelValidationErr{err.Error()}
+		}
+
+		if isEntryNotExistErr(err) {
+			return &modelNotExistErr{"visit doesn't exist"}
+		}
+
+		return errors.Wrap(err, "update visit failed")
+	}
+
+	if err := service.closeInactiveVisitsWithoutPagerFromClient(tx, visit.ClientID); err != nil {
+		tx.Rollback()
+		return errors.WithStack(err)
+	}
+
+	// status changed from another state to VisitStatusCall
+	if visit.Status == model.VisitStatusCall && visit.Status != visitBeforeUpdate.Status {
+		service.logger().Debug().
+			Uint("pager", visit.PagerID).
+			Msg("pager gets called")
+
+		if err := service.callVisit(tx, visit); err != nil {
+			tx.Rollback()
+			return errors.Wrap(err, "call visit failed")
+		}
+	}
+
+	tx.notifyUpdatedVisit(visit)
+
+	return tx.Commit()
+}
+
+// CloseVisit removes an open visit from the queue, it's kept for the patient's history
+func (service *defaultService) CloseVisit(visit *model.Visit) (err error) {
+	service, span := service.trace("CloseVisit")
+	defer func() { tracing.End(span, err) }()
+
+	if visit.PagerID != 0 {
+		return &invalidArgumentErr{"pagerId: cannot be set"}
+	}
+
+	if visit.ClosedAt != nil {
+		return &modelNotExistErr{"visit doesn't exist"}
+	}
+
+	tx, err := service.begin()
+	if err != nil {
+		return errors.Wrap(err, "create transaction failed")
+	}
+
+	if err := service.closeVisit(tx, visit); err != nil {
+		tx.Rollback()
+		return errors.WithStack(err)
+	}
+
+	return tx.Commit()
+}
+
+// CallVisit calls the pager of a visit
+func (service *defaultService) CallVisit(visit *model.Visit) (err error) {
+	service, span := service.trace("CallVisit")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := service.begin()
+	if err != nil {
+		return errors.Wrap(err, "create transaction failed")
+	}
+
+	if err := service.callVisit(tx, visit); err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "call visit failed")
+	}
+
+	tx.notifyUpdatedVisit(visit)
+
+	return tx.Commit()
+}
+
+func (service *defaultService) callVisit(tx Tx, visit *model.Visit) error {
+	pager, err := tx.GetPager(visit.PagerID)
+	if err != nil {
+		return errors.Wrap(err, "get pager failed")
+	}
+
+	url, user, password, port := config.PagerGateway()
+	client := easycall.NewClient(url, user, password)
+
+	_, span := tracing.Start(service.context(), "easycall.send",
+		trace.Int64Attribute("visit.id", int64(visit.ID)),
+		trace.Int64Attribute("pager.easycall_id", int64(pager.EasyCallID)),
+	)
+	err = client.Send(&easycall.SendOptions{
+		Receiver: int(pager.EasyCallID),
+		Message:  "",
+		Port:     port,
+	})
+	tracing.End(span, err)
+	if err != nil {
+		metrics.PagerCalls.WithLabelValues("failure").Inc()
+		return &externalServiceErr{"pager call failed"}
+	}
+	metrics.PagerCalls.WithLabelValues("success").Inc()
+
+	now := time.Now()
+	visit.Status = model.VisitStatusCalled
+	visit.CalledAt = &now
+	if visit.FirstCalledAt == nil {
+		visit.FirstCalledAt = &now
+	} else {
+		visit.RecalledAt = &now
+	}
+
+	err = tx.UpdateVisit(visit)
+	if err != nil {
+		return errors.Wrap(err, "update visit failed")
+	}
+
+	return nil
+}
+
+func (service *defaultService) closeVisit(tx *transaction, visit *model.Visit) error {
+	now := time.Now()
+	visit.ClosedAt = &now
+	visit.Active = false
+
+	err := tx.UpdateVisit(visit)
+	if err != nil {
+		if isEntryNotExistErr(err) {
+			return &modelNotExistErr{"visit doesn't exist"}
+		}
+
+		return errors.Wrap(err, "close visit failed")
+	}
+
+	tx.notifyDeletedVisit(visit)
+
+	return nil
+}
+
+func (service *defaultService) validateVisit(tx Tx, visit *model.Visit) error {
+	var pagers []*model.Pager
+
+	if visit.PagerID != 0 {
+		// load pagers to validate if pager sent with request is valid
+		var err error
+		pagers, err = tx.GetUnassignedPagers()
+		if err != nil {
+			return errors.Wrap(err, "get all pagers failed")
+		}
+	} else if visit.Status == model.VisitStatusCall {
+		return &modelValidationErr{"Status: \"call\" can only be set if PagerID is set."}
+	}
+
+	if visit.ID != 0 {
+		v, err := tx.GetVisit(visit.ID)
+		if err != nil {
+			return errors.Wrap(err, "get visit failed")
+		}
+
+		// visit exists so it is an update
+		// pager hasn't changed so it is also valid
+		if v != nil && v.PagerID == visit.PagerID {
+			pagers = append(pagers, &model.Pager{ID: visit.PagerID})
+		}
+	}
+
+	// validate visit
+	if err := visit.Validate(pagers); err != nil {
+		if model.IsValidationErr(err) {
+			return &modelValidationErr{err.Error()}
+		}
+
+		return errors.Wrap(err, "validate visit failed")
+	}
+
+	return nil
+}
+
+func (service *defaultService) markVisitsInactiveFromClient(tx *transaction, clientID uint) error {
+	visits, err := tx.GetVisitsByClient(clientID, true)
+	if err != nil {
+		return errors.Wrap(err, "get all visits by client failed")
+	}
+
+	if err := tx.MarkVisitsInactiveByClient(clientID); err != nil {
+		return errors.Wrap(err, "mark all visits as inactive failed")
+	}
+
+	for _, visit := range visits {
+		visit.Active = false
+		tx.notifyUpdatedVisit(visit)
+	}
+
+	return nil
+}
+
+func (service *defaultService) closeInactiveVisitsWithoutPagerFromClient(tx *transaction, clientID uint) error {
+	visits, err := tx.GetVisitsByClient(clientID, false, false)
+	if err != nil {
+		return errors.Wrap(err, "get all visits by client failed")
+	}
+
+	if err := tx.CloseVisitsByClient(clientID, false, false); err != nil {
+		return errors.Wrap(err, "close all inactive visits without pager failed")
+	}
+
+	for _, visit := range visits {
+		tx.notifyDeletedVisit(visit)
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pagient/pagient-server/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDefaultService_ShowAverageWaitTime(t *testing.T) {
+	now := time.Now()
+	calledAt := func(d time.Duration) *time.Time {
+		at := now.Add(-time.Hour).Add(d)
+		return &at
+	}
+
+	tests := map[string]struct {
+		visits  []*model.Visit
+		average time.Duration
+	}{
+		"without called visits": {
+			visits:  nil,
+			average: 0,
+		},
+		"average of called visits": {
+			visits: []*model.Visit{
+				{ID: 1, CreatedAt: now.Add(-time.Hour), FirstCalledAt: calledAt(10 * time.Minute)},
+				{ID: 2, CreatedAt: now.Add(-time.Hour), FirstCalledAt: calledAt(20 * time.Minute)},
+			},
+			average: 15 * time.Minute,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		tx := &MockTx{}
+		db := &MockDB{}
+
+		db.On("Begin").Return(tx, nil).Once()
+		tx.On("GetVisitsFirstCalledAfter", mock.AnythingOfType("time.Time")).Return(test.visits, nil).Once()
+		tx.On("Commit").Return(nil).Once()
+
+		s := NewService(db, nil)
+		average, err := s.ShowAverageWaitTime()
+
+		assert.NoError(t, err)
+		assert.Equal(t, test.average, average)
+
+		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
+	}
+}
+
+func TestDefaultService_CheckIn(t *testing.T) {
+	tests := map[string]struct {
+		openVisit *model.Visit
+		closed    bool
+		existErr  bool
+	}{
+		"new patient": {},
+		"finished visit gets closed": {
+			openVisit: &model.Visit{ID: 1, PatientID: 1, Status: model.VisitStatusFinished},
+			closed:    true,
+		},
+		"finished visit holding a pager rejects check-in": {
+			openVisit: &model.Visit{ID: 1, PatientID: 1, PagerID: 1, Status: model.VisitStatusFinished},
+			existErr:  true,
+		},
+		"pending visit rejects check-in": {
+			openVisit: &model.Visit{ID: 1, PatientID: 1, Status: model.VisitStatusPending},
+			existErr:  true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		var openVisits []*model.Visit
+		if test.openVisit != nil {
+			openVisits = append(openVisits, test.openVisit)
+		}
+
+		tx := &MockTx{}
+		tx.On("GetVisitsByClient", uint(1), false, false).Return(nil, nil).Once()
+		tx.On("CloseVisitsByClient", uint(1), false, false).Return(nil).Once()
+		tx.On("GetPatient", uint(1)).Return(nil, nil).Once()
+		tx.On("AddPatient", mock.AnythingOfType("*model.Patient")).Return(nil).Once()
+		tx.On("GetVisitsByPatient", uint(1)).Return(openVisits, nil).Once()
+
+		if test.closed {
+			tx.On("UpdateVisit", mock.MatchedBy(func(visit *model.Visit) bool {
+				return visit.ID == 1 && visit.ClosedAt != nil
+			})).Return(nil).Once()
+		}
+
+		if test.existErr {
+			tx.On("Rollback").Return(nil).Once()
+		} else {
+			tx.On("NextVisitTicket", mock.AnythingOfType("time.Time")).Return(uint(7), nil).Once()
+			tx.On("AddVisit", mock.MatchedBy(func(visit *model.Visit) bool {
+				return visit.Ticket == 7
+			})).Return(nil).Once()
+			tx.On("Commit").Return(nil).Once()
+		}
+
+		db := &MockDB{}
+		db.On("Begin").Return(tx, nil).Once()
+
+		s := NewService(db, nil)
+		err := s.CheckIn(&model.Visit{
+			ClientID: 1,
+			Patient:  model.Patient{ID: 1, SocialSecurityNo: "1234010180", Name: "Jane Doe"},
+		})
+
+		assert.Equal(t, test.existErr, IsModelExistErr(err))
+		if !test.existErr {
+			assert.NoError(t, err)
+		}
+
+		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
+	}
+}
@@ -0,0 +1,121 @@
+package tracing
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/pagient/pagient-server/internal/config"
+
+	"contrib.go.opencensus.io/exporter/ocagent"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+	"google.golang.org/grpc/credentials"
+)
+
+var (
+	exporter  trace.Exporter
+	collector *ocagent.Exporter
+)
+
+// Init registers the exporter of spans configured, without tracing enabled spans are not recorded at all
+func Init() error {
+	if !config.Tracing.Enabled {
+		trace.ApplyConfig(trace.Config{DefaultSampler: trace.NeverSample()})
+		return nil
+	}
+
+	var err error
+	exporter, err = newExporter()
+	if err != nil {
+		return errors.Wrap(err, "create span exporter failed")
+	}
+
+	trace.RegisterExporter(exporter)
+	// traces of sampled callers are kept regardless of the ratio
+	trace.ApplyConfig(trace.Config{DefaultSampler: trace.ProbabilitySampler(config.Tracing.SampleRatio)})
+
+	return nil
+}
+
+func newExporter() (trace.Exporter, error) {
+	switch config.Tracing.Exporter {
+	case "stdout":
+		return &stdoutExporter{encoder: json.NewEncoder(os.Stdout)}, nil
+	case "otlp":
+		opts := []ocagent.ExporterOption{
+			ocagent.WithAddress(config.Tracing.Endpoint),
+			ocagent.WithServiceName(config.Tracing.ServiceName),
+		}
+		if config.Tracing.Insecure {
+			opts = append(opts, ocagent.WithInsecure())
+		} else {
+			opts = append(opts, ocagent.WithTLSCredentials(credentials.NewTLS(&tls.Config{})))
+		}
+
+		// the collector is connected in the background, so an unreachable collector doesn't prevent the start
+		var err error
+		collector, err = ocagent.NewExporter(opts...)
+		if err != nil {
+			return nil, errors.Wrap(err, "create collector exporter failed")
+		}
+
+		return collector, nil
+	default:
+		return nil, errors.Errorf("unknown span exporter %s", config.Tracing.Exporter)
+	}
+}
+
+// Close flushes the pending spans and stops exporting
+func Close() error {
+	if exporter == nil {
+		return nil
+	}
+
+	trace.UnregisterExporter(exporter)
+	if collector == nil {
+		return nil
+	}
+
+	return errors.Wrap(collector.Stop(), "stop collector exporter failed")
+}
+
+// Start starts a span as child of the span in ctx, the returned context holds the new span
+func Start(ctx context.Context, name string, attrs ...trace.Attribute) (context.Context, *trace.Span) {
+	ctx, span := trace.StartSpan(ctx, name)
+	span.AddAttributes(attrs...)
+
+	return ctx, span
+}
+
+// StartRemote starts a span continuing the trace of a caller, the returned context holds the new span
+func StartRemote(ctx context.Context, name string, parent trace.SpanContext, attrs ...trace.Attribute) (context.Context, *trace.Span) {
+	ctx, span := trace.StartSpanWithRemoteParent(ctx, name, parent)
+	span.AddAttributes(attrs...)
+
+	return ctx, span
+}
+
+// End marks the span as failed by err if any and ends it
+func End(span *trace.Span, err error) {
+	if err != nil {
+		span.SetStatus(trace.Status{Code: trace.StatusCodeUnknown, Message: err.Error()})
+	}
+
+	span.End()
+}
+
+// stdoutExporter prints the spans as json for testing
+type stdoutExporter struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+func (e *stdoutExporter) ExportSpan(span *trace.SpanData) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.encoder.Encode(span)
+}
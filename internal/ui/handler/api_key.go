@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/service"
+	"github.com/pagient/pagient-server/internal/ui/renderer"
+	"github.com/pagient/pagient-server/internal/ui/router/context"
+	"github.com/pagient/pagient-server/internal/ui/websocket"
+
+	"github.com/go-chi/render"
+)
+
+// GetAPIKeys returns all api keys of the user
+func GetAPIKeys(apiKeyService service.APIKeyService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		user := req.Context().Value(context.UserKey).(*model.User)
+
+		keys, err := context.Scoped(apiKeyService, req).ListAPIKeys(user.Username)
+		if err != nil {
+			render.Render(w, req, renderer.ErrInternalServer(err))
+			return
+		}
+
+		render.RenderList(w, req, renderer.NewAPIKeyListResponse(keys))
+	}
+}
+
+// AddAPIKey creates an api key for the user, the key is only returned once
+func AddAPIKey(apiKeyService service.APIKeyService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		keyReq := &renderer.APIKeyRequest{}
+		if err := render.Bind(req, keyReq); err != nil {
+			render.Render(w, req, renderer.ErrBadRequest(err))
+			return
+		}
+
+		user := req.Context().Value(context.UserKey).(*model.User)
+
+		key := keyReq.GetModel()
+		key.User = *user
+		key.UserID = user.ID
+
+		if err := context.Scoped(apiKeyService, req).CreateAPIKey(key); err != nil {
+			if service.IsModelValidationErr(err) {
+				render.Render(w, req, renderer.ErrValidation(err))
+				return
+			}
+
+			render.Render(w, req, renderer.ErrInternalServer(err))
+			return
+		}
+
+		render.Status(req, http.StatusCreated)
+		render.Render(w, req, renderer.NewAPIKeyResponse(key))
+	}
+}
+
+// DeleteAPIKey revokes an api key and disconnects the displays using it
+func DeleteAPIKey(apiKeyService service.APIKeyService, wsHub *websocket.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		key := req.Context().Value(context.ManagedAPIKeyKey).(*model.APIKey)
+
+		if err := context.Scoped(apiKeyService, req).DeleteAPIKey(key); err != nil {
+			if service.IsModelNotExistErr(err) {
+				render.Render(w, req, renderer.ErrNotFound)
+				return
+			}
+
+			render.Render(w, req, renderer.ErrInternalServer(err))
+			return
+		}
+
+		wsHub.DisconnectClient(key.Session())
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
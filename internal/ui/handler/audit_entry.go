@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/pagient/pagient-server/internal/service"
+	"github.com/pagient/pagient-server/internal/ui/renderer"
+	"github.com/pagient/pagient-server/internal/ui/router/context"
+
+	"github.com/go-chi/render"
+)
+
+// defaultAuditPeriod limits listed audit entries if no start is requested
+const defaultAuditPeriod = 30 * 24 * time.Hour
+
+// GetAuditEntries lists the audit log, optionally filtered by start time
+func GetAuditEntries(auditService service.AuditService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		since := time.Now().Add(-defaultAuditPeriod)
+		if param := req.URL.Query().Get("since"); param != "" {
+			var err error
+			since, err = time.Parse(time.RFC3339, param)
+			if err != nil {
+				render.Render(w, req, renderer.ErrBadRequest(err))
+				return
+			}
+		}
+
+		entries, err := context.Scoped(auditService, req).ListAuditEntries(since)
+		if err != nil {
+			render.Render(w, req, renderer.ErrInternalServer(err))
+			return
+		}
+
+		render.RenderList(w, req, renderer.NewAuditEntryListResponse(entries))
+	}
+}
@@ -1,68 +1,117 @@
 package handler
 
 import (
-	"github.com/dgrijalva/jwt-go"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/pagient/pagient-server/internal/config"
+	"github.com/pagient/pagient-server/internal/limiter"
 	"github.com/pagient/pagient-server/internal/model"
 	"github.com/pagient/pagient-server/internal/service"
 	"github.com/pagient/pagient-server/internal/ui/renderer"
+	"github.com/pagient/pagient-server/internal/ui/router/context"
 	"github.com/pagient/pagient-server/internal/ui/websocket"
 
+	"github.com/dgrijalva/jwt-go"
 	"github.com/go-chi/jwtauth"
 	"github.com/go-chi/render"
+	"github.com/rs/xid"
+	"github.com/rs/zerolog/hlog"
+)
+
+const (
+	jwtCookie     = "jwt"
+	refreshCookie = "refresh_token"
 )
 
-// CreateToken authenticates a user and creates a jwt token
-func CreateToken(userService service.UserService, tokenService service.TokenService) http.HandlerFunc {
+// CreateToken authenticates a user by password or refresh token and creates a jwt token,
+// password logins are rate limited per username and per ip address
+func CreateToken(userService service.UserService, tokenService service.TokenService, loginLimiter *limiter.Limiter) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		userReq := &renderer.UserRequest{}
-		if err := render.Bind(req, userReq); err != nil {
+		tokenReq := &renderer.TokenRequest{}
+		if err := render.Bind(req, tokenReq); err != nil {
 			render.Render(w, req, renderer.ErrBadRequest(err))
 			return
 		}
 
-		user, valid, err := userService.Login(userReq.Username, userReq.Password)
+		token, err := newToken()
 		if err != nil {
 			render.Render(w, req, renderer.ErrInternalServer(err))
 			return
 		}
-
-		if !valid {
-			render.Render(w, req, renderer.ErrUnauthorized)
+		token.IP = context.RemoteIP(req)
+		token.UserAgent = req.UserAgent()
+
+		switch tokenReq.GrantType {
+		case renderer.GrantTypePassword:
+			// count both before rejecting, so neither limit can be bypassed,
+			// the ip limit is keyed on the peer as proxy headers can be forged
+			peerIP := context.PeerIP(req)
+			ipAllowed := loginLimiter.Allow("ip:" + peerIP)
+			userAllowed := loginLimiter.Allow("user:" + tokenReq.Username)
+			if !ipAllowed || !userAllowed {
+				hlog.FromRequest(req).Warn().
+					Str("user", tokenReq.Username).
+					Str("ip", token.IP).
+					Str("peer", peerIP).
+					Msg("login rate limit exceeded")
+
+				w.Header().Set("Retry-After", strconv.Itoa(int(loginLimiter.Window().Seconds())))
+				render.Render(w, req, renderer.ErrTooManyRequests)
+				return
+			}
+
+			user, valid, err := context.Scoped(userService, req).Login(tokenReq.Username, tokenReq.Password, token.IP)
+			if err != nil {
+				// locked users get the same response as wrong passwords, so usernames can't be probed,
+				// the lockout is recorded as failed login attempt only
+				if service.IsUserLockedErr(err) {
+					render.Render(w, req, renderer.ErrUnauthorized)
+					return
+				}
+
+				render.Render(w, req, renderer.ErrInternalServer(err))
+				return
+			}
+
+			if !valid {
+				render.Render(w, req, renderer.ErrUnauthorized)
+				return
+			}
+
+			loginLimiter.Reset("user:" + tokenReq.Username)
+
+			token.User = *user
+			token.UserID = user.ID
+			if err := context.Scoped(tokenService, req).CreateToken(token); err != nil {
+				render.Render(w, req, renderer.ErrInternalServer(err))
+				return
+			}
+		case renderer.GrantTypeRefreshToken:
+			refresh := tokenReq.RefreshToken
+			if cookie, err := req.Cookie(refreshCookie); refresh == "" && err == nil {
+				refresh = cookie.Value
+			}
+
+			if err := context.Scoped(tokenService, req).RefreshToken(refresh, token); err != nil {
+				if service.IsModelNotExistErr(err) {
+					render.Render(w, req, renderer.ErrUnauthorized)
+					return
+				}
+
+				render.Render(w, req, renderer.ErrInternalServer(err))
+				return
+			}
+		default:
+			render.Render(w, req, renderer.ErrBadRequest(errors.New("unsupported grant_type")))
 			return
 		}
 
-		tokenAuth := jwtauth.New("HS256", []byte(config.General.Secret), nil)
-
-		var claim jwt.MapClaims
-		jwtauth.SetExpiryIn(claim, 12 * time.Hour)
-
-		jwtToken, _, err := tokenAuth.Encode(claim)
-		if err != nil {
-			render.Render(w, req, renderer.ErrInternalServer(err))
-			return
-		}
-
-		token := &model.Token{
-			Raw:  jwtToken.Raw,
-			User: *user,
-		}
-		err = tokenService.CreateToken(token)
-		if err != nil {
-			render.Render(w, req, renderer.ErrInternalServer(err))
-			return
-		}
-
-		http.SetCookie(w, &http.Cookie{
-			Name:     "jwt",
-			Value:    jwtToken.Raw,
-			Path:     "/",
-			Expires:  time.Now().Add(12 * time.Hour),
-			HttpOnly: true,
-		})
+		setTokenCookies(w, token)
 
 		render.Render(w, req, renderer.NewTokenResponse(token))
 	}
@@ -77,53 +126,93 @@ func DeleteToken(tokenService service.TokenService, wsHub *websocket.Hub) http.H
 			return
 		}
 
-		token, err := tokenService.ShowToken(jwtToken.Raw)
+		token, err := context.Scoped(tokenService, req).ShowToken(jwtToken.Raw)
 		if err != nil {
 			render.Render(w, req, renderer.ErrInternalServer(err))
 			return
 		}
 
-		err = tokenService.DeleteToken(token)
+		err = context.Scoped(tokenService, req).DeleteToken(token)
 		if err != nil {
 			render.Render(w, req, renderer.ErrInternalServer(err))
 			return
 		}
 
-		wsHub.DisconnectClient(token.ID)
+		wsHub.DisconnectClient(token.Session())
 
 		http.SetCookie(w, &http.Cookie{
-			Name:     "jwt",
+			Name:     jwtCookie,
 			Value:    "",
 			Path:     "/",
 			Expires:  time.Now(),
 			HttpOnly: true,
 		})
 
+		http.SetCookie(w, &http.Cookie{
+			Name:     refreshCookie,
+			Value:    "",
+			Path:     "/oauth",
+			Expires:  time.Now(),
+			HttpOnly: true,
+		})
+
 		w.WriteHeader(http.StatusNoContent)
 	}
 }
 
-// GetSessions returns all jwt tokens from a user
-func GetSessions(userService service.UserService, tokenService service.TokenService) http.HandlerFunc {
-	return func(w http.ResponseWriter, req *http.Request) {
-		jwtToken, _, err := jwtauth.FromContext(req.Context())
-		if err != nil {
-			render.Render(w, req, renderer.ErrInternalServer(err))
-			return
-		}
+// setTokenCookies stores jwt and refresh token in http only cookies
+func setTokenCookies(w http.ResponseWriter, token *model.Token) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     jwtCookie,
+		Value:    token.Raw,
+		Path:     "/",
+		Expires:  token.ExpiresAt,
+		HttpOnly: true,
+	})
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     refreshCookie,
+		Value:    token.Refresh,
+		Path:     "/oauth",
+		Expires:  token.RefreshExpiresAt,
+		HttpOnly: true,
+	})
+}
 
-		user, err := userService.ShowUserByToken(jwtToken.Raw)
-		if err != nil {
-			render.Render(w, req, renderer.ErrInternalServer(err))
-			return
-		}
+// newToken creates a token with a signed jwt and a random refresh token
+func newToken() (*model.Token, error) {
+	now := time.Now()
+	tokenAuth := jwtauth.New("HS256", []byte(config.General.Secret), nil)
 
-		tokens, err := tokenService.ListTokensByUser(user.Username)
-		if err != nil {
-			render.Render(w, req, renderer.ErrInternalServer(err))
-			return
-		}
+	claims := jwt.MapClaims{"jti": xid.New().String()}
+	jwtauth.SetIssuedNow(claims)
+	jwtauth.SetExpiry(claims, now.Add(config.Auth.AccessTokenLifetime))
+
+	jwtToken, _, err := tokenAuth.Encode(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	refresh, err := randomString()
+	if err != nil {
+		return nil, err
+	}
 
-		render.RenderList(w, req, renderer.NewTokenListResponse(tokens))
+	return &model.Token{
+		LastSeenAt:       now,
+		Raw:              jwtToken.Raw,
+		Refresh:          refresh,
+		ExpiresAt:        now.Add(config.Auth.AccessTokenLifetime),
+		RefreshExpiresAt: now.Add(config.Auth.RefreshTokenLifetime),
+	}, nil
+}
+
+// randomString returns 32 random bytes encoded url safe
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
 	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
 }
@@ -5,6 +5,7 @@ import (
 
 	"github.com/pagient/pagient-server/internal/service"
 	"github.com/pagient/pagient-server/internal/ui/renderer"
+	"github.com/pagient/pagient-server/internal/ui/router/context"
 
 	"github.com/go-chi/render"
 )
@@ -12,7 +13,7 @@ import (
 // GetClients lists all configured clients
 func GetClients(clientService service.ClientService) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		clients, err := clientService.ListClients()
+		clients, err := context.Scoped(clientService, req).ListClients()
 		if err != nil {
 			render.Render(w, req, renderer.ErrInternalServer(err))
 			return
@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/service"
+	"github.com/pagient/pagient-server/internal/ui/renderer"
+	"github.com/pagient/pagient-server/internal/ui/router/context"
+
+	"github.com/go-chi/render"
+)
+
+// GetDisplay lists the called visits shown on waiting-room displays
+func GetDisplay(visitService service.VisitService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		visits, err := context.Scoped(visitService, req).ListPagerVisitsByStatus(model.VisitStatusCall, model.VisitStatusCalled)
+		if err != nil {
+			render.Render(w, req, renderer.ErrInternalServer(err))
+			return
+		}
+
+		render.RenderList(w, req, renderer.NewDisplayListResponse(visits))
+	}
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/pagient/pagient-server/internal/notifier"
+	"github.com/pagient/pagient-server/internal/ui/renderer"
+	"github.com/pagient/pagient-server/internal/ui/router/context"
+
+	"github.com/go-chi/render"
+)
+
+// ServeEvents streams visit notifications as server-sent events,
+// the stream ends with the request timeout and clients are expected to reconnect
+func ServeEvents(stream *notifier.SSE) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			render.Render(w, req, renderer.ErrInternalServer(errors.New("streaming unsupported")))
+			return
+		}
+
+		sub := stream.Subscribe(!context.RevealSocialSecurityNo(req))
+		defer stream.Unsubscribe(sub)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		for {
+			select {
+			case event, ok := <-sub.Events():
+				if !ok {
+					return
+				}
+
+				if _, err := w.Write(event); err != nil {
+					return
+				}
+				flusher.Flush()
+			case <-req.Context().Done():
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/pagient/pagient-server/internal/health"
+	"github.com/pagient/pagient-server/internal/ui/renderer"
+
+	"github.com/go-chi/render"
+)
+
+// GetHealth reports that the process is alive and serving requests
+func GetHealth() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		render.Render(w, req, renderer.NewHealthResponse(&health.Report{Status: health.StatusUp}))
+	}
+}
+
+// GetReadiness checks all dependencies and reports whether the server is able to page patients
+func GetReadiness(checker *health.Checker) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		render.Render(w, req, renderer.NewHealthResponse(checker.Run()))
+	}
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/service"
+	"github.com/pagient/pagient-server/internal/ui/renderer"
+	"github.com/pagient/pagient-server/internal/ui/router/context"
+
+	"github.com/go-chi/render"
+)
+
+// inventoryContentTypes maps the inventory formats to their content type
+var inventoryContentTypes = map[string]string{
+	renderer.InventoryFormatCSV:  "text/csv; charset=utf-8",
+	renderer.InventoryFormatJSON: "application/json; charset=utf-8",
+	renderer.InventoryFormatYAML: "application/x-yaml; charset=utf-8",
+}
+
+// ImportInventory adds the clients, pagers and users of the request body in csv, json or yaml,
+// nothing is added if any row is invalid and the invalid rows are listed
+func ImportInventory(inventoryService service.InventoryService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+
+		format := query.Get("format")
+		if format == "" {
+			format = renderer.InventoryFormatJSON
+		}
+
+		dryRun := false
+		if param := query.Get("dryRun"); param != "" {
+			var err error
+			dryRun, err = strconv.ParseBool(param)
+			if err != nil {
+				render.Render(w, req, renderer.ErrBadRequest(err))
+				return
+			}
+		}
+
+		inventory, importErrs, err := renderer.ReadInventory(req.Body, format, model.InventoryKind(query.Get("kind")))
+		if err != nil {
+			render.Render(w, req, renderer.ErrBadRequest(err))
+			return
+		}
+
+		if len(importErrs) == 0 {
+			importErrs, err = context.Scoped(inventoryService, req).ImportInventory(inventory, dryRun)
+			if err != nil {
+				render.Render(w, req, renderer.ErrInternalServer(err))
+				return
+			}
+		}
+
+		render.Render(w, req, renderer.NewImportResponse(inventory, importErrs, dryRun))
+	}
+}
+
+// ExportInventory writes all clients, pagers and users in csv, json or yaml, without passwords
+func ExportInventory(inventoryService service.InventoryService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+
+		format := query.Get("format")
+		if format == "" {
+			format = renderer.InventoryFormatJSON
+		}
+
+		kind := model.InventoryKind(query.Get("kind"))
+		if err := renderer.ValidInventoryFormat(format, kind); err != nil {
+			render.Render(w, req, renderer.ErrBadRequest(err))
+			return
+		}
+
+		inventory, err := context.Scoped(inventoryService, req).ExportInventory()
+		if err != nil {
+			render.Render(w, req, renderer.ErrInternalServer(err))
+			return
+		}
+
+		name := "inventory"
+		if kind != "" {
+			name = string(kind)
+		}
+
+		w.Header().Set("Content-Type", inventoryContentTypes[format])
+		w.Header().Set("Content-Disposition", "attachment; filename=\""+name+"."+format+"\"")
+		renderer.WriteInventory(w, format, kind, inventory)
+	}
+}
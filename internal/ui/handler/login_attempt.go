@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/pagient/pagient-server/internal/service"
+	"github.com/pagient/pagient-server/internal/ui/renderer"
+	"github.com/pagient/pagient-server/internal/ui/router/context"
+
+	"github.com/go-chi/render"
+)
+
+// defaultLoginAttemptPeriod limits listed login attempts if no start is requested
+const defaultLoginAttemptPeriod = 24 * time.Hour
+
+// GetLoginAttempts lists failed logins, optionally filtered by username and start time
+func GetLoginAttempts(loginAttemptService service.LoginAttemptService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		since := time.Now().Add(-defaultLoginAttemptPeriod)
+		if param := req.URL.Query().Get("since"); param != "" {
+			var err error
+			since, err = time.Parse(time.RFC3339, param)
+			if err != nil {
+				render.Render(w, req, renderer.ErrBadRequest(err))
+				return
+			}
+		}
+
+		attempts, err := context.Scoped(loginAttemptService, req).ListLoginAttempts(req.URL.Query().Get("username"), since)
+		if err != nil {
+			render.Render(w, req, renderer.ErrInternalServer(err))
+			return
+		}
+
+		render.RenderList(w, req, renderer.NewLoginAttemptListResponse(attempts))
+	}
+}
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pagient/pagient-server/internal/config"
+	"github.com/pagient/pagient-server/internal/directory"
+	"github.com/pagient/pagient-server/internal/service"
+	"github.com/pagient/pagient-server/internal/ui/renderer"
+	"github.com/pagient/pagient-server/internal/ui/router/context"
+
+	"github.com/go-chi/render"
+	"github.com/rs/zerolog/hlog"
+)
+
+const (
+	oidcCookie = "oidc"
+	// oidcLoginTimeout limits the time to login at the provider
+	oidcLoginTimeout = 10 * time.Minute
+	// oidcLoginPage of the ui to continue the session started by single sign-on
+	oidcLoginPage = "/#/login?sso=1"
+)
+
+// OIDCLogin redirects to the openid connect provider to login
+func OIDCLogin(provider *directory.OIDC) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		var values [3]string
+		for i := range values {
+			value, err := randomString()
+			if err != nil {
+				render.Render(w, req, renderer.ErrInternalServer(err))
+				return
+			}
+			values[i] = value
+		}
+		state, nonce, verifier := values[0], values[1], values[2]
+
+		url, err := provider.AuthCodeURL(req.Context(), state, nonce, verifier)
+		if err != nil {
+			hlog.FromRequest(req).Error().
+				Err(err).
+				Msg("create openid connect login url failed")
+
+			render.Render(w, req, renderer.ErrGateway(err))
+			return
+		}
+
+		// the browser keeps the login attempt until the provider redirects back
+		http.SetCookie(w, &http.Cookie{
+			Name:     oidcCookie,
+			Value:    strings.Join(values[:], "."),
+			Path:     "/oauth",
+			Expires:  time.Now().Add(oidcLoginTimeout),
+			HttpOnly: true,
+		})
+
+		http.Redirect(w, req, url, http.StatusFound)
+	}
+}
+
+// OIDCCallback completes the login at the openid connect provider and starts a session
+func OIDCCallback(provider *directory.OIDC, userService service.UserService, tokenService service.TokenService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		cookie, err := req.Cookie(oidcCookie)
+		if err != nil {
+			render.Render(w, req, renderer.ErrBadRequest(errors.New("openid connect login not started")))
+			return
+		}
+
+		http.SetCookie(w, &http.Cookie{
+			Name:     oidcCookie,
+			Value:    "",
+			Path:     "/oauth",
+			Expires:  time.Now(),
+			HttpOnly: true,
+		})
+
+		values := strings.Split(cookie.Value, ".")
+		if len(values) != 3 || req.URL.Query().Get("state") != values[0] {
+			render.Render(w, req, renderer.ErrBadRequest(errors.New("openid connect state mismatch")))
+			return
+		}
+
+		if reason := req.URL.Query().Get("error"); reason != "" {
+			hlog.FromRequest(req).Warn().
+				Str("error", reason).
+				Str("description", req.URL.Query().Get("error_description")).
+				Msg("openid connect login failed")
+
+			render.Render(w, req, renderer.ErrUnauthorized)
+			return
+		}
+
+		external, err := provider.Exchange(req.Context(), req.URL.Query().Get("code"), values[1], values[2])
+		if err != nil {
+			hlog.FromRequest(req).Error().
+				Err(err).
+				Msg("exchange openid connect authorization code failed")
+
+			render.Render(w, req, renderer.ErrUnauthorized)
+			return
+		}
+
+		if external == nil {
+			render.Render(w, req, renderer.ErrForbidden)
+			return
+		}
+
+		token, err := newToken()
+		if err != nil {
+			render.Render(w, req, renderer.ErrInternalServer(err))
+			return
+		}
+		token.IP = context.RemoteIP(req)
+		token.UserAgent = req.UserAgent()
+
+		user, err := context.Scoped(userService, req).LoginExternal(external, token.IP)
+		if err != nil {
+			if service.IsUserLockedErr(err) {
+				render.Render(w, req, renderer.ErrLocked)
+				return
+			}
+
+			if service.IsUserNotLinkedErr(err) {
+				render.Render(w, req, renderer.ErrForbidden)
+				return
+			}
+
+			render.Render(w, req, renderer.ErrInternalServer(err))
+			return
+		}
+
+		token.User = *user
+		token.UserID = user.ID
+		if err := context.Scoped(tokenService, req).CreateToken(token); err != nil {
+			render.Render(w, req, renderer.ErrInternalServer(err))
+			return
+		}
+
+		setTokenCookies(w, token)
+
+		http.Redirect(w, req, oidcLoginPage, http.StatusFound)
+	}
+}
+
+// GetProviders lists the available login methods besides username and password
+func GetProviders() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		render.Render(w, req, renderer.NewProvidersResponse(config.OIDC.Enabled))
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/pagient/pagient-server/internal/service"
 	"github.com/pagient/pagient-server/internal/ui/renderer"
+	"github.com/pagient/pagient-server/internal/ui/router/context"
 
 	"github.com/go-chi/render"
 )
@@ -12,7 +13,7 @@ import (
 // GetPagers lists all configured pagers
 func GetPagers(pagerService service.PagerService) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		pagers, err := pagerService.ListPagers()
+		pagers, err := context.Scoped(pagerService, req).ListPagers()
 		if err != nil {
 			render.Render(w, req, renderer.ErrInternalServer(err))
 			return
@@ -15,7 +15,7 @@ import (
 // GetPatients lists all patients
 func GetPatients(patientService service.PatientService) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		patients, err := patientService.ListPatients()
+		patients, err := context.Scoped(patientService, req).ListPatients()
 		if err != nil {
 			render.Render(w, req, renderer.ErrInternalServer(err))
 			return
@@ -25,53 +25,16 @@ func GetPatients(patientService service.PatientService) http.HandlerFunc {
 	}
 }
 
-// AddPatient adds a patient
-func AddPatient(patientService service.PatientService) http.HandlerFunc {
+// AddPatient checks in a patient posted including the state of it's visit
+func AddPatient(visitService service.VisitService) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		patientReq := &renderer.PatientRequest{}
-		if err := render.Bind(req, patientReq); err != nil {
+		checkInReq := &renderer.CheckInRequest{}
+		if err := render.Bind(req, checkInReq); err != nil {
 			render.Render(w, req, renderer.ErrBadRequest(err))
 			return
 		}
 
-		if patientReq.ClientID != 0 {
-			render.Render(w, req, renderer.ErrBadRequest(errors.New("clientId not allowed")))
-			return
-		}
-
-		if patientReq.Status != "" {
-			render.Render(w, req, renderer.ErrBadRequest(errors.New("status not allowed")))
-			return
-		}
-
-		// Set clientID to the client that added the patientReq
-		ctxClient := req.Context().Value(context.ClientKey).(*model.Client)
-		if ctxClient == nil {
-			render.Render(w, req, renderer.ErrUnauthorized)
-			return
-		}
-		patientReq.ClientID = ctxClient.ID
-
-		patient := patientReq.GetModel()
-		err := patientService.CreatePatient(patient)
-		if err != nil {
-			if service.IsModelExistErr(err) {
-				render.Render(w, req, renderer.ErrConflict(err))
-				return
-			}
-
-			if service.IsModelValidationErr(err) {
-				render.Render(w, req, renderer.ErrValidation(err))
-				return
-			}
-
-			// on any other error raise 500 status
-			render.Render(w, req, renderer.ErrInternalServer(err))
-			return
-		}
-
-		render.Status(req, http.StatusCreated)
-		render.Render(w, req, renderer.NewPatientResponse(patient))
+		checkIn(w, req, visitService, checkInReq.GetModel())
 	}
 }
 
@@ -84,7 +47,7 @@ func GetPatient() http.HandlerFunc {
 	}
 }
 
-// UpdatePatient updates a patient by specified id
+// UpdatePatient updates the identity of a patient by specified id
 func UpdatePatient(patientService service.PatientService) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
 		patientReq := &renderer.PatientRequest{}
@@ -94,41 +57,29 @@ func UpdatePatient(patientService service.PatientService) http.HandlerFunc {
 		}
 
 		// prevent ID update
-		// prevent direct ClientID update
 		ctxPatient := req.Context().Value(context.PatientKey).(*model.Patient)
 
 		if patientReq.ID != 0 && patientReq.ID != ctxPatient.ID {
 			render.Render(w, req, renderer.ErrBadRequest(errors.New("id not allowed")))
 			return
 		}
-
-		if patientReq.ClientID != 0 && patientReq.ClientID != ctxPatient.ClientID {
-			render.Render(w, req, renderer.ErrBadRequest(errors.New("clientId not allowed")))
-			return
-		}
-
-		if patientReq.PagerID == 0 && patientReq.Status == string(model.PatientStatusCall) {
-			render.Render(w, req, renderer.ErrBadRequest(errors.New("status \"call\" can only be set if pager is assigned")))
-			return
-		}
-
-		// Set clientID to the client that updated the patient
-		// Update/Keep ClientID of requester's client
-		ctxClient := req.Context().Value(context.ClientKey).(*model.Client)
-		if ctxClient != nil {
-			patientReq.ClientID = ctxClient.ID
-		}
+		patientReq.ID = ctxPatient.ID
 
 		patient := patientReq.GetModel()
-		err := patientService.UpdatePatient(patient)
+		err := context.Scoped(patientService, req).UpdatePatient(patient)
 		if err != nil {
+			if service.IsModelExistErr(err) {
+				render.Render(w, req, renderer.ErrConflict(err))
+				return
+			}
+
 			if service.IsModelValidationErr(err) {
 				render.Render(w, req, renderer.ErrValidation(err))
 				return
 			}
 
-			if service.IsExternalServiceErr(err) {
-				render.Render(w, req, renderer.ErrGateway(err))
+			if service.IsModelNotExistErr(err) {
+				render.Render(w, req, renderer.ErrNotFound)
 				return
 			}
 
@@ -141,12 +92,12 @@ func UpdatePatient(patientService service.PatientService) http.HandlerFunc {
 	}
 }
 
-// DeletePatient deletes a patient by specified id
+// DeletePatient deletes a patient and all of it's visits by specified id
 func DeletePatient(patientService service.PatientService) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
 		ctxPatient := req.Context().Value(context.PatientKey).(*model.Patient)
 
-		if err := patientService.DeletePatient(ctxPatient); err != nil {
+		if err := context.Scoped(patientService, req).DeletePatient(ctxPatient); err != nil {
 			if service.IsInvalidArgumentErr(err) {
 				render.Render(w, req, renderer.ErrBadRequest(err))
 				return
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/service"
+	"github.com/pagient/pagient-server/internal/ui/renderer"
+	"github.com/pagient/pagient-server/internal/ui/router/context"
+
+	"github.com/go-chi/render"
+)
+
+// defaultReportPeriod limits reported visits if no start is requested
+const defaultReportPeriod = 30 * 24 * time.Hour
+
+// GetVisitReport aggregates visits by the requested group, either as json or csv
+func GetVisitReport(reportService service.ReportService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+
+		to := time.Now()
+		if param := query.Get("to"); param != "" {
+			var err error
+			to, err = time.Parse(time.RFC3339, param)
+			if err != nil {
+				render.Render(w, req, renderer.ErrBadRequest(err))
+				return
+			}
+		}
+
+		from := to.Add(-defaultReportPeriod)
+		if param := query.Get("from"); param != "" {
+			var err error
+			from, err = time.Parse(time.RFC3339, param)
+			if err != nil {
+				render.Render(w, req, renderer.ErrBadRequest(err))
+				return
+			}
+		}
+
+		group := model.ReportGroupDay
+		if param := query.Get("group"); param != "" {
+			group = model.ReportGroup(param)
+		}
+
+		format := query.Get("format")
+		if format != "" && format != "json" && format != "csv" {
+			render.Render(w, req, renderer.ErrBadRequest(errors.New("format must be either json or csv")))
+			return
+		}
+
+		rows, err := context.Scoped(reportService, req).ReportVisits(from, to, group)
+		if err != nil {
+			if service.IsInvalidArgumentErr(err) {
+				render.Render(w, req, renderer.ErrBadRequest(err))
+				return
+			}
+
+			render.Render(w, req, renderer.ErrInternalServer(err))
+			return
+		}
+
+		if format == "csv" {
+			w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+			w.Header().Set("Content-Disposition", "attachment; filename=\"report.csv\"")
+			renderer.WriteReportCSV(w, rows)
+			return
+		}
+
+		render.RenderList(w, req, renderer.NewReportListResponse(rows))
+	}
+}
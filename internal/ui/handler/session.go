@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/service"
+	"github.com/pagient/pagient-server/internal/ui/renderer"
+	"github.com/pagient/pagient-server/internal/ui/router/context"
+	"github.com/pagient/pagient-server/internal/ui/websocket"
+
+	"github.com/go-chi/jwtauth"
+	"github.com/go-chi/render"
+)
+
+// GetSessions returns all sessions of a user
+func GetSessions(tokenService service.TokenService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		owner := context.SessionOwner(req.Context())
+
+		current, err := currentToken(req, tokenService)
+		if err != nil {
+			render.Render(w, req, renderer.ErrInternalServer(err))
+			return
+		}
+
+		tokens, err := context.Scoped(tokenService, req).ListTokensByUser(owner.Username)
+		if err != nil {
+			render.Render(w, req, renderer.ErrInternalServer(err))
+			return
+		}
+
+		render.RenderList(w, req, renderer.NewSessionListResponse(tokens, current.Session()))
+	}
+}
+
+// DeleteSession revokes a session of a user and disconnects it's websockets
+func DeleteSession(tokenService service.TokenService, wsHub *websocket.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		token := req.Context().Value(context.SessionKey).(*model.Token)
+
+		if err := context.Scoped(tokenService, req).DeleteToken(token); err != nil {
+			if service.IsModelNotExistErr(err) {
+				render.Render(w, req, renderer.ErrNotFound)
+				return
+			}
+
+			render.Render(w, req, renderer.ErrInternalServer(err))
+			return
+		}
+
+		wsHub.DisconnectClient(token.Session())
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// DeleteSessions revokes all sessions of a user except the one of the request
+// and disconnects their websockets
+func DeleteSessions(tokenService service.TokenService, wsHub *websocket.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		owner := context.SessionOwner(req.Context())
+
+		current, err := currentToken(req, tokenService)
+		if err != nil {
+			render.Render(w, req, renderer.ErrInternalServer(err))
+			return
+		}
+
+		tokens, err := context.Scoped(tokenService, req).DeleteTokensByUser(owner.Username, current.Session())
+		if err != nil {
+			render.Render(w, req, renderer.ErrInternalServer(err))
+			return
+		}
+
+		for _, token := range tokens {
+			wsHub.DisconnectClient(token.Session())
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// currentToken returns the token the request has been authenticated with
+func currentToken(req *http.Request, tokenService service.TokenService) (*model.Token, error) {
+	jwtToken, _, err := jwtauth.FromContext(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := context.Scoped(tokenService, req).ShowToken(jwtToken.Raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if token == nil {
+		return &model.Token{}, nil
+	}
+
+	return token, nil
+}
@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/service"
+	"github.com/pagient/pagient-server/internal/ui/renderer"
+	"github.com/pagient/pagient-server/internal/ui/router/context"
+
+	"github.com/go-chi/render"
+)
+
+// GetVisits lists all open visits
+func GetVisits(visitService service.VisitService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		visits, err := context.Scoped(visitService, req).ListVisits()
+		if err != nil {
+			render.Render(w, req, renderer.ErrInternalServer(err))
+			return
+		}
+
+		average, err := context.Scoped(visitService, req).ShowAverageWaitTime()
+		if err != nil {
+			render.Render(w, req, renderer.ErrInternalServer(err))
+			return
+		}
+		req = renderer.WithAverageWaitTime(req, average)
+
+		render.RenderList(w, req, renderer.NewVisitListResponse(visits))
+	}
+}
+
+// GetWaitTime returns the average wait time of recently called patients
+func GetWaitTime(visitService service.VisitService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		average, err := context.Scoped(visitService, req).ShowAverageWaitTime()
+		if err != nil {
+			render.Render(w, req, renderer.ErrInternalServer(err))
+			return
+		}
+
+		render.Render(w, req, renderer.NewWaitTimeResponse(average))
+	}
+}
+
+// AddVisit checks in a patient
+func AddVisit(visitService service.VisitService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		visitReq := &renderer.VisitRequest{}
+		if err := render.Bind(req, visitReq); err != nil {
+			render.Render(w, req, renderer.ErrBadRequest(err))
+			return
+		}
+
+		if visitReq.ID != 0 {
+			render.Render(w, req, renderer.ErrBadRequest(errors.New("id not allowed")))
+			return
+		}
+
+		if visitReq.Patient == nil {
+			render.Render(w, req, renderer.ErrBadRequest(errors.New("patient missing")))
+			return
+		}
+
+		checkIn(w, req, visitService, visitReq.GetModel())
+	}
+}
+
+// GetVisit returns the visit by specified id
+func GetVisit(visitService service.VisitService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		ctxVisit := req.Context().Value(context.VisitKey).(*model.Visit)
+
+		average, err := context.Scoped(visitService, req).ShowAverageWaitTime()
+		if err != nil {
+			render.Render(w, req, renderer.ErrInternalServer(err))
+			return
+		}
+		req = renderer.WithAverageWaitTime(req, average)
+
+		render.Render(w, req, renderer.NewVisitResponse(ctxVisit))
+	}
+}
+
+// UpdateVisit updates a visit by specified id
+func UpdateVisit(visitService service.VisitService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		visitReq := &renderer.VisitRequest{}
+		if err := render.Bind(req, visitReq); err != nil {
+			render.Render(w, req, renderer.ErrBadRequest(err))
+			return
+		}
+
+		// prevent ID update
+		// prevent direct ClientID update
+		ctxVisit := req.Context().Value(context.VisitKey).(*model.Visit)
+
+		if visitReq.ID != 0 && visitReq.ID != ctxVisit.ID {
+			render.Render(w, req, renderer.ErrBadRequest(errors.New("id not allowed")))
+			return
+		}
+		visitReq.ID = ctxVisit.ID
+
+		if visitReq.Patient != nil && visitReq.Patient.ID != ctxVisit.PatientID {
+			render.Render(w, req, renderer.ErrBadRequest(errors.New("patient not allowed")))
+			return
+		}
+
+		if visitReq.ClientID != 0 && visitReq.ClientID != ctxVisit.ClientID {
+			render.Render(w, req, renderer.ErrBadRequest(errors.New("clientId not allowed")))
+			return
+		}
+
+		if visitReq.PagerID == 0 && visitReq.Status == string(model.VisitStatusCall) {
+			render.Render(w, req, renderer.ErrBadRequest(errors.New("status \"call\" can only be set if pager is assigned")))
+			return
+		}
+
+		// Set clientID to the client that updated the visit
+		// Update/Keep ClientID of requester's client
+		visitReq.ClientID = ctxVisit.ClientID
+		ctxClient := req.Context().Value(context.ClientKey).(*model.Client)
+		if ctxClient != nil {
+			visitReq.ClientID = ctxClient.ID
+		}
+
+		visit := visitReq.GetModel()
+		err := context.Scoped(visitService, req).UpdateVisit(visit)
+		if err != nil {
+			if service.IsModelValidationErr(err) {
+				render.Render(w, req, renderer.ErrValidation(err))
+				return
+			}
+
+			if service.IsModelNotExistErr(err) {
+				render.Render(w, req, renderer.ErrNotFound)
+				return
+			}
+
+			if service.IsExternalServiceErr(err) {
+				render.Render(w, req, renderer.ErrGateway(err))
+				return
+			}
+
+			// on any other error raise 500 status
+			render.Render(w, req, renderer.ErrInternalServer(err))
+			return
+		}
+
+		render.Render(w, req, renderer.NewVisitResponse(visit))
+	}
+}
+
+// CloseVisit removes a visit by specified id from the queue
+func CloseVisit(visitService service.VisitService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		ctxVisit := req.Context().Value(context.VisitKey).(*model.Visit)
+
+		if err := context.Scoped(visitService, req).CloseVisit(ctxVisit); err != nil {
+			if service.IsInvalidArgumentErr(err) {
+				render.Render(w, req, renderer.ErrBadRequest(err))
+				return
+			}
+
+			if service.IsModelNotExistErr(err) {
+				render.Render(w, req, renderer.ErrNotFound)
+				return
+			}
+
+			render.Render(w, req, renderer.ErrInternalServer(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// CallVisit calls the pager assigned to a visit by specified id
+func CallVisit(visitService service.VisitService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		ctxVisit := req.Context().Value(context.VisitKey).(*model.Visit)
+
+		if ctxVisit.PagerID == 0 {
+			render.Render(w, req, renderer.ErrBadRequest(errors.New("patient can only be called if pager is assigned")))
+			return
+		}
+
+		visit := *ctxVisit
+		visit.Status = model.VisitStatusCall
+
+		if err := context.Scoped(visitService, req).UpdateVisit(&visit); err != nil {
+			if service.IsModelValidationErr(err) {
+				render.Render(w, req, renderer.ErrValidation(err))
+				return
+			}
+
+			if service.IsExternalServiceErr(err) {
+				render.Render(w, req, renderer.ErrGateway(err))
+				return
+			}
+
+			render.Render(w, req, renderer.ErrInternalServer(err))
+			return
+		}
+
+		render.Render(w, req, renderer.NewVisitResponse(&visit))
+	}
+}
+
+// checkIn adds the visit for the requester's client
+func checkIn(w http.ResponseWriter, req *http.Request, visitService service.VisitService, visit *model.Visit) {
+	if visit.ClientID != 0 {
+		render.Render(w, req, renderer.ErrBadRequest(errors.New("clientId not allowed")))
+		return
+	}
+
+	if visit.Status != "" {
+		render.Render(w, req, renderer.ErrBadRequest(errors.New("status not allowed")))
+		return
+	}
+
+	// Set clientID to the client that checked in the patient
+	ctxClient := req.Context().Value(context.ClientKey).(*model.Client)
+	if ctxClient == nil {
+		render.Render(w, req, renderer.ErrUnauthorized)
+		return
+	}
+	visit.ClientID = ctxClient.ID
+
+	err := context.Scoped(visitService, req).CheckIn(visit)
+	if err != nil {
+		if service.IsModelExistErr(err) {
+			render.Render(w, req, renderer.ErrConflict(err))
+			return
+		}
+
+		if service.IsModelValidationErr(err) {
+			render.Render(w, req, renderer.ErrValidation(err))
+			return
+		}
+
+		if service.IsInvalidArgumentErr(err) {
+			render.Render(w, req, renderer.ErrBadRequest(err))
+			return
+		}
+
+		// on any other error raise 500 status
+		render.Render(w, req, renderer.ErrInternalServer(err))
+		return
+	}
+
+	render.Status(req, http.StatusCreated)
+	render.Render(w, req, renderer.NewVisitResponse(visit))
+}
@@ -7,36 +7,23 @@ import (
 	"github.com/pagient/pagient-server/internal/config"
 	"github.com/pagient/pagient-server/internal/service"
 	"github.com/pagient/pagient-server/internal/ui/renderer"
+	"github.com/pagient/pagient-server/internal/ui/router/context"
 	"github.com/pagient/pagient-server/internal/ui/websocket"
 
 	"github.com/go-chi/jwtauth"
 	"github.com/go-chi/render"
 	ws "github.com/gorilla/websocket"
-	"github.com/rs/zerolog/log"
+	"github.com/rs/zerolog/hlog"
 )
 
 // ServeWebsocket establishes the websocket connection per client
 func ServeWebsocket(tokenService service.TokenService, wsHub *websocket.Hub) http.HandlerFunc {
-	wsUpgrader := ws.Upgrader{
-		ReadBufferSize:  1024,
-		WriteBufferSize: 1024,
-		CheckOrigin: func(req *http.Request) bool {
-			hostURL, err := url.Parse(config.Server.Host)
-			if err != nil {
-				return false
-			}
-
-			if hostURL.String() == req.Header.Get("Origin") && hostURL.Host == req.Host {
-				return true
-			}
-			return false
-		},
-	}
+	wsUpgrader := newUpgrader()
 
 	return func(w http.ResponseWriter, req *http.Request) {
 		conn, err := wsUpgrader.Upgrade(w, req, nil)
 		if err != nil {
-			log.Error().
+			hlog.FromRequest(req).Error().
 				Err(err).
 				Msg("websocket connection could not be established")
 
@@ -50,13 +37,13 @@ func ServeWebsocket(tokenService service.TokenService, wsHub *websocket.Hub) htt
 			return
 		}
 
-		token, err := tokenService.ShowToken(jwtToken.Raw)
+		token, err := context.Scoped(tokenService, req).ShowToken(jwtToken.Raw)
 		if err != nil {
 			render.Render(w, req, renderer.ErrInternalServer(err))
 			return
 		}
 
-		client := websocket.NewClient(token.ID, wsHub, conn)
+		client := websocket.NewClient(token.Session(), !context.RevealSocialSecurityNo(req), wsHub, conn)
 		wsHub.Register <- client
 
 		// Allow collection of memory referenced by the caller by doing all work in
@@ -64,3 +51,64 @@ func ServeWebsocket(tokenService service.TokenService, wsHub *websocket.Hub) htt
 		go client.WritePump()
 	}
 }
+
+// ServeDisplayWebsocket establishes the websocket connection per waiting-room display,
+// displays authenticate by a display scoped api key or the token of a user
+func ServeDisplayWebsocket(tokenService service.TokenService, wsHub *websocket.Hub) http.HandlerFunc {
+	wsUpgrader := newUpgrader()
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		var session string
+		if key := context.APIKey(req); key != nil {
+			session = key.Session()
+		} else {
+			jwtToken, _, err := jwtauth.FromContext(req.Context())
+			if err != nil {
+				render.Render(w, req, renderer.ErrInternalServer(err))
+				return
+			}
+
+			token, err := context.Scoped(tokenService, req).ShowToken(jwtToken.Raw)
+			if err != nil {
+				render.Render(w, req, renderer.ErrInternalServer(err))
+				return
+			}
+
+			session = token.Session()
+		}
+
+		conn, err := wsUpgrader.Upgrade(w, req, nil)
+		if err != nil {
+			hlog.FromRequest(req).Error().
+				Err(err).
+				Msg("websocket connection could not be established")
+
+			render.Render(w, req, renderer.ErrInternalServer(err))
+			return
+		}
+
+		client := websocket.NewDisplayClient(session, wsHub, conn)
+		wsHub.Register <- client
+
+		go client.WritePump()
+	}
+}
+
+// newUpgrader returns a websocket upgrader only accepting connections from the configured host
+func newUpgrader() ws.Upgrader {
+	return ws.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		CheckOrigin: func(req *http.Request) bool {
+			hostURL, err := url.Parse(config.Server.Host)
+			if err != nil {
+				return false
+			}
+
+			if hostURL.String() == req.Header.Get("Origin") && hostURL.Host == req.Host {
+				return true
+			}
+			return false
+		},
+	}
+}
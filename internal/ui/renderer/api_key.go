@@ -0,0 +1,89 @@
+package renderer
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/pagient/pagient-server/internal/model"
+
+	"github.com/go-chi/render"
+)
+
+// APIKeyRequest is the request payload to create an api key
+type APIKeyRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+// Bind postprocesses the decoding of the request body
+func (kr *APIKeyRequest) Bind(r *http.Request) error {
+	return nil
+}
+
+// GetModel returns an APIKey model
+func (kr *APIKeyRequest) GetModel() *model.APIKey {
+	scopes := make([]model.APIKeyScope, len(kr.Scopes))
+	for i, scope := range kr.Scopes {
+		scopes[i] = model.APIKeyScope(scope)
+	}
+
+	key := &model.APIKey{
+		Name:      kr.Name,
+		ExpiresAt: kr.ExpiresAt,
+	}
+	key.SetScopes(scopes)
+
+	return key
+}
+
+// APIKeyResponse is the response payload for the api key data model
+type APIKeyResponse struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	Username   string     `json:"username"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt time.Time  `json:"lastUsedAt"`
+	ExpiresAt  *time.Time `json:"expiresAt"`
+	// Key is only returned once on creation
+	Key string `json:"key,omitempty"`
+}
+
+// NewAPIKeyResponse creates a new api key response from api key model
+func NewAPIKeyResponse(key *model.APIKey) *APIKeyResponse {
+	scopes := []string{}
+	for _, scope := range key.ScopeList() {
+		scopes = append(scopes, string(scope))
+	}
+
+	return &APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     scopes,
+		Username:   key.User.Username,
+		CreatedAt:  key.CreatedAt,
+		LastUsedAt: key.LastUsedAt,
+		ExpiresAt:  key.ExpiresAt,
+		Key:        key.Key,
+	}
+}
+
+// Render preprocesses the response before marshalling
+func (kr *APIKeyResponse) Render(w http.ResponseWriter, req *http.Request) error {
+	return nil
+}
+
+// APIKeyListResponse is the list response payload for the api key data model
+type APIKeyListResponse []*APIKeyResponse
+
+// NewAPIKeyListResponse creates a new api key list response from multiple api key models
+func NewAPIKeyListResponse(keys []*model.APIKey) []render.Renderer {
+	list := make([]render.Renderer, len(keys))
+	for i, key := range keys {
+		list[i] = NewAPIKeyResponse(key)
+	}
+	return list
+}
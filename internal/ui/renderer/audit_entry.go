@@ -0,0 +1,47 @@
+package renderer
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/pagient/pagient-server/internal/model"
+
+	"github.com/go-chi/render"
+)
+
+// AuditEntryResponse is the response payload for the audit entry data model
+type AuditEntryResponse struct {
+	ID        uint      `json:"id"`
+	Actor     string    `json:"actor"`
+	Action    string    `json:"action"`
+	Details   string    `json:"details"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// NewAuditEntryResponse creates a new audit entry response from audit entry model
+func NewAuditEntryResponse(entry *model.AuditEntry) *AuditEntryResponse {
+	return &AuditEntryResponse{
+		ID:        entry.ID,
+		Actor:     entry.Actor,
+		Action:    string(entry.Action),
+		Details:   entry.Details,
+		CreatedAt: entry.CreatedAt,
+	}
+}
+
+// Render preprocesses the response before marshalling
+func (ar *AuditEntryResponse) Render(w http.ResponseWriter, req *http.Request) error {
+	return nil
+}
+
+// AuditEntryListResponse is the list response payload for the audit entry data model
+type AuditEntryListResponse []*AuditEntryResponse
+
+// NewAuditEntryListResponse creates a new audit entry list response from multiple audit entry models
+func NewAuditEntryListResponse(entries []*model.AuditEntry) []render.Renderer {
+	list := make([]render.Renderer, len(entries))
+	for i, entry := range entries {
+		list[i] = NewAuditEntryResponse(entry)
+	}
+	return list
+}
@@ -0,0 +1,52 @@
+package renderer
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pagient/pagient-server/internal/model"
+
+	"github.com/go-chi/render"
+)
+
+// DisplayEntryResponse is the response payload of a called visit shown on waiting-room displays,
+// it must never contain personal data of the patient
+type DisplayEntryResponse struct {
+	// Ticket is a pseudonymous number to recognize the visit, unique among the displayed ones
+	Ticket   string     `json:"ticket"`
+	PagerID  uint       `json:"pagerId,omitempty"`
+	Room     string     `json:"room,omitempty"`
+	Status   string     `json:"status"`
+	CalledAt *time.Time `json:"calledAt,omitempty"`
+}
+
+// NewDisplayEntryResponse creates a new display entry response from visit model
+func NewDisplayEntryResponse(visit *model.Visit) *DisplayEntryResponse {
+	return &DisplayEntryResponse{
+		Ticket:   fmt.Sprintf("%03d", visit.Ticket),
+		PagerID:  visit.PagerID,
+		Room:     visit.Room,
+		Status:   string(visit.Status),
+		CalledAt: visit.CalledAt,
+	}
+}
+
+// Render preprocesses the response before marshalling
+func (dr *DisplayEntryResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+// DisplayListResponse is the list response payload of visits shown on waiting-room displays
+type DisplayListResponse []*DisplayEntryResponse
+
+// NewDisplayListResponse creates a new display list response from the displayed ones of multiple visit models
+func NewDisplayListResponse(visits []*model.Visit) []render.Renderer {
+	list := []render.Renderer{}
+	for _, visit := range visits {
+		if visit.IsDisplayed() {
+			list = append(list, NewDisplayEntryResponse(visit))
+		}
+	}
+	return list
+}
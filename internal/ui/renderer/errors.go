@@ -4,7 +4,7 @@ import (
 	"net/http"
 
 	"github.com/go-chi/render"
-	"github.com/rs/zerolog/log"
+	"github.com/rs/zerolog/hlog"
 )
 
 // ErrResponse renderer type for handling all sorts of errors.
@@ -19,7 +19,7 @@ type ErrResponse struct {
 // Render renders the ErrResponse
 func (e *ErrResponse) Render(w http.ResponseWriter, req *http.Request) error {
 	if e.Err != nil {
-		log.Error().
+		hlog.FromRequest(req).Error().
 			Err(e.Err).
 			Msg("")
 	}
@@ -75,5 +75,14 @@ func ErrGateway(err error) render.Renderer {
 // ErrUnauthorized represents a 401 error
 var ErrUnauthorized = &ErrResponse{HTTPStatusCode: http.StatusUnauthorized, Message: http.StatusText(http.StatusUnauthorized)}
 
+// ErrForbidden represents a 403 error
+var ErrForbidden = &ErrResponse{HTTPStatusCode: http.StatusForbidden, Message: http.StatusText(http.StatusForbidden)}
+
+// ErrLocked represents a 423 error caused by a locked out user
+var ErrLocked = &ErrResponse{HTTPStatusCode: http.StatusLocked, Message: http.StatusText(http.StatusLocked)}
+
+// ErrTooManyRequests represents a 429 error caused by exceeding a rate limit
+var ErrTooManyRequests = &ErrResponse{HTTPStatusCode: http.StatusTooManyRequests, Message: http.StatusText(http.StatusTooManyRequests)}
+
 // ErrNotFound represents a 404 error
 var ErrNotFound = &ErrResponse{HTTPStatusCode: http.StatusNotFound, Message: http.StatusText(http.StatusNotFound)}
@@ -0,0 +1,55 @@
+package renderer
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/pagient/pagient-server/internal/health"
+
+	"github.com/go-chi/render"
+)
+
+// HealthResponse is the response payload of the health of the server and it's dependencies
+type HealthResponse struct {
+	Status     string                        `json:"status"`
+	Components map[string]*ComponentResponse `json:"components,omitempty"`
+}
+
+// ComponentResponse is the response payload of the health of a single dependency
+type ComponentResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// Duration of the check in milliseconds
+	Duration int64 `json:"duration"`
+}
+
+// NewHealthResponse creates a new health response from a report of the components
+func NewHealthResponse(report *health.Report) *HealthResponse {
+	resp := &HealthResponse{
+		Status:     string(report.Status),
+		Components: make(map[string]*ComponentResponse, len(report.Components)),
+	}
+
+	for name, component := range report.Components {
+		compResp := &ComponentResponse{
+			Status:   string(component.Status),
+			Duration: int64(component.Duration / time.Millisecond),
+		}
+		if component.Error != nil {
+			compResp.Error = component.Error.Error()
+		}
+
+		resp.Components[name] = compResp
+	}
+
+	return resp
+}
+
+// Render preprocesses the response before marshalling
+func (hr *HealthResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	if hr.Status != string(health.StatusUp) {
+		render.Status(r, http.StatusServiceUnavailable)
+	}
+
+	return nil
+}
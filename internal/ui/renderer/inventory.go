@@ -0,0 +1,352 @@
+package renderer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/pagient/pagient-server/internal/model"
+
+	"github.com/go-chi/render"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// enumerates all formats of inventories, csv holds one section of an inventory only
+const (
+	InventoryFormatCSV  = "csv"
+	InventoryFormatJSON = "json"
+	InventoryFormatYAML = "yaml"
+)
+
+// inventoryHeaders holds the csv column names of each section, they match the json field names
+var inventoryHeaders = map[model.InventoryKind][]string{
+	model.InventoryKindClients: {"name"},
+	model.InventoryKindPagers:  {"name", "easyCallId"},
+	model.InventoryKindUsers:   {"username", "password", "role", "client", "origin"},
+}
+
+// ClientRow is the inventory payload for the client data model
+type ClientRow struct {
+	Name string `json:"name" yaml:"name"`
+}
+
+// PagerRow is the inventory payload for the pager data model
+type PagerRow struct {
+	Name       string `json:"name" yaml:"name"`
+	EasyCallID uint   `json:"easyCallId" yaml:"easyCallId"`
+}
+
+// UserRow is the inventory payload for the user data model, users refer to their client by name
+type UserRow struct {
+	Username string `json:"username" yaml:"username"`
+	Password string `json:"password,omitempty" yaml:"password,omitempty"`
+	Role     string `json:"role,omitempty" yaml:"role,omitempty"`
+	Client   string `json:"client,omitempty" yaml:"client,omitempty"`
+	Origin   string `json:"origin,omitempty" yaml:"origin,omitempty"`
+}
+
+// InventoryPayload is the import and export payload for the inventory data model
+type InventoryPayload struct {
+	Clients []*ClientRow `json:"clients,omitempty" yaml:"clients,omitempty"`
+	Pagers  []*PagerRow  `json:"pagers,omitempty" yaml:"pagers,omitempty"`
+	Users   []*UserRow   `json:"users,omitempty" yaml:"users,omitempty"`
+}
+
+// NewInventoryPayload creates a new inventory payload from inventory model, restricted to a section if kind is set
+func NewInventoryPayload(inventory *model.Inventory, kind model.InventoryKind) *InventoryPayload {
+	payload := &InventoryPayload{}
+
+	if kind == "" || kind == model.InventoryKindClients {
+		payload.Clients = make([]*ClientRow, len(inventory.Clients))
+		for i, client := range inventory.Clients {
+			payload.Clients[i] = &ClientRow{Name: client.Name}
+		}
+	}
+
+	if kind == "" || kind == model.InventoryKindPagers {
+		payload.Pagers = make([]*PagerRow, len(inventory.Pagers))
+		for i, pager := range inventory.Pagers {
+			payload.Pagers[i] = &PagerRow{Name: pager.Name, EasyCallID: pager.EasyCallID}
+		}
+	}
+
+	if kind == "" || kind == model.InventoryKindUsers {
+		payload.Users = make([]*UserRow, len(inventory.Users))
+		for i, user := range inventory.Users {
+			payload.Users[i] = &UserRow{
+				Username: user.Username,
+				Password: user.Password,
+				Role:     string(user.Role),
+				Client:   user.Client.Name,
+				Origin:   string(user.Origin),
+			}
+		}
+	}
+
+	return payload
+}
+
+// GetModel returns an Inventory model
+func (ip *InventoryPayload) GetModel() *model.Inventory {
+	inventory := &model.Inventory{
+		Clients: make([]*model.Client, len(ip.Clients)),
+		Pagers:  make([]*model.Pager, len(ip.Pagers)),
+		Users:   make([]*model.User, len(ip.Users)),
+	}
+
+	for i, row := range ip.Clients {
+		inventory.Clients[i] = &model.Client{Name: row.Name}
+	}
+
+	for i, row := range ip.Pagers {
+		inventory.Pagers[i] = &model.Pager{Name: row.Name, EasyCallID: row.EasyCallID}
+	}
+
+	for i, row := range ip.Users {
+		inventory.Users[i] = &model.User{
+			Username: row.Username,
+			Password: row.Password,
+			Role:     model.UserRole(row.Role),
+			Client:   model.Client{Name: row.Client},
+			Origin:   model.UserOrigin(row.Origin),
+		}
+	}
+
+	return inventory
+}
+
+// ValidInventoryFormat checks the format and kind of an inventory, csv requires a kind
+func ValidInventoryFormat(format string, kind model.InventoryKind) error {
+	switch format {
+	case InventoryFormatCSV, InventoryFormatJSON, InventoryFormatYAML:
+	default:
+		return errors.Errorf("format %q is unknown, use either csv, json or yaml", format)
+	}
+
+	if _, ok := inventoryHeaders[kind]; !ok && kind != "" {
+		return errors.Errorf("kind %q is unknown, use either clients, pagers or users", kind)
+	}
+
+	if format == InventoryFormatCSV && kind == "" {
+		return errors.New("csv holds one kind only, use either clients, pagers or users")
+	}
+
+	return nil
+}
+
+// ReadInventory decodes an inventory in given format, restricted to a section if kind is set.
+// Rows of csv which can't be decoded are returned as import errors.
+func ReadInventory(r io.Reader, format string, kind model.InventoryKind) (*model.Inventory, []*model.ImportError, error) {
+	if err := ValidInventoryFormat(format, kind); err != nil {
+		return nil, nil, err
+	}
+
+	payload := &InventoryPayload{}
+
+	switch format {
+	case InventoryFormatCSV:
+		importErrs, err := payload.readCSV(r, kind)
+		if err != nil || len(importErrs) > 0 {
+			return nil, importErrs, err
+		}
+	case InventoryFormatJSON:
+		if err := json.NewDecoder(r).Decode(payload); err != nil {
+			return nil, nil, errors.Wrap(err, "decode json failed")
+		}
+	case InventoryFormatYAML:
+		if err := yaml.NewDecoder(r).Decode(payload); err != nil {
+			return nil, nil, errors.Wrap(err, "decode yaml failed")
+		}
+	}
+
+	return payload.restrict(kind).GetModel(), nil, nil
+}
+
+// WriteInventory encodes the inventory in given format, restricted to a section if kind is set
+func WriteInventory(w io.Writer, format string, kind model.InventoryKind, inventory *model.Inventory) error {
+	if err := ValidInventoryFormat(format, kind); err != nil {
+		return err
+	}
+
+	payload := NewInventoryPayload(inventory, kind)
+
+	switch format {
+	case InventoryFormatCSV:
+		return payload.writeCSV(w, kind)
+	case InventoryFormatYAML:
+		return errors.Wrap(yaml.NewEncoder(w).Encode(payload), "encode yaml failed")
+	default:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		return errors.Wrap(encoder.Encode(payload), "encode json failed")
+	}
+}
+
+// restrict drops all sections except the one of kind if set
+func (ip *InventoryPayload) restrict(kind model.InventoryKind) *InventoryPayload {
+	switch kind {
+	case model.InventoryKindClients:
+		return &InventoryPayload{Clients: ip.Clients}
+	case model.InventoryKindPagers:
+		return &InventoryPayload{Pagers: ip.Pagers}
+	case model.InventoryKindUsers:
+		return &InventoryPayload{Users: ip.Users}
+	default:
+		return ip
+	}
+}
+
+// readCSV reads the section of kind, the columns are identified by the header
+func (ip *InventoryPayload) readCSV(r io.Reader, kind model.InventoryKind) ([]*model.ImportError, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "read csv header failed")
+	}
+
+	known := make(map[string]bool)
+	for _, column := range inventoryHeaders[kind] {
+		known[column] = true
+	}
+	for _, column := range header {
+		if !known[column] {
+			return nil, errors.Errorf("column %q is unknown for %s", column, kind)
+		}
+	}
+
+	var importErrs []*model.ImportError
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "read csv row %d failed", row)
+		}
+
+		values := make(map[string]string, len(header))
+		for i, column := range header {
+			values[column] = record[i]
+		}
+
+		switch kind {
+		case model.InventoryKindClients:
+			ip.Clients = append(ip.Clients, &ClientRow{Name: values["name"]})
+		case model.InventoryKindPagers:
+			var easyCallID uint64
+			if value := values["easyCallId"]; value != "" {
+				easyCallID, err = strconv.ParseUint(value, 10, 32)
+				if err != nil {
+					importErrs = append(importErrs, &model.ImportError{
+						Kind:    kind,
+						Row:     row,
+						Message: fmt.Sprintf("easyCallId: %q is no number.", value),
+					})
+					continue
+				}
+			}
+
+			ip.Pagers = append(ip.Pagers, &PagerRow{Name: values["name"], EasyCallID: uint(easyCallID)})
+		case model.InventoryKindUsers:
+			ip.Users = append(ip.Users, &UserRow{
+				Username: values["username"],
+				Password: values["password"],
+				Role:     values["role"],
+				Client:   values["client"],
+				Origin:   values["origin"],
+			})
+		}
+	}
+
+	return importErrs, nil
+}
+
+// writeCSV writes the section of kind including a header
+func (ip *InventoryPayload) writeCSV(w io.Writer, kind model.InventoryKind) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(inventoryHeaders[kind]); err != nil {
+		return errors.Wrap(err, "write csv header failed")
+	}
+
+	var records [][]string
+	switch kind {
+	case model.InventoryKindClients:
+		for _, row := range ip.Clients {
+			records = append(records, []string{row.Name})
+		}
+	case model.InventoryKindPagers:
+		for _, row := range ip.Pagers {
+			records = append(records, []string{row.Name, strconv.FormatUint(uint64(row.EasyCallID), 10)})
+		}
+	case model.InventoryKindUsers:
+		for _, row := range ip.Users {
+			records = append(records, []string{row.Username, row.Password, row.Role, row.Client, row.Origin})
+		}
+	}
+
+	if err := writer.WriteAll(records); err != nil {
+		return errors.Wrap(err, "write csv records failed")
+	}
+
+	return errors.Wrap(writer.Error(), "flush csv failed")
+}
+
+// ImportErrorResponse is the response payload for the import error data model
+type ImportErrorResponse struct {
+	Kind    string `json:"kind"`
+	Row     int    `json:"row"`
+	Message string `json:"message"`
+}
+
+// ImportResponse is the response payload of an import, listing the invalid rows if any
+type ImportResponse struct {
+	DryRun  bool                   `json:"dryRun"`
+	Clients int                    `json:"clients"`
+	Pagers  int                    `json:"pagers"`
+	Users   int                    `json:"users"`
+	Errors  []*ImportErrorResponse `json:"errors,omitempty"`
+}
+
+// NewImportResponse creates a new import response from the imported inventory and it's invalid rows
+func NewImportResponse(inventory *model.Inventory, importErrs []*model.ImportError, dryRun bool) *ImportResponse {
+	resp := &ImportResponse{DryRun: dryRun}
+
+	if len(importErrs) > 0 {
+		resp.Errors = make([]*ImportErrorResponse, len(importErrs))
+		for i, importErr := range importErrs {
+			resp.Errors[i] = &ImportErrorResponse{
+				Kind:    string(importErr.Kind),
+				Row:     importErr.Row,
+				Message: importErr.Message,
+			}
+		}
+
+		return resp
+	}
+
+	resp.Clients = len(inventory.Clients)
+	resp.Pagers = len(inventory.Pagers)
+	resp.Users = len(inventory.Users)
+
+	return resp
+}
+
+// Render preprocesses the response before marshalling, imports with invalid rows are unprocessable
+func (ir *ImportResponse) Render(w http.ResponseWriter, req *http.Request) error {
+	if len(ir.Errors) > 0 {
+		render.Status(req, http.StatusUnprocessableEntity)
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package renderer
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/pagient/pagient-server/internal/model"
+
+	"github.com/go-chi/render"
+)
+
+// LoginAttemptResponse is the response payload for the login attempt data model
+type LoginAttemptResponse struct {
+	ID        uint      `json:"id"`
+	Username  string    `json:"username"`
+	IP        string    `json:"ip"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// NewLoginAttemptResponse creates a new login attempt response from login attempt model
+func NewLoginAttemptResponse(attempt *model.LoginAttempt) *LoginAttemptResponse {
+	return &LoginAttemptResponse{
+		ID:        attempt.ID,
+		Username:  attempt.Username,
+		IP:        attempt.IP,
+		Reason:    string(attempt.Reason),
+		CreatedAt: attempt.CreatedAt,
+	}
+}
+
+// Render preprocesses the response before marshalling
+func (lr *LoginAttemptResponse) Render(w http.ResponseWriter, req *http.Request) error {
+	return nil
+}
+
+// LoginAttemptListResponse is the list response payload for the login attempt data model
+type LoginAttemptListResponse []*LoginAttemptResponse
+
+// NewLoginAttemptListResponse creates a new login attempt list response from multiple login attempt models
+func NewLoginAttemptListResponse(attempts []*model.LoginAttempt) []render.Renderer {
+	list := make([]render.Renderer, len(attempts))
+	for i, attempt := range attempts {
+		list[i] = NewLoginAttemptResponse(attempt)
+	}
+	return list
+}
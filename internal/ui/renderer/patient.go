@@ -1,21 +1,39 @@
 package renderer
 
 import (
+	"context"
 	"net/http"
+	"strings"
 
 	"github.com/go-chi/render"
 	"github.com/pagient/pagient-server/internal/model"
 )
 
+// visibleSocialSecurityNoDigits is the count of leading digits shown of masked social security numbers,
+// the trailing digits contain the date of birth
+const visibleSocialSecurityNoDigits = 4
+
+type maskKey struct{}
+
+// WithMaskedSocialSecurityNo returns the request with masking of social security numbers in responses enabled
+func WithMaskedSocialSecurityNo(req *http.Request) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), maskKey{}, true))
+}
+
+// MaskSocialSecurityNo returns the social security number with all but the leading digits replaced
+func MaskSocialSecurityNo(ssn string) string {
+	if len(ssn) <= visibleSocialSecurityNoDigits {
+		return strings.Repeat("*", len(ssn))
+	}
+
+	return ssn[:visibleSocialSecurityNoDigits] + strings.Repeat("*", len(ssn)-visibleSocialSecurityNoDigits)
+}
+
 // PatientRequest is the request payload for patient data model
 type PatientRequest struct {
 	ID               uint   `json:"id"`
 	SocialSecurityNo string `json:"ssn"`
 	Name             string `json:"name"`
-	PagerID          uint   `json:"pagerId"`
-	ClientID         uint   `json:"clientId"`
-	Status           string `json:"status"`
-	Active           bool   `json:"active"`
 }
 
 // Bind postprocesses the decoding of the request body
@@ -29,10 +47,6 @@ func (pr *PatientRequest) GetModel() *model.Patient {
 		ID:               pr.ID,
 		SocialSecurityNo: pr.SocialSecurityNo,
 		Name:             pr.Name,
-		PagerID:          pr.PagerID,
-		ClientID:         pr.ClientID,
-		Status:           model.PatientStatus(pr.Status),
-		Active:           pr.Active,
 	}
 }
 
@@ -41,10 +55,6 @@ type PatientResponse struct {
 	ID               uint   `json:"id"`
 	SocialSecurityNo string `json:"ssn"`
 	Name             string `json:"name"`
-	PagerID          uint   `json:"pagerId,omitempty"`
-	ClientID         uint   `json:"clientId"`
-	Status           string `json:"status"`
-	Active           bool   `json:"active"`
 }
 
 // NewPatientResponse creates a new patient response from patient model
@@ -53,17 +63,25 @@ func NewPatientResponse(patient *model.Patient) *PatientResponse {
 		ID:               patient.ID,
 		SocialSecurityNo: patient.SocialSecurityNo,
 		Name:             patient.Name,
-		PagerID:          patient.PagerID,
-		ClientID:         patient.ClientID,
-		Status:           string(patient.Status),
-		Active:           patient.Active,
 	}
 
 	return resp
 }
 
+// Masked returns a copy of the response with masked social security number
+func (pr *PatientResponse) Masked() *PatientResponse {
+	masked := *pr
+	masked.SocialSecurityNo = MaskSocialSecurityNo(pr.SocialSecurityNo)
+
+	return &masked
+}
+
 // Render preprocesses the response before marshalling
 func (pr *PatientResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	if masked, _ := r.Context().Value(maskKey{}).(bool); masked {
+		pr.SocialSecurityNo = MaskSocialSecurityNo(pr.SocialSecurityNo)
+	}
+
 	return nil
 }
 
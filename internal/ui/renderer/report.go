@@ -0,0 +1,121 @@
+package renderer
+
+import (
+	"encoding/csv"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/pagient/pagient-server/internal/model"
+
+	"github.com/go-chi/render"
+	"github.com/pkg/errors"
+)
+
+// reportHeader holds the csv column names of report rows, it matches the json field names
+var reportHeader = []string{
+	"group",
+	"visits",
+	"called",
+	"waitAverage",
+	"waitMedian",
+	"waitP90",
+	"arrivalAverage",
+	"arrivalMedian",
+	"arrivalP90",
+	"noShows",
+	"noShowRate",
+	"pagerTime",
+	"pagerUtilization",
+}
+
+// ReportRowResponse is the response payload for the report row data model, durations are in seconds
+type ReportRowResponse struct {
+	Group            string  `json:"group"`
+	Visits           int     `json:"visits"`
+	Called           int     `json:"called"`
+	WaitAverage      int64   `json:"waitAverage"`
+	WaitMedian       int64   `json:"waitMedian"`
+	WaitP90          int64   `json:"waitP90"`
+	ArrivalAverage   int64   `json:"arrivalAverage"`
+	ArrivalMedian    int64   `json:"arrivalMedian"`
+	ArrivalP90       int64   `json:"arrivalP90"`
+	NoShows          int     `json:"noShows"`
+	NoShowRate       float64 `json:"noShowRate"`
+	PagerTime        int64   `json:"pagerTime"`
+	PagerUtilization float64 `json:"pagerUtilization"`
+}
+
+// NewReportRowResponse creates a new report row response from report row model
+func NewReportRowResponse(row *model.ReportRow) *ReportRowResponse {
+	return &ReportRowResponse{
+		Group:            row.Group,
+		Visits:           row.Visits,
+		Called:           row.Called,
+		WaitAverage:      int64(row.WaitAverage.Seconds()),
+		WaitMedian:       int64(row.WaitMedian.Seconds()),
+		WaitP90:          int64(row.WaitP90.Seconds()),
+		ArrivalAverage:   int64(row.ArrivalAverage.Seconds()),
+		ArrivalMedian:    int64(row.ArrivalMedian.Seconds()),
+		ArrivalP90:       int64(row.ArrivalP90.Seconds()),
+		NoShows:          row.NoShows,
+		NoShowRate:       row.NoShowRate,
+		PagerTime:        int64(row.PagerTime.Seconds()),
+		PagerUtilization: row.PagerUtilization,
+	}
+}
+
+// Render preprocesses the response before marshalling
+func (rr *ReportRowResponse) Render(w http.ResponseWriter, req *http.Request) error {
+	return nil
+}
+
+// record returns the csv record of the report row
+func (rr *ReportRowResponse) record() []string {
+	return []string{
+		rr.Group,
+		strconv.Itoa(rr.Visits),
+		strconv.Itoa(rr.Called),
+		strconv.FormatInt(rr.WaitAverage, 10),
+		strconv.FormatInt(rr.WaitMedian, 10),
+		strconv.FormatInt(rr.WaitP90, 10),
+		strconv.FormatInt(rr.ArrivalAverage, 10),
+		strconv.FormatInt(rr.ArrivalMedian, 10),
+		strconv.FormatInt(rr.ArrivalP90, 10),
+		strconv.Itoa(rr.NoShows),
+		strconv.FormatFloat(rr.NoShowRate, 'f', 4, 64),
+		strconv.FormatInt(rr.PagerTime, 10),
+		strconv.FormatFloat(rr.PagerUtilization, 'f', 4, 64),
+	}
+}
+
+// ReportListResponse is the list response payload for the report row data model
+type ReportListResponse []*ReportRowResponse
+
+// NewReportListResponse creates a new report list response from multiple report row models
+func NewReportListResponse(rows []*model.ReportRow) []render.Renderer {
+	list := make([]render.Renderer, len(rows))
+	for i, row := range rows {
+		list[i] = NewReportRowResponse(row)
+	}
+	return list
+}
+
+// WriteReportCSV writes the report rows as csv including a header
+func WriteReportCSV(w io.Writer, rows []*model.ReportRow) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(reportHeader); err != nil {
+		return errors.Wrap(err, "write csv header failed")
+	}
+
+	for _, row := range rows {
+		if err := writer.Write(NewReportRowResponse(row).record()); err != nil {
+			return errors.Wrap(err, "write csv record failed")
+		}
+	}
+
+	writer.Flush()
+
+	return errors.Wrap(writer.Error(), "flush csv failed")
+}
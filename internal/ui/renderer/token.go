@@ -2,21 +2,55 @@ package renderer
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/pagient/pagient-server/internal/model"
 
 	"github.com/go-chi/render"
 )
 
+// enumerates all supported oauth grant types
+const (
+	GrantTypePassword     = "password"
+	GrantTypeRefreshToken = "refresh_token"
+)
+
+// TokenRequest is the request payload to create a token
+type TokenRequest struct {
+	GrantType    string `json:"grant_type"`
+	Username     string `json:"username"`
+	Password     string `json:"password"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Bind postprocesses the decoding of the request body
+func (tr *TokenRequest) Bind(r *http.Request) error {
+	// logins of clients not knowing about grant types
+	if tr.GrantType == "" {
+		tr.GrantType = GrantTypePassword
+	}
+
+	return nil
+}
+
 // TokenResponse is the response payload for the token data model
 type TokenResponse struct {
-	Token string `json:"token"`
+	Token        string `json:"token"`
+	TokenType    string `json:"token_type,omitempty"`
+	ExpiresIn    int64  `json:"expires_in,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
 }
 
 // NewTokenResponse creates a new token response from token model
 func NewTokenResponse(token *model.Token) *TokenResponse {
 	resp := &TokenResponse{Token: token.Raw}
 
+	if token.Refresh != "" {
+		resp.TokenType = "bearer"
+		resp.ExpiresIn = int64(time.Until(token.ExpiresAt).Seconds())
+		resp.RefreshToken = token.Refresh
+	}
+
 	return resp
 }
 
@@ -25,14 +59,58 @@ func (cr *TokenResponse) Render(w http.ResponseWriter, req *http.Request) error
 	return nil
 }
 
-// TokenListResponse is the list response payload for the token data model
-type TokenListResponse []*TokenResponse
+// SessionResponse is the response payload for a session of a user
+type SessionResponse struct {
+	ID         uint      `json:"id"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"userAgent"`
+	Current    bool      `json:"current"`
+}
+
+// NewSessionResponse creates a new session response from the active token of a session
+func NewSessionResponse(token *model.Token, current bool) *SessionResponse {
+	return &SessionResponse{
+		ID:         token.ID,
+		CreatedAt:  token.CreatedAt,
+		LastSeenAt: token.LastSeenAt,
+		IP:         token.IP,
+		UserAgent:  token.UserAgent,
+		Current:    current,
+	}
+}
 
-// NewTokenListResponse creates a new token list response from multiple token models
-func NewTokenListResponse(tokens []*model.Token) []render.Renderer {
+// Render preprocesses the response before marshalling
+func (sr *SessionResponse) Render(w http.ResponseWriter, req *http.Request) error {
+	return nil
+}
+
+// SessionListResponse is the list response payload for sessions of a user
+type SessionListResponse []*SessionResponse
+
+// NewSessionListResponse creates a new session list response from the active tokens of sessions
+func NewSessionListResponse(tokens []*model.Token, currentSession string) []render.Renderer {
 	list := make([]render.Renderer, len(tokens))
 	for i, token := range tokens {
-		list[i] = NewTokenResponse(token)
+		list[i] = NewSessionResponse(token, token.Session() == currentSession)
 	}
 	return list
 }
+
+// ProvidersResponse is the response payload listing the available login methods
+type ProvidersResponse struct {
+	OIDC bool `json:"oidc"`
+}
+
+// NewProvidersResponse creates a new providers response
+func NewProvidersResponse(oidc bool) *ProvidersResponse {
+	return &ProvidersResponse{
+		OIDC: oidc,
+	}
+}
+
+// Render preprocesses the response before marshalling
+func (pr *ProvidersResponse) Render(w http.ResponseWriter, req *http.Request) error {
+	return nil
+}
@@ -0,0 +1,187 @@
+package renderer
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/render"
+	"github.com/pagient/pagient-server/internal/model"
+)
+
+type averageWaitTimeKey struct{}
+
+// WithAverageWaitTime returns the request with the average wait time used to estimate the wait time of visits in responses
+func WithAverageWaitTime(req *http.Request, average time.Duration) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), averageWaitTimeKey{}, average))
+}
+
+// VisitRequest is the request payload for visit data model
+type VisitRequest struct {
+	ID       uint            `json:"id"`
+	Patient  *PatientRequest `json:"patient"`
+	PagerID  uint            `json:"pagerId"`
+	ClientID uint            `json:"clientId"`
+	Room     string          `json:"room"`
+	Status   string          `json:"status"`
+	Active   bool            `json:"active"`
+}
+
+// Bind postprocesses the decoding of the request body
+func (vr *VisitRequest) Bind(r *http.Request) error {
+	return nil
+}
+
+// GetModel returns a Visit model
+func (vr *VisitRequest) GetModel() *model.Visit {
+	visit := &model.Visit{
+		ID:       vr.ID,
+		PagerID:  vr.PagerID,
+		ClientID: vr.ClientID,
+		Room:     vr.Room,
+		Status:   model.VisitStatus(vr.Status),
+		Active:   vr.Active,
+	}
+
+	if vr.Patient != nil {
+		visit.Patient = *vr.Patient.GetModel()
+	}
+
+	return visit
+}
+
+// CheckInRequest is the flat request payload to check in a patient,
+// it's kept for clients posting patients including the state of their visit
+type CheckInRequest struct {
+	PatientRequest
+	PagerID  uint   `json:"pagerId"`
+	ClientID uint   `json:"clientId"`
+	Room     string `json:"room"`
+	Status   string `json:"status"`
+	Active   bool   `json:"active"`
+}
+
+// GetModel returns a Visit model of the checked in patient
+func (cr *CheckInRequest) GetModel() *model.Visit {
+	return &model.Visit{
+		Patient:  *cr.PatientRequest.GetModel(),
+		PagerID:  cr.PagerID,
+		ClientID: cr.ClientID,
+		Room:     cr.Room,
+		Status:   model.VisitStatus(cr.Status),
+		Active:   cr.Active,
+	}
+}
+
+// VisitResponse is the response payload for the visit data model
+type VisitResponse struct {
+	ID            uint             `json:"id"`
+	Patient       *PatientResponse `json:"patient"`
+	PagerID       uint             `json:"pagerId,omitempty"`
+	ClientID      uint             `json:"clientId"`
+	Room          string           `json:"room,omitempty"`
+	Status        string           `json:"status"`
+	Active        bool             `json:"active"`
+	CheckedInAt   time.Time        `json:"checkedInAt"`
+	FirstCalledAt *time.Time       `json:"firstCalledAt,omitempty"`
+	CalledAt      *time.Time       `json:"calledAt,omitempty"`
+	RecalledAt    *time.Time       `json:"recalledAt,omitempty"`
+	FinishedAt    *time.Time       `json:"finishedAt,omitempty"`
+	// QueuePosition in the queue of the room, starting at 1 for the patient examined next
+	QueuePosition   uint       `json:"queuePosition,omitempty"`
+	EstimatedCallAt *time.Time `json:"estimatedCallAt,omitempty"`
+	// WaitTime in seconds until the first call or until now if not yet called
+	WaitTime int64 `json:"waitTime"`
+	// EstimatedWaitTime in seconds until the first call, only set for not yet called visits
+	EstimatedWaitTime *int64 `json:"estimatedWaitTime,omitempty"`
+
+	visit *model.Visit
+}
+
+// NewVisitResponse creates a new visit response from visit model
+func NewVisitResponse(visit *model.Visit) *VisitResponse {
+	resp := &VisitResponse{
+		ID:              visit.ID,
+		Patient:         NewPatientResponse(&visit.Patient),
+		PagerID:         visit.PagerID,
+		ClientID:        visit.ClientID,
+		Room:            visit.Room,
+		Status:          string(visit.Status),
+		Active:          visit.Active,
+		CheckedInAt:     visit.CreatedAt,
+		FirstCalledAt:   visit.FirstCalledAt,
+		CalledAt:        visit.CalledAt,
+		RecalledAt:      visit.RecalledAt,
+		FinishedAt:      visit.FinishedAt,
+		QueuePosition:   visit.QueuePosition,
+		EstimatedCallAt: visit.EstimatedCallAt,
+		WaitTime:        int64(visit.WaitTime(time.Now()).Seconds()),
+		visit:           visit,
+	}
+
+	// predicted estimates don't depend on the average wait time, so they are pushed to views as well
+	if visit.EstimatedCallAt != nil {
+		resp.setEstimatedWaitTime(0)
+	}
+
+	return resp
+}
+
+// Masked returns a copy of the response with masked social security number of the patient
+func (vr *VisitResponse) Masked() *VisitResponse {
+	masked := *vr
+	masked.Patient = vr.Patient.Masked()
+
+	return &masked
+}
+
+// Display returns the entry of the visit on waiting-room displays and whether it's shown there
+func (vr *VisitResponse) Display() (*DisplayEntryResponse, bool) {
+	return NewDisplayEntryResponse(vr.visit), vr.visit.IsDisplayed()
+}
+
+// Render preprocesses the response before marshalling
+func (vr *VisitResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	if average, ok := r.Context().Value(averageWaitTimeKey{}).(time.Duration); ok {
+		vr.setEstimatedWaitTime(average)
+	}
+
+	return vr.Patient.Render(w, r)
+}
+
+func (vr *VisitResponse) setEstimatedWaitTime(average time.Duration) {
+	if estimated, ok := vr.visit.EstimatedWaitTime(average, time.Now()); ok {
+		seconds := int64(estimated.Seconds())
+		vr.EstimatedWaitTime = &seconds
+	}
+}
+
+// VisitListResponse is the list response payload for the visit data model
+type VisitListResponse []*VisitResponse
+
+// NewVisitListResponse creates a new visit list response from multiple visit models
+func NewVisitListResponse(visits []*model.Visit) []render.Renderer {
+	list := make([]render.Renderer, len(visits))
+	for i, visit := range visits {
+		list[i] = NewVisitResponse(visit)
+	}
+	return list
+}
+
+// WaitTimeResponse is the response payload for wait time statistics
+type WaitTimeResponse struct {
+	// AverageWaitTime in seconds until the first call of recently called patients
+	AverageWaitTime int64 `json:"averageWaitTime"`
+}
+
+// NewWaitTimeResponse creates a new wait time response from the average wait time
+func NewWaitTimeResponse(average time.Duration) *WaitTimeResponse {
+	return &WaitTimeResponse{
+		AverageWaitTime: int64(average.Seconds()),
+	}
+}
+
+// Render preprocesses the response before marshalling
+func (wr *WaitTimeResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
@@ -0,0 +1,40 @@
+package context
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pagient/pagient-server/internal/service"
+	"github.com/pagient/pagient-server/internal/ui/renderer"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"github.com/rs/zerolog/hlog"
+)
+
+// AccountCtx middleware is used to load the User object managed by
+// the request from the URL parameters. In case the User
+// could not be found, we stop here and return a 404.
+func AccountCtx(userService service.UserService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			user, err := Scoped(userService, req).ShowUser(chi.URLParam(req, "username"))
+			if err != nil {
+				hlog.FromRequest(req).Error().
+					Err(err).
+					Msg("get user failed")
+
+				render.Render(w, req, renderer.ErrInternalServer(err))
+				return
+			}
+
+			if user == nil {
+				render.Render(w, req, renderer.ErrNotFound)
+				return
+			}
+
+			ctx := context.WithValue(req.Context(), AccountKey, user)
+			next.ServeHTTP(w, req.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package context
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pagient/pagient-server/internal/model"
+)
+
+// WithAPIKey returns the request authenticated by the api key
+func WithAPIKey(req *http.Request, key *model.APIKey) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), APIKeyKey, key))
+}
+
+// APIKey returns the api key the request has been authenticated with, nil for user sessions
+func APIKey(req *http.Request) *model.APIKey {
+	key, _ := req.Context().Value(APIKeyKey).(*model.APIKey)
+	return key
+}
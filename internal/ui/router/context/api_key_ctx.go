@@ -0,0 +1,50 @@
+package context
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/service"
+	"github.com/pagient/pagient-server/internal/ui/renderer"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"github.com/rs/zerolog/hlog"
+)
+
+// APIKeyCtx middleware is used to load an APIKey object from
+// the URL parameters passed through as the request. In case the APIKey
+// could not be found or belongs to another user, we stop here and return a 404.
+// Admins may manage the api keys of all users.
+func APIKeyCtx(apiKeyService service.APIKeyService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			id, err := strconv.Atoi(chi.URLParam(req, "keyID"))
+			if err != nil {
+				render.Render(w, req, renderer.ErrBadRequest(err))
+				return
+			}
+
+			key, err := Scoped(apiKeyService, req).ShowAPIKey(uint(id))
+			if err != nil {
+				hlog.FromRequest(req).Error().
+					Err(err).
+					Msg("get api key failed")
+
+				render.Render(w, req, renderer.ErrInternalServer(err))
+				return
+			}
+
+			user := req.Context().Value(UserKey).(*model.User)
+			if key == nil || (key.UserID != user.ID && user.Role != model.UserRoleAdmin) {
+				render.Render(w, req, renderer.ErrNotFound)
+				return
+			}
+
+			ctx := context.WithValue(req.Context(), ManagedAPIKeyKey, key)
+			next.ServeHTTP(w, req.WithContext(ctx))
+		})
+	}
+}
@@ -4,12 +4,13 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/pagient/pagient-server/internal/model"
 	"github.com/pagient/pagient-server/internal/service"
 	"github.com/pagient/pagient-server/internal/ui/renderer"
 
 	"github.com/go-chi/jwtauth"
 	"github.com/go-chi/render"
-	"github.com/rs/zerolog/log"
+	"github.com/rs/zerolog/hlog"
 )
 
 // AuthCtx middleware is used to load a User object from
@@ -18,15 +19,22 @@ import (
 func AuthCtx(userService service.UserService) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			// api keys act on behalf of their user
+			if key, ok := req.Context().Value(APIKeyKey).(*model.APIKey); ok {
+				ctx := context.WithValue(req.Context(), UserKey, &key.User)
+				next.ServeHTTP(w, req.WithContext(ctx))
+				return
+			}
+
 			jwtToken, _, err := jwtauth.FromContext(req.Context())
 			if err != nil {
 				render.Render(w, req, renderer.ErrUnauthorized)
 				return
 			}
 
-			user, err := userService.ShowUserByToken(jwtToken.Raw)
+			user, err := Scoped(userService, req).ShowUserByToken(jwtToken.Raw)
 			if err != nil {
-				log.Error().
+				hlog.FromRequest(req).Error().
 					Err(err).
 					Msg("get user failed")
 
@@ -9,7 +9,7 @@ import (
 	"github.com/pagient/pagient-server/internal/ui/renderer"
 
 	"github.com/go-chi/render"
-	"github.com/rs/zerolog/log"
+	"github.com/rs/zerolog/hlog"
 )
 
 // ClientCtx middleware is used to load a Client object from the authenticated user
@@ -17,9 +17,9 @@ func ClientCtx(clientService service.ClientService) func(http.Handler) http.Hand
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 			ctxUser := req.Context().Value(UserKey).(*model.User)
-			client, err := clientService.ShowClientByUser(ctxUser.Username)
+			client, err := Scoped(clientService, req).ShowClientByUser(ctxUser.Username)
 			if err != nil {
-				log.Fatal().
+				hlog.FromRequest(req).Error().
 					Err(err).
 					Msg("get client failed")
 
@@ -4,7 +4,16 @@ type ctxKey string
 
 // enumerates all context keys
 const (
+	AccountKey ctxKey = "account"
+	APIKeyKey  ctxKey = "apiKey"
 	ClientKey  ctxKey = "client"
-	PatientKey ctxKey = "patient"
+	// ManagedAPIKeyKey holds the api key managed by the request,
+	// not the one it has been authenticated with
+	ManagedAPIKeyKey ctxKey = "managedApiKey"
+	PatientKey       ctxKey = "patient"
+	// PeerIPKey holds the address of the connection's peer, ignoring proxy headers
+	PeerIPKey  ctxKey = "peerIp"
+	SessionKey ctxKey = "session"
 	UserKey    ctxKey = "user"
+	VisitKey   ctxKey = "visit"
 )
@@ -12,7 +12,7 @@ import (
 	"github.com/go-chi/chi"
 	"github.com/go-chi/render"
 	"github.com/pkg/errors"
-	"github.com/rs/zerolog/log"
+	"github.com/rs/zerolog/hlog"
 )
 
 // PatientCtx middleware is used to load a RoomAssignment object from
@@ -30,9 +30,9 @@ func PatientCtx(patientService service.PatientService) func(http.Handler) http.H
 					return
 				}
 
-				patient, err = patientService.ShowPatient(uint(id))
+				patient, err = Scoped(patientService, req).ShowPatient(uint(id))
 				if err != nil {
-					log.Fatal().
+					hlog.FromRequest(req).Error().
 						Err(err).
 						Msg("get patient failed")
 
@@ -51,7 +51,7 @@ func PatientCtx(patientService service.PatientService) func(http.Handler) http.H
 			}
 
 			err := errors.New("patient id parameter missing in url")
-			log.Error().
+			hlog.FromRequest(req).Error().
 				Err(err).
 				Msg("patient id parameter missing in url")
 
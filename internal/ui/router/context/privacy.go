@@ -0,0 +1,22 @@
+package context
+
+import (
+	"net/http"
+
+	"github.com/pagient/pagient-server/internal/model"
+)
+
+// RevealSocialSecurityNo returns whether full social security numbers may be sent to the requester,
+// waiting-room displays and api keys without permission to read patients only get them masked
+func RevealSocialSecurityNo(req *http.Request) bool {
+	if key := APIKey(req); key != nil {
+		return key.HasScope(model.APIKeyScopeReadPatients)
+	}
+
+	user, ok := req.Context().Value(UserKey).(*model.User)
+	if !ok || user == nil {
+		return false
+	}
+
+	return user.Role != model.UserRoleDisplay
+}
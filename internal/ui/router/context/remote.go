@@ -0,0 +1,40 @@
+package context
+
+import (
+	"context"
+	"net"
+	"net/http"
+)
+
+// RemoteIP returns the ip address of the client sending the request
+func RemoteIP(req *http.Request) string {
+	return host(req.RemoteAddr)
+}
+
+// PeerCtx middleware stores the address of the connection's peer before it is replaced
+// by proxy headers, it has to be used in front of the RealIP middleware
+func PeerCtx(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := context.WithValue(req.Context(), PeerIPKey, host(req.RemoteAddr))
+		next.ServeHTTP(w, req.WithContext(ctx))
+	})
+}
+
+// PeerIP returns the ip address of the connection's peer, which can't be spoofed by proxy headers.
+// Requests which haven't passed the PeerCtx middleware fall back to the remote ip.
+func PeerIP(req *http.Request) string {
+	if ip, ok := req.Context().Value(PeerIPKey).(string); ok {
+		return ip
+	}
+
+	return RemoteIP(req)
+}
+
+// host strips the port of an address, RemoteAddr contains the port unless it has been replaced by a proxy header
+func host(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+
+	return addr
+}
@@ -0,0 +1,15 @@
+package context
+
+import (
+	"net/http"
+
+	"github.com/pagient/pagient-server/internal/service"
+
+	"github.com/rs/zerolog/hlog"
+)
+
+// Scoped returns the service scoped to the request, its log entries carry the id of the request
+// and its operations are traced as children of the span of the request
+func Scoped(s service.Scope, req *http.Request) service.Service {
+	return s.WithLogger(*hlog.FromRequest(req)).WithContext(req.Context())
+}
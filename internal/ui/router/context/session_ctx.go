@@ -0,0 +1,60 @@
+package context
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/service"
+	"github.com/pagient/pagient-server/internal/ui/renderer"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"github.com/rs/zerolog/hlog"
+)
+
+// SessionCtx middleware is used to load the active Token of a session from
+// the URL parameters passed through as the request. In case the session
+// could not be found or belongs to another user, we stop here and return a 404.
+func SessionCtx(tokenService service.TokenService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			id, err := strconv.Atoi(chi.URLParam(req, "sessionID"))
+			if err != nil {
+				render.Render(w, req, renderer.ErrBadRequest(err))
+				return
+			}
+
+			token, err := Scoped(tokenService, req).ShowTokenByID(uint(id))
+			if err != nil {
+				hlog.FromRequest(req).Error().
+					Err(err).
+					Msg("get token failed")
+
+				render.Render(w, req, renderer.ErrInternalServer(err))
+				return
+			}
+
+			owner := SessionOwner(req.Context())
+			if token == nil || token.Rotated || owner == nil || token.UserID != owner.ID {
+				render.Render(w, req, renderer.ErrNotFound)
+				return
+			}
+
+			ctx := context.WithValue(req.Context(), SessionKey, token)
+			next.ServeHTTP(w, req.WithContext(ctx))
+		})
+	}
+}
+
+// SessionOwner returns the user whose sessions are managed by the request,
+// which is the managed account if there is one, otherwise the authenticated user
+func SessionOwner(ctx context.Context) *model.User {
+	if account, ok := ctx.Value(AccountKey).(*model.User); ok {
+		return account
+	}
+
+	user, _ := ctx.Value(UserKey).(*model.User)
+	return user
+}
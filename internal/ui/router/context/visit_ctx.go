@@ -0,0 +1,61 @@
+package context
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/service"
+	"github.com/pagient/pagient-server/internal/ui/renderer"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/hlog"
+)
+
+// VisitCtx middleware is used to load a Visit object from
+// the URL parameters passed through as the request. In case
+// the Visit could not be found, we stop here and return a 404.
+func VisitCtx(visitService service.VisitService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			var visit *model.Visit
+
+			if visitID := chi.URLParam(req, "visitID"); visitID != "" {
+				id, err := strconv.Atoi(visitID)
+				if err != nil {
+					render.Render(w, req, renderer.ErrBadRequest(err))
+					return
+				}
+
+				visit, err = Scoped(visitService, req).ShowVisit(uint(id))
+				if err != nil {
+					hlog.FromRequest(req).Error().
+						Err(err).
+						Msg("get visit failed")
+
+					render.Render(w, req, renderer.ErrInternalServer(err))
+					return
+				}
+
+				if visit == nil || visit.ClosedAt != nil {
+					render.Render(w, req, renderer.ErrNotFound)
+					return
+				}
+
+				ctx := context.WithValue(req.Context(), VisitKey, visit)
+				next.ServeHTTP(w, req.WithContext(ctx))
+				return
+			}
+
+			err := errors.New("visit id parameter missing in url")
+			hlog.FromRequest(req).Error().
+				Err(err).
+				Msg("visit id parameter missing in url")
+
+			render.Render(w, req, renderer.ErrInternalServer(err))
+		})
+	}
+}
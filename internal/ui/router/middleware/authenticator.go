@@ -2,18 +2,69 @@ package middleware
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/pagient/pagient-server/internal/service"
 	"github.com/pagient/pagient-server/internal/ui/renderer"
+	"github.com/pagient/pagient-server/internal/ui/router/context"
 
 	"github.com/go-chi/jwtauth"
 	"github.com/go-chi/render"
+	"github.com/rs/zerolog/hlog"
 )
 
-// Authenticator middleware is used to authenticate the user by bearer token
-func Authenticator(tokenService service.TokenService) func(http.Handler) http.Handler {
+const (
+	// lastSeenInterval throttles storing the last access of a token or api key
+	lastSeenInterval = time.Minute
+	// apiKeyHeader holds the api key of integrations
+	apiKeyHeader = "X-API-Key"
+	// apiKeyParam holds the api key of waiting-room displays, which can't set headers on websockets
+	apiKeyParam = "key"
+)
+
+// Authenticator middleware is used to authenticate the user by bearer token or the integration by api key
+func Authenticator(tokenService service.TokenService, apiKeyService service.APIKeyService) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			plainKey, fromQuery := req.Header.Get(apiKeyHeader), false
+			if plainKey == "" {
+				plainKey, fromQuery = req.URL.Query().Get(apiKeyParam), true
+			}
+
+			if plainKey != "" {
+				key, err := context.Scoped(apiKeyService, req).ShowAPIKeyByKey(plainKey)
+				if err != nil {
+					render.Render(w, req, renderer.ErrInternalServer(err))
+					return
+				}
+
+				if key == nil || key.IsExpired(time.Now()) {
+					render.Render(w, req, renderer.ErrUnauthorized)
+					return
+				}
+
+				// urls end up in logs and browser histories, so only display keys may be passed there
+				if fromQuery && !key.IsDisplayOnly() {
+					render.Render(w, req, renderer.ErrUnauthorized)
+					return
+				}
+
+				if time.Since(key.LastUsedAt) > lastSeenInterval {
+					key.LastUsedAt = time.Now()
+
+					if err := context.Scoped(apiKeyService, req).TouchAPIKey(key); err != nil {
+						hlog.FromRequest(req).Warn().
+							Err(err).
+							Uint("key", key.ID).
+							Msg("store last usage of api key failed")
+					}
+				}
+
+				// API key is authenticated, pass it through
+				next.ServeHTTP(w, context.WithAPIKey(req, key))
+				return
+			}
+
 			jwtToken, _, err := jwtauth.FromContext(req.Context())
 
 			if err != nil {
@@ -26,13 +77,27 @@ func Authenticator(tokenService service.TokenService) func(http.Handler) http.Ha
 				return
 			}
 
-			token, err := tokenService.ShowToken(jwtToken.Raw)
+			token, err := context.Scoped(tokenService, req).ShowToken(jwtToken.Raw)
 			if err != nil {
 				render.Render(w, req, renderer.ErrInternalServer(err))
 				return
 			}
 
-			if token != nil {
+			// rotated tokens have been replaced by refreshing
+			if token != nil && !token.Rotated {
+				if time.Since(token.LastSeenAt) > lastSeenInterval {
+					token.LastSeenAt = time.Now()
+					token.IP = context.RemoteIP(req)
+					token.UserAgent = req.UserAgent()
+
+					if err := context.Scoped(tokenService, req).TouchToken(token); err != nil {
+						hlog.FromRequest(req).Warn().
+							Err(err).
+							Uint("token", token.ID).
+							Msg("store last access of token failed")
+					}
+				}
+
 				// Token is authenticated, pass it through
 				next.ServeHTTP(w, req)
 				return
@@ -43,3 +108,18 @@ func Authenticator(tokenService service.TokenService) func(http.Handler) http.Ha
 		})
 	}
 }
+
+// RedactedURL returns the url of the request with the api key of displays replaced,
+// so keys passed as query parameter don't end up in logs
+func RedactedURL(req *http.Request) string {
+	query := req.URL.Query()
+	if query.Get(apiKeyParam) == "" {
+		return req.URL.String()
+	}
+
+	query.Set(apiKeyParam, "redacted")
+
+	u := *req.URL
+	u.RawQuery = query.Encode()
+	return u.String()
+}
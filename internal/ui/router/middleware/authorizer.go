@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/ui/renderer"
+	"github.com/pagient/pagient-server/internal/ui/router/context"
+
+	"github.com/go-chi/render"
+)
+
+// Authorizer middleware is used to restrict access to users with one of the given roles,
+// it requires the user to be loaded into the request context. API keys are never authorized.
+func Authorizer(roles ...model.UserRole) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if context.APIKey(req) != nil {
+				render.Render(w, req, renderer.ErrForbidden)
+				return
+			}
+
+			user, ok := req.Context().Value(context.UserKey).(*model.User)
+			if !ok || user == nil {
+				render.Render(w, req, renderer.ErrUnauthorized)
+				return
+			}
+
+			for _, role := range roles {
+				if user.Role == role {
+					next.ServeHTTP(w, req)
+					return
+				}
+			}
+
+			render.Render(w, req, renderer.ErrForbidden)
+		})
+	}
+}
+
+// Deny middleware is used to reject users with one of the given roles,
+// it requires the user to be loaded into the request context. API keys are restricted by scope instead.
+func Deny(roles ...model.UserRole) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if context.APIKey(req) != nil {
+				next.ServeHTTP(w, req)
+				return
+			}
+
+			user, ok := req.Context().Value(context.UserKey).(*model.User)
+			if !ok || user == nil {
+				render.Render(w, req, renderer.ErrUnauthorized)
+				return
+			}
+
+			for _, role := range roles {
+				if user.Role == role {
+					render.Render(w, req, renderer.ErrForbidden)
+					return
+				}
+			}
+
+			next.ServeHTTP(w, req)
+		})
+	}
+}
+
+// Scope middleware is used to restrict access of api keys to keys with one of the given scopes,
+// without scopes api keys are rejected. User sessions aren't restricted.
+func Scope(scopes ...model.APIKeyScope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if key := context.APIKey(req); key != nil && !key.HasScope(scopes...) {
+				render.Render(w, req, renderer.ErrForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, req)
+		})
+	}
+}
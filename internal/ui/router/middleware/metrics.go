@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pagient/pagient-server/internal/metrics"
+
+	"github.com/go-chi/chi"
+	chiMiddleware "github.com/go-chi/chi/middleware"
+)
+
+// Metrics middleware observes the latency of requests by route pattern,
+// the patterns keep the count of series low unlike the urls containing ids
+func Metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		start := time.Now()
+		ww := chiMiddleware.NewWrapResponseWriter(w, req.ProtoMajor)
+
+		next.ServeHTTP(ww, req)
+
+		// the route is known after the request has been routed
+		route := chi.RouteContext(req.Context()).RoutePattern()
+		if route == "" {
+			route = "unknown"
+		}
+
+		// handlers not writing a header respond with ok
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		metrics.HTTPRequestDuration.
+			WithLabelValues(req.Method, route, strconv.Itoa(status)).
+			Observe(metrics.Since(start))
+	})
+}
+
+// MetricsToken middleware is used to restrict scraping of metrics to requests bearing the configured token,
+// without a token metrics are public
+func MetricsToken(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if token != "" && req.Header.Get("Authorization") != "Bearer "+token {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, req)
+		})
+	}
+}
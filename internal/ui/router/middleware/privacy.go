@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/pagient/pagient-server/internal/ui/renderer"
+	"github.com/pagient/pagient-server/internal/ui/router/context"
+)
+
+// Privacy middleware masks social security numbers in responses to requesters not allowed to see them,
+// it requires the user to be loaded into the request context
+func Privacy(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !context.RevealSocialSecurityNo(req) {
+			req = renderer.WithMaskedSocialSecurityNo(req)
+		}
+
+		next.ServeHTTP(w, req)
+	})
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pagient/pagient-server/internal/tracing"
+
+	"github.com/go-chi/chi"
+	chiMiddleware "github.com/go-chi/chi/middleware"
+	"go.opencensus.io/plugin/ochttp/propagation/tracecontext"
+	"go.opencensus.io/trace"
+)
+
+// traceFormat reads the trace of callers from the w3c trace context headers
+var traceFormat = &tracecontext.HTTPFormat{}
+
+// Tracing middleware starts a span per request continuing the trace of the caller if any,
+// services scoped to the request trace their operations as children of it
+func Tracing(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		attrs := []trace.Attribute{
+			trace.StringAttribute("http.method", req.Method),
+			trace.StringAttribute("http.target", req.URL.Path),
+		}
+
+		var ctx context.Context
+		var span *trace.Span
+		if parent, ok := traceFormat.SpanContextFromRequest(req); ok {
+			ctx, span = tracing.StartRemote(req.Context(), req.Method, parent, attrs...)
+		} else {
+			ctx, span = tracing.Start(req.Context(), req.Method, attrs...)
+		}
+		defer span.End()
+
+		ww := chiMiddleware.NewWrapResponseWriter(w, req.ProtoMajor)
+
+		next.ServeHTTP(ww, req.WithContext(ctx))
+
+		// the route is known after the request has been routed
+		if route := chi.RouteContext(req.Context()).RoutePattern(); route != "" {
+			span.AddAttributes(trace.StringAttribute("http.route", route))
+		}
+
+		// handlers not writing a header respond with ok
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		span.AddAttributes(trace.Int64Attribute("http.status_code", int64(status)))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(trace.Status{Code: trace.StatusCodeInternal, Message: http.StatusText(status)})
+		}
+	})
+}
@@ -5,6 +5,12 @@ import (
 	"time"
 
 	"github.com/pagient/pagient-server/internal/config"
+	"github.com/pagient/pagient-server/internal/directory"
+	"github.com/pagient/pagient-server/internal/health"
+	"github.com/pagient/pagient-server/internal/limiter"
+	"github.com/pagient/pagient-server/internal/metrics"
+	"github.com/pagient/pagient-server/internal/model"
+	"github.com/pagient/pagient-server/internal/notifier"
 	"github.com/pagient/pagient-server/internal/service"
 	"github.com/pagient/pagient-server/internal/ui/handler"
 	"github.com/pagient/pagient-server/internal/ui/router/context"
@@ -21,14 +27,14 @@ import (
 )
 
 // Load initializes the routing of the application.
-func Load(s service.Service, wsHub *websocket.Hub) http.Handler {
+func Load(s service.Service, wsHub *websocket.Hub, events *notifier.SSE, checker *health.Checker) http.Handler {
 	mux := chi.NewRouter()
 
 	mux.Use(hlog.NewHandler(log.Logger))
 	mux.Use(hlog.AccessHandler(func(r *http.Request, status, size int, duration time.Duration) {
 		hlog.FromRequest(r).Debug().
 			Str("method", r.Method).
-			Str("url", r.URL.String()).
+			Str("url", middleware.RedactedURL(r)).
 			Int("status", status).
 			Int("size", size).
 			Dur("duration", duration).
@@ -36,8 +42,11 @@ func Load(s service.Service, wsHub *websocket.Hub) http.Handler {
 	}))
 	mux.Use(hlog.RemoteAddrHandler("ip"))
 	mux.Use(hlog.RequestIDHandler("request_id", "Request-Id"))
+	mux.Use(middleware.Tracing)
+	mux.Use(middleware.Metrics)
 
 	mux.Use(chiMiddleware.RequestID)
+	mux.Use(context.PeerCtx)
 	mux.Use(chiMiddleware.RealIP)
 	mux.Use(chiMiddleware.Recoverer)
 	mux.Use(chiMiddleware.Timeout(60 * time.Second))
@@ -48,49 +57,137 @@ func Load(s service.Service, wsHub *websocket.Hub) http.Handler {
 	mux.Use(middleware.Options())
 
 	tokenAuth := jwtauth.New("HS256", []byte(config.General.Secret), nil)
+	loginLimiter := limiter.New(config.Auth.LoginRateLimit, config.Auth.LoginRateWindow)
 
 	mux.Route("/", func(root chi.Router) {
 		root.Group(func(r chi.Router) {
 			r.Use(jwtauth.Verifier(tokenAuth))
-			r.Use(middleware.Authenticator(s))
+			r.Use(middleware.Authenticator(s, s))
 
 			r.Route("/api", func(r chi.Router) {
 				r.Use(render.SetContentType(render.ContentTypeJSON))
 				r.Use(context.AuthCtx(s))
+				r.Use(middleware.Privacy)
 
-				// Manage patients
+				// Manage patients, displays only get the called visits of /api/display
 				r.Route("/patients", func(r chi.Router) {
-					r.Get("/", handler.GetPatients(s))
-					r.With(context.ClientCtx(s)).Post("/", handler.AddPatient(s))
+					r.With(middleware.Scope(model.APIKeyScopeReadPatients), middleware.Deny(model.UserRoleDisplay)).Get("/", handler.GetPatients(s))
+					r.With(middleware.Scope(model.APIKeyScopeCreatePatients), middleware.Deny(model.UserRoleDisplay), context.ClientCtx(s)).Post("/", handler.AddPatient(s))
 
 					r.Route("/{patientID}", func(r chi.Router) {
 						r.Use(context.PatientCtx(s))
 
-						r.Get("/", handler.GetPatient())
-						r.With(context.ClientCtx(s)).Post("/", handler.UpdatePatient(s))
-						r.Delete("/", handler.DeletePatient(s))
+						r.With(middleware.Scope(model.APIKeyScopeReadPatients), middleware.Deny(model.UserRoleDisplay)).Get("/", handler.GetPatient())
+						r.With(middleware.Scope(), middleware.Deny(model.UserRoleDisplay)).Post("/", handler.UpdatePatient(s))
+						r.With(middleware.Scope(), middleware.Deny(model.UserRoleDisplay)).Delete("/", handler.DeletePatient(s))
 					})
 				})
 
-				// List pagers
-				r.Get("/pagers", handler.GetPagers(s))
+				// Manage visits, displays only get the called visits of /api/display
+				r.Route("/visits", func(r chi.Router) {
+					r.With(middleware.Scope(model.APIKeyScopeReadPatients), middleware.Deny(model.UserRoleDisplay)).Get("/", handler.GetVisits(s))
+					r.With(middleware.Scope(model.APIKeyScopeCreatePatients), middleware.Deny(model.UserRoleDisplay), context.ClientCtx(s)).Post("/", handler.AddVisit(s))
+					r.With(middleware.Scope(model.APIKeyScopeReadPatients)).Get("/wait-time", handler.GetWaitTime(s))
+
+					r.Route("/{visitID}", func(r chi.Router) {
+						r.Use(context.VisitCtx(s))
+
+						r.With(middleware.Scope(model.APIKeyScopeReadPatients), middleware.Deny(model.UserRoleDisplay)).Get("/", handler.GetVisit(s))
+						r.With(middleware.Scope(), middleware.Deny(model.UserRoleDisplay), context.ClientCtx(s)).Post("/", handler.UpdateVisit(s))
+						r.With(middleware.Scope(), middleware.Deny(model.UserRoleDisplay)).Delete("/", handler.CloseVisit(s))
+						r.With(middleware.Scope(model.APIKeyScopeCallPagers), middleware.Deny(model.UserRoleDisplay)).Post("/call", handler.CallVisit(s))
+					})
+				})
+
+				// List pagers, displays show their names
+				r.With(middleware.Scope(model.APIKeyScopeCallPagers, model.APIKeyScopeDisplay)).Get("/pagers", handler.GetPagers(s))
+				// List called visits for waiting-room displays
+				r.With(middleware.Scope(model.APIKeyScopeDisplay)).Get("/display", handler.GetDisplay(s))
 				// List clients
-				r.Get("/clients", handler.GetClients(s))
+				r.With(middleware.Scope(model.APIKeyScopeReadPatients), middleware.Deny(model.UserRoleDisplay)).Get("/clients", handler.GetClients(s))
+
+				// Manage own api keys, displays get theirs from admins only
+				r.Route("/keys", func(r chi.Router) {
+					r.Use(middleware.Scope(), middleware.Deny(model.UserRoleDisplay))
+
+					r.Get("/", handler.GetAPIKeys(s))
+					r.Post("/", handler.AddAPIKey(s))
+					r.With(context.APIKeyCtx(s)).Delete("/{keyID}", handler.DeleteAPIKey(s, wsHub))
+				})
+
+				// List failed logins
+				r.With(middleware.Authorizer(model.UserRoleAdmin)).Get("/login-attempts", handler.GetLoginAttempts(s))
+
+				// List audit log
+				r.With(middleware.Authorizer(model.UserRoleAdmin)).Get("/audit", handler.GetAuditEntries(s))
+
+				// Import and export clients, pagers and users
+				r.Route("/inventory", func(r chi.Router) {
+					r.Use(middleware.Authorizer(model.UserRoleAdmin))
+
+					r.Get("/", handler.ExportInventory(s))
+					r.Post("/", handler.ImportInventory(s))
+				})
+
+				// Report wait times and pager usage
+				r.With(middleware.Authorizer(model.UserRoleAdmin)).Get("/reports/visits", handler.GetVisitReport(s))
+
+				// Manage sessions of other users
+				r.Route("/users/{username}/sessions", func(r chi.Router) {
+					r.Use(middleware.Authorizer(model.UserRoleAdmin))
+					r.Use(context.AccountCtx(s))
+
+					r.Get("/", handler.GetSessions(s))
+					r.Delete("/", handler.DeleteSessions(s, wsHub))
+					r.With(context.SessionCtx(s)).Delete("/{sessionID}", handler.DeleteSession(s, wsHub))
+				})
 			})
 
-			// Serve Websocket
-			r.Get("/ws", handler.ServeWebsocket(s, wsHub))
+			// Serve Websocket, displays connect to /ws/display
+			r.With(middleware.Scope(), context.AuthCtx(s), middleware.Deny(model.UserRoleDisplay)).Get("/ws", handler.ServeWebsocket(s, wsHub))
+			r.With(middleware.Scope(model.APIKeyScopeDisplay)).Get("/ws/display", handler.ServeDisplayWebsocket(s, wsHub))
+
+			// Stream server-sent events
+			r.With(middleware.Scope(model.APIKeyScopeReadPatients), context.AuthCtx(s), middleware.Deny(model.UserRoleDisplay)).Get("/events", handler.ServeEvents(events))
 		})
 
+		// Probe liveness and readiness, e.g. by monitoring or the service manager
+		root.Get("/healthz", handler.GetHealth())
+		root.Get("/readyz", handler.GetReadiness(checker))
+
+		// Expose metrics to prometheus
+		if config.Metrics.Enabled {
+			root.With(middleware.MetricsToken(config.Metrics.Token)).Get("/metrics", metrics.Handler().ServeHTTP)
+		}
+
 		root.Route("/oauth", func(r chi.Router) {
-			r.Post("/token", handler.CreateToken(s, s))
+			r.Post("/token", handler.CreateToken(s, s, loginLimiter))
+			r.With(render.SetContentType(render.ContentTypeJSON)).Get("/providers", handler.GetProviders())
+
+			// Single sign-on
+			if config.OIDC.Enabled {
+				provider := directory.NewOIDC()
+
+				r.Get("/login", handler.OIDCLogin(provider))
+				r.Get("/callback", handler.OIDCCallback(provider, s, s))
+			}
 
 			r.Route("/", func(r chi.Router) {
 				r.Use(jwtauth.Verifier(tokenAuth))
-				r.Use(middleware.Authenticator(s))
+				r.Use(middleware.Authenticator(s, s))
+				r.Use(middleware.Scope())
 
 				r.Delete("/token", handler.DeleteToken(s, wsHub))
-				r.Get("/sessions", handler.GetSessions(s, s))
+
+				// Manage own sessions
+				r.Route("/sessions", func(r chi.Router) {
+					r.Use(render.SetContentType(render.ContentTypeJSON))
+					r.Use(context.AuthCtx(s))
+
+					r.Get("/", handler.GetSessions(s))
+					r.Delete("/", handler.DeleteSessions(s, wsHub))
+					r.With(context.SessionCtx(s)).Delete("/{sessionID}", handler.DeleteSession(s, wsHub))
+				})
 			})
 		})
 
@@ -27,7 +27,14 @@ var (
 
 // Client is a middleman between the websocket connection and the hub.
 type Client struct {
-	id uint
+	// session of the token the client connected with
+	session string
+
+	// masked clients don't receive full social security numbers
+	masked bool
+
+	// display clients only receive pseudonymous entries of called visits
+	display bool
 
 	hub *Hub
 
@@ -39,12 +46,25 @@ type Client struct {
 }
 
 // NewClient initializes a websocket Client
-func NewClient(id uint, hub *Hub, conn *ws.Conn) *Client {
+func NewClient(session string, masked bool, hub *Hub, conn *ws.Conn) *Client {
+	return &Client{
+		session: session,
+		masked:  masked,
+		hub:     hub,
+		conn:    conn,
+		send:    make(chan *Message, 256),
+	}
+}
+
+// NewDisplayClient initializes a websocket Client of a waiting-room display
+func NewDisplayClient(session string, hub *Hub, conn *ws.Conn) *Client {
 	return &Client{
-		id:   id,
-		hub:  hub,
-		conn: conn,
-		send: make(chan *Message, 256),
+		session: session,
+		masked:  true,
+		display: true,
+		hub:     hub,
+		conn:    conn,
+		send:    make(chan *Message, 256),
 	}
 }
 
@@ -5,8 +5,13 @@
 package websocket
 
 import (
+	"sync/atomic"
+
+	"github.com/pagient/pagient-server/internal/metrics"
 	"github.com/pagient/pagient-server/internal/model"
 	"github.com/pagient/pagient-server/internal/ui/renderer"
+
+	"github.com/rs/zerolog/log"
 )
 
 // Hub maintains the set of active clients and broadcasts messages to the
@@ -18,8 +23,11 @@ type Hub struct {
 	// Inbound messages from the clients.
 	transmit chan *Message
 
-	// stop running hub
-	stop chan struct{}
+	// Count of messages dropped because of a full transmit queue.
+	dropped uint64
+
+	// Sessions whose clients get disconnected.
+	disconnect chan string
 
 	// Register requests from the clients.
 	Register chan *Client
@@ -28,13 +36,14 @@ type Hub struct {
 	Unregister chan *Client
 }
 
-// NewHub creates and returns a new websocket hub
-func NewHub() *Hub {
+// NewHub creates and returns a new websocket hub with a transmit queue of given size
+func NewHub(size int) *Hub {
 	return &Hub{
 		Register:   make(chan *Client),
 		Unregister: make(chan *Client),
 		clients:    make(map[*Client]bool),
-		transmit:   make(chan *Message),
+		transmit:   make(chan *Message, size),
+		disconnect: make(chan string),
 	}
 }
 
@@ -45,18 +54,34 @@ func (h *Hub) Run(stop <-chan struct{}) {
 			select {
 			case client := <-h.Register:
 				h.clients[client] = true
+				metrics.WebsocketConnections.Inc()
 			case client := <-h.Unregister:
 				if _, ok := h.clients[client]; ok {
-					delete(h.clients, client)
-					close(client.send)
+					h.remove(client)
+				}
+			case session := <-h.disconnect:
+				for client := range h.clients {
+					if client.session == session {
+						h.remove(client)
+					}
 				}
 			case message := <-h.transmit:
+				masked, display := message.masked(), message.display()
 				for client := range h.clients {
+					msg := message
+					if client.display {
+						if display == nil {
+							continue
+						}
+						msg = display
+					} else if client.masked {
+						msg = masked
+					}
+
 					select {
-					case client.send <- message:
+					case client.send <- msg:
 					default:
-						close(client.send)
-						delete(h.clients, client)
+						h.remove(client)
 					}
 				}
 			case <-stop:
@@ -66,36 +91,54 @@ func (h *Hub) Run(stop <-chan struct{}) {
 	}()
 }
 
-// broadcast creates a message and adds it to the broadcast channel
+// remove unregisters the client and closes it's send channel, so the connection gets closed
+func (h *Hub) remove(client *Client) {
+	delete(h.clients, client)
+	close(client.send)
+	metrics.WebsocketConnections.Dec()
+}
+
+// broadcast creates a message and adds it to the broadcast channel,
+// the message is dropped if the channel is full, so a stalled hub never blocks the sender
 func (h *Hub) broadcast(msgType MessageType, data interface{}) {
 	msg := &Message{
 		Type: msgType,
 		Data: data,
 	}
 
-	h.transmit <- msg
+	select {
+	case h.transmit <- msg:
+	default:
+		atomic.AddUint64(&h.dropped, 1)
+		metrics.WebsocketDroppedMessages.Inc()
+
+		log.Warn().
+			Str("type", string(msgType)).
+			Msg("websocket transmit queue full, message dropped")
+	}
+}
+
+// Dropped returns the count of messages dropped because of a full transmit queue
+func (h *Hub) Dropped() uint64 {
+	return atomic.LoadUint64(&h.dropped)
 }
 
-// NotifyNewPatient broadcasts a notification about a new patient
-func (h *Hub) NotifyNewPatient(patient *model.Patient) {
-	h.broadcast(MessageTypePatientAdd, renderer.NewPatientResponse(patient))
+// NotifyNewVisit broadcasts a notification about a new visit
+func (h *Hub) NotifyNewVisit(visit *model.Visit) {
+	h.broadcast(MessageTypeVisitAdd, renderer.NewVisitResponse(visit))
 }
 
-// NotifyUpdatedPatient broadcasts a notification about an updated patient
-func (h *Hub) NotifyUpdatedPatient(patient *model.Patient) {
-	h.broadcast(MessageTypePatientUpdate, renderer.NewPatientResponse(patient))
+// NotifyUpdatedVisit broadcasts a notification about an updated visit
+func (h *Hub) NotifyUpdatedVisit(visit *model.Visit) {
+	h.broadcast(MessageTypeVisitUpdate, renderer.NewVisitResponse(visit))
 }
 
-// NotifyDeletedPatient broadcasts a notification about a deleted patient
-func (h *Hub) NotifyDeletedPatient(patient *model.Patient) {
-	h.broadcast(MessageTypePatientDelete, renderer.NewPatientResponse(patient))
+// NotifyDeletedVisit broadcasts a notification about a deleted visit
+func (h *Hub) NotifyDeletedVisit(visit *model.Visit) {
+	h.broadcast(MessageTypeVisitDelete, renderer.NewVisitResponse(visit))
 }
 
-// DisconnectClient disconnects a client by token signature
-func (h *Hub) DisconnectClient(id uint) {
-	for client := range h.clients {
-		if client.id == id {
-			h.Unregister <- client
-		}
-	}
+// DisconnectClient disconnects all clients connected with a token of the session
+func (h *Hub) DisconnectClient(session string) {
+	h.disconnect <- session
 }
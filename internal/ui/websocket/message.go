@@ -1,15 +1,23 @@
 package websocket
 
+import (
+	"github.com/pagient/pagient-server/internal/ui/renderer"
+)
+
 // MessageType is the type of a websocket message
 type MessageType string
 
 const (
-	// MessageTypePatientAdd marks a message that originates from a patient add operation
-	MessageTypePatientAdd MessageType = "patient_add"
-	// MessageTypePatientUpdate marks a message that originates from a patient update operation
-	MessageTypePatientUpdate MessageType = "patient_update"
-	// MessageTypePatientDelete marks a message that originates from a patient delete operation
-	MessageTypePatientDelete MessageType = "patient_delete"
+	// MessageTypeVisitAdd marks a message that originates from a visit add operation
+	MessageTypeVisitAdd MessageType = "visit_add"
+	// MessageTypeVisitUpdate marks a message that originates from a visit update operation
+	MessageTypeVisitUpdate MessageType = "visit_update"
+	// MessageTypeVisitDelete marks a message that originates from a visit delete operation
+	MessageTypeVisitDelete MessageType = "visit_delete"
+	// MessageTypeDisplayUpdate marks a message that shows or updates a called visit on waiting-room displays
+	MessageTypeDisplayUpdate MessageType = "display_update"
+	// MessageTypeDisplayDelete marks a message that removes a visit from waiting-room displays
+	MessageTypeDisplayDelete MessageType = "display_delete"
 )
 
 // Message struct
@@ -17,3 +25,37 @@ type Message struct {
 	Type MessageType `json:"type"`
 	Data interface{} `json:"data"`
 }
+
+// masked returns a copy of the message without full social security numbers
+func (m *Message) masked() *Message {
+	visit, ok := m.Data.(*renderer.VisitResponse)
+	if !ok {
+		return m
+	}
+
+	return &Message{
+		Type: m.Type,
+		Data: visit.Masked(),
+	}
+}
+
+// display returns the message for waiting-room displays, which only contains pseudonymous data of called visits
+func (m *Message) display() *Message {
+	visit, ok := m.Data.(*renderer.VisitResponse)
+	if !ok {
+		return nil
+	}
+
+	entry, displayed := visit.Display()
+	if m.Type == MessageTypeVisitDelete || !displayed {
+		return &Message{
+			Type: MessageTypeDisplayDelete,
+			Data: entry,
+		}
+	}
+
+	return &Message{
+		Type: MessageTypeDisplayUpdate,
+		Data: entry,
+	}
+}
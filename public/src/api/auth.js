@@ -1,15 +1,29 @@
 import axios from "axios";
 
-const base =
+const root =
   process.env.NODE_ENV === "production"
-    ? "/oauth/token"
-    : `${process.env.VUE_APP_API_ROOT}/oauth/token`;
+    ? "/oauth"
+    : `${process.env.VUE_APP_API_ROOT}/oauth`;
+const base = `${root}/token`;
+
+// url to start the single sign-on at
+export const ssoURL = `${root}/login`;
 
 export function login(credentials) {
   // credentials have to be an object { username, password }
   return axios.post(base, credentials);
 }
 
+export function refresh() {
+  // the refresh token is sent as http only cookie
+  return axios.post(base, { grant_type: "refresh_token" });
+}
+
 export function logout() {
   return axios.delete(base);
 }
+
+export function getProviders() {
+  // lists the login methods besides username and password
+  return axios.get(`${root}/providers`);
+}
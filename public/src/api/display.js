@@ -0,0 +1,19 @@
+import axios from "axios";
+
+const base =
+  process.env.NODE_ENV === "production"
+    ? "/api"
+    : `${process.env.VUE_APP_API_ROOT}/api`;
+
+// displays without a logged in user authenticate by a display scoped api key
+function keyHeaders(key) {
+  return key ? { headers: { "X-API-Key": key } } : {};
+}
+
+export function getDisplay(key) {
+  return axios.get(base + "/display", keyHeaders(key));
+}
+
+export function getDisplayPagers(key) {
+  return axios.get(base + "/pagers", keyHeaders(key));
+}
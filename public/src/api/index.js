@@ -1,4 +1,6 @@
 export * from "./auth";
 export * from "./client";
+export * from "./display";
 export * from "./pager";
 export * from "./patient";
+export * from "./visit";
@@ -0,0 +1,34 @@
+import axios from "axios";
+
+const base =
+  process.env.NODE_ENV === "production"
+    ? "/api/visits"
+    : `${process.env.VUE_APP_API_ROOT}/api/visits`;
+
+export function getAllVisits() {
+  return axios.get(base);
+}
+
+export function getVisit(id) {
+  return axios.get(base + "/" + id);
+}
+
+export function addVisit(visit) {
+  return axios.post(base, visit);
+}
+
+export function updateVisit(visit) {
+  return axios.post(base + "/" + visit.id, visit);
+}
+
+export function closeVisit(visit) {
+  return axios.delete(base + "/" + visit.id);
+}
+
+export function callVisit(visit) {
+  return axios.post(base + "/" + visit.id + "/call");
+}
+
+export function getWaitTime() {
+  return axios.get(base + "/wait-time");
+}
@@ -1,6 +1,7 @@
 import axios from "axios";
 import store from "@/store";
 import router from "@/plugins/vue-router";
+import { refresh } from "@/api/auth";
 
 axios.interceptors.request.use(
   config => {
@@ -16,6 +17,26 @@ axios.interceptors.request.use(
 axios.interceptors.response.use(
   response => response,
   error => {
+    const request = error.config;
+
+    // try to refresh the session once before logging out
+    if (
+      error.response &&
+      error.response.status === 401 &&
+      !request.retried &&
+      !request.url.endsWith("/oauth/token")
+    ) {
+      request.retried = true;
+
+      return refresh().then(
+        response => {
+          store.commit("login", response.data.token);
+          return axios(request);
+        },
+        () => Promise.reject(error)
+      );
+    }
+
     if (
       error.response &&
       error.response.status === 401 &&
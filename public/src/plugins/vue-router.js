@@ -2,6 +2,7 @@ import Vue from "vue";
 import VueRouter from "vue-router";
 import store from "@/store";
 
+import DisplayComponent from "@/components/Display";
 import HomeComponent from "@/components/Home";
 import LoginComponent from "@/components/Login";
 import LogoutComponent from "@/components/Logout";
@@ -11,6 +12,7 @@ Vue.use(VueRouter);
 
 const routes = [
   { path: "/", component: HomeComponent, meta: { requiresAuth: true } },
+  { path: "/display", component: DisplayComponent },
   { path: "/login", component: LoginComponent, meta: { guestOnly: true } },
   { path: "/logout", component: LogoutComponent, meta: { requiresAuth: true } },
   { path: "*", component: NotFoundComponent }
@@ -7,6 +7,12 @@ export const login = ({ commit }, credentials) => {
   });
 };
 
+export const refresh = ({ commit }) => {
+  return api.refresh().then(response => {
+    commit("login", response.data.token);
+  });
+};
+
 export const logout = ({ commit }) => {
   return api.logout().then(() => {
     commit("logout");
@@ -25,29 +31,32 @@ export const getAllPagers = ({ commit }) => {
   });
 };
 
-export const getAllPatients = ({ commit }) => {
-  return api.getAllPatients().then(response => {
-    commit("receivePatients", response.data);
+export const getAllVisits = ({ commit }) => {
+  return api.getAllVisits().then(response => {
+    commit("receiveVisits", response.data);
+  });
+};
+
+export const getWaitTime = ({ commit }) => {
+  return api.getWaitTime().then(response => {
+    commit("receiveWaitTime", response.data);
   });
 };
 
-export const callPatient = (_, patient) => {
-  if (!patient) return;
-  // Copy patient to prevent direct state mutation
-  patient = clone(patient);
-  patient.status = "call";
-  return api.updatePatient(patient);
+export const callVisit = (_, visit) => {
+  if (!visit) return;
+  return api.callVisit(visit);
 };
 
-export const assignPager = (_, { patient, pager }) => {
-  if (!patient) return;
-  // Copy patient to prevent direct state mutation
-  patient = clone(patient);
-  patient.status = "pending";
-  patient.pagerId = pager ? pager.id : null;
-  return api.updatePatient(patient).then(() => {
-    if (!patient.active && !patient.pagerId) {
-      return api.deletePatient(patient);
+export const assignPager = (_, { visit, pager }) => {
+  if (!visit) return;
+  // Copy visit to prevent direct state mutation
+  visit = clone(visit);
+  visit.status = "pending";
+  visit.pagerId = pager ? pager.id : null;
+  return api.updateVisit(visit).then(() => {
+    if (!visit.active && !visit.pagerId) {
+      return api.closeVisit(visit);
     }
   });
 };
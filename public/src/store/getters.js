@@ -3,11 +3,11 @@ export const authToken = state => state.authToken;
 
 export const clients = (state, getters) => {
   const clientIds = Object.keys(state.clients);
-  // map to array and load patients
+  // map to array and load visits
   if (clientIds.length > 0) {
     return clientIds.map(id => {
       const client = state.clients[id];
-      client.patient = getters.patientByClient(client);
+      client.visit = getters.visitByClient(client);
       return client;
     });
   }
@@ -16,22 +16,22 @@ export const clients = (state, getters) => {
 
 export const pagers = (state, getters) => {
   const pagerIds = Object.keys(state.pagers);
-  // map to array and load patients
+  // map to array and load visits
   if (pagerIds.length > 0) {
     return pagerIds.map(id => {
       const pager = state.pagers[id];
-      pager.patient = getters.patientByPager(pager);
+      pager.visit = getters.visitByPager(pager);
       return pager;
     });
   }
   return [];
 };
 
-export const patients = state => {
-  const patientIds = Object.keys(state.patients);
+export const visits = state => {
+  const visitIds = Object.keys(state.visits);
   // map to array
-  if (patientIds.length > 0) {
-    return patientIds.map(id => state.patients[id]);
+  if (visitIds.length > 0) {
+    return visitIds.map(id => state.visits[id]);
   }
   return [];
 };
@@ -40,17 +40,41 @@ export const activeClient = state => {
   return state.activeClientId ? state.clients[state.activeClientId] : {};
 };
 
-export const activePatient = (state, getters) => {
+export const activeVisit = (state, getters) => {
   const client = activeClient(state);
-  return getters.patientByClient(client);
+  return getters.visitByClient(client);
 };
 
-export const patientByClient = (_, getters) => client => {
-  return getters.patients.find(
-    patient => patient.clientId === client.id && patient.active
+export const visitByClient = (_, getters) => client => {
+  return getters.visits.find(
+    visit => visit.clientId === client.id && visit.active
   );
 };
 
-export const patientByPager = (_, getters) => pager => {
-  return getters.patients.find(patient => patient.pagerId === pager.id);
+// waitTime returns the minutes a patient has waited until the first call
+export const waitTime = state => visit => {
+  const end = visit.firstCalledAt
+    ? Date.parse(visit.firstCalledAt)
+    : state.now;
+  return Math.max(0, Math.floor((end - Date.parse(visit.checkedInAt)) / 60000));
+};
+
+// estimatedWaitTime returns the minutes a patient likely waits until the first call
+export const estimatedWaitTime = (state, getters) => visit => {
+  if (visit.firstCalledAt || visit.status === "finished") return null;
+  // predicted from the queue position of the patient
+  if (visit.estimatedCallAt) {
+    return Math.max(
+      0,
+      Math.round((Date.parse(visit.estimatedCallAt) - state.now) / 60000)
+    );
+  }
+  return Math.max(
+    0,
+    Math.round(state.averageWaitTime / 60) - getters.waitTime(visit)
+  );
+};
+
+export const visitByPager = (_, getters) => pager => {
+  return getters.visits.find(visit => visit.pagerId === pager.id);
 };
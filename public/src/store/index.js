@@ -8,6 +8,10 @@ const state = {
   isLoggedIn: !!localStorage.getItem("token"),
   authToken: localStorage.getItem("token"),
   activeClientId: localStorage.getItem("activeClient"),
+  // average wait time in seconds of recently called patients
+  averageWaitTime: 0,
+  // updated regularly to show live wait times
+  now: Date.now(),
   clients: {
     /*
     id: {
@@ -24,16 +28,24 @@ const state = {
     }
     */
   },
-  patients: {
+  visits: {
     /*
     id: {
       id,
-      name,
-      ssn,
-      clientID,
-      pagerID,
+      patient: {
+        id,
+        name,
+        ssn
+      },
+      clientId,
+      pagerId,
+      room,
       status,
-      active
+      active,
+      checkedInAt,
+      firstCalledAt,
+      queuePosition,
+      estimatedCallAt
     }
     */
   }
@@ -25,32 +25,38 @@ export default {
       }
     });
   },
-  receivePatients(state, patients) {
-    patients.forEach(patient => {
-      if (!state.patients[patient.id]) {
-        createPatient(state, patient.id, patient);
+  receiveVisits(state, visits) {
+    visits.forEach(visit => {
+      if (!state.visits[visit.id]) {
+        createVisit(state, visit.id, visit);
       }
     });
   },
-  receivePatient(state, patient) {
-    const patientBeforeUpdate = state.patients[patient.id];
-    createPatient(state, patient.id, patient);
+  receiveVisit(state, visit) {
+    const visitBeforeUpdate = state.visits[visit.id];
+    createVisit(state, visit.id, visit);
 
     // changes originate from pagient-cli
     if (
-      !patientBeforeUpdate ||
-      patientBeforeUpdate.ssn !== patient.ssn ||
-      patientBeforeUpdate.name !== patient.name ||
-      patientBeforeUpdate.clientId !== patient.clientId
+      !visitBeforeUpdate ||
+      visitBeforeUpdate.patient.ssn !== visit.patient.ssn ||
+      visitBeforeUpdate.patient.name !== visit.patient.name ||
+      visitBeforeUpdate.clientId !== visit.clientId
     ) {
-      setActiveClient(state, patient.clientId);
+      setActiveClient(state, visit.clientId);
     }
   },
-  deletePatient(state, patient) {
-    const patients = state.patients;
-    delete patients[patient.id];
+  deleteVisit(state, visit) {
+    const visits = state.visits;
+    delete visits[visit.id];
 
-    state.patients = { ...patients };
+    state.visits = { ...visits };
+  },
+  receiveWaitTime(state, waitTime) {
+    state.averageWaitTime = waitTime.averageWaitTime;
+  },
+  tick(state) {
+    state.now = Date.now();
   },
   switchClient(state, client) {
     localStorage.setItem("activeClient", client.id);
@@ -72,8 +78,8 @@ function createPager(state, id, name) {
   });
 }
 
-function createPatient(state, id, patient) {
-  Vue.set(state.patients, id, patient);
+function createVisit(state, id, visit) {
+  Vue.set(state.visits, id, visit);
 }
 
 function setActiveClient(state, id) {
@@ -1,20 +1,31 @@
+// refreshInterval in milliseconds of live wait times
+const refreshInterval = 30000;
+
 export default function createWebSocketPlugin(socket) {
   return store => {
+    setInterval(() => {
+      store.commit("tick");
+      if (store.getters.isLoggedIn) {
+        store.dispatch("getWaitTime");
+      }
+    }, refreshInterval);
+
     socket.addEventListener("open", () => {
       store
         .dispatch("getAllClients")
         .then(() => store.dispatch("getAllPagers"))
-        .then(() => store.dispatch("getAllPatients"))
+        .then(() => store.dispatch("getAllVisits"))
+        .then(() => store.dispatch("getWaitTime"))
         .then(() => {
           socket.addEventListener("message", ({ data }) => {
             const message = JSON.parse(data);
             switch (message.type) {
-              case "patient_add":
-              case "patient_update":
-                store.commit("receivePatient", message.data);
+              case "visit_add":
+              case "visit_update":
+                store.commit("receiveVisit", message.data);
                 break;
-              case "patient_delete":
-                store.commit("deletePatient", message.data);
+              case "visit_delete":
+                store.commit("deleteVisit", message.data);
                 break;
             }
           });